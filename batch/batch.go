@@ -0,0 +1,196 @@
+// batch/batch.go
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// Location 是对象存储中的一个位置。
+type Location struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"` // 对象键；作为目录前缀使用时通常以 "/" 结尾
+}
+
+// Storage 是批量任务读写输入、输出文件所用的对象存储，例如火山引擎 TOS。
+// SDK 不直接依赖任何对象存储的 SDK，也不内置任何实现，由调用方按需实现。
+type Storage interface {
+	// Put 将 r 的内容写入 loc。
+	Put(ctx context.Context, loc Location, r io.Reader) error
+	// Get 读取 loc 的内容，调用方负责关闭返回的 ReadCloser。
+	Get(ctx context.Context, loc Location) (io.ReadCloser, error)
+	// List 列出 prefix 下的全部对象。
+	List(ctx context.Context, prefix Location) ([]Location, error)
+}
+
+// InputLine 是平台批量任务输入文件中的一行。
+type InputLine struct {
+	CustomID string      `json:"custom_id"`
+	Body     interface{} `json:"body"` // 平台特定的请求体
+}
+
+// OutputLine 是平台批量任务输出文件中的一行。
+type OutputLine struct {
+	ID       string          `json:"id,omitempty"`
+	CustomID string          `json:"custom_id"`
+	Response *OutputResponse `json:"response,omitempty"`
+	Error    *OutputError    `json:"error,omitempty"`
+}
+
+// OutputResponse 是单个请求的响应。
+type OutputResponse struct {
+	StatusCode int             `json:"status_code"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Body       json.RawMessage `json:"body"` // 平台特定的响应体
+}
+
+// OutputError 是单个请求的错误。
+type OutputError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// requestLine 是 ReadRequests 与 WriteRequests 使用的行格式，body 为 SDK 的请求结构。
+type requestLine struct {
+	CustomID string          `json:"custom_id,omitempty"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// NormalizeItems 校验批量请求与操作类型是否匹配，并为空的 CustomID 填充请求序号。
+// 返回新的切片，不修改 items。
+func NormalizeItems(op models.BatchOperation, items []models.BatchRequestItem) ([]models.BatchRequestItem, error) {
+	if len(items) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "batch job requires at least one request")
+	}
+	out := make([]models.BatchRequestItem, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		if item.CustomID == "" {
+			item.CustomID = strconv.Itoa(i)
+		}
+		if seen[item.CustomID] {
+			return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("duplicate custom_id %q in batch requests", item.CustomID))
+		}
+		seen[item.CustomID] = true
+
+		switch op {
+		case models.BatchOperationTextGeneration:
+			if item.TextGeneration == nil || item.Embedding != nil {
+				return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("batch request %q must set only a text generation request", item.CustomID))
+			}
+		case models.BatchOperationEmbedding:
+			if item.Embedding == nil || item.TextGeneration != nil {
+				return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("batch request %q must set only an embedding request", item.CustomID))
+			}
+		default:
+			return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("unsupported batch operation %q", op))
+		}
+		out[i] = item
+	}
+	return out, nil
+}
+
+// ReadRequests 从 JSONL 中读取批量请求，每行格式为 {"custom_id": "...", "body": {...}}，
+// body 按 op 解析为 TextGenerationRequest 或 EmbeddingRequest。空行会被跳过。
+func ReadRequests(r io.Reader, op models.BatchOperation) ([]models.BatchRequestItem, error) {
+	var items []models.BatchRequestItem
+	err := readLines(r, func(lineNo int, line []byte) error {
+		var rl requestLine
+		if err := json.Unmarshal(line, &rl); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInvalidRequest, fmt.Sprintf("invalid batch request on line %d", lineNo))
+		}
+		item := models.BatchRequestItem{CustomID: rl.CustomID}
+		var target interface{}
+		switch op {
+		case models.BatchOperationTextGeneration:
+			item.TextGeneration = &models.TextGenerationRequest{}
+			target = item.TextGeneration
+		case models.BatchOperationEmbedding:
+			item.Embedding = &models.EmbeddingRequest{}
+			target = item.Embedding
+		default:
+			return errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("unsupported batch operation %q", op))
+		}
+		if err := json.Unmarshal(rl.Body, target); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInvalidRequest, fmt.Sprintf("invalid batch request body on line %d", lineNo))
+		}
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// WriteRequests 按 ReadRequests 的格式将批量请求写为 JSONL。
+func WriteRequests(w io.Writer, items []models.BatchRequestItem) error {
+	lines := make([]InputLine, 0, len(items))
+	for _, item := range items {
+		line := InputLine{CustomID: item.CustomID}
+		if item.TextGeneration != nil {
+			line.Body = item.TextGeneration
+		} else {
+			line.Body = item.Embedding
+		}
+		lines = append(lines, line)
+	}
+	return WriteInput(w, lines)
+}
+
+// WriteInput 将平台批量任务的输入写为 JSONL。
+func WriteInput(w io.Writer, lines []InputLine) error {
+	enc := json.NewEncoder(w)
+	for _, line := range lines {
+		if err := enc.Encode(line); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, fmt.Sprintf("failed to encode batch input %q", line.CustomID))
+		}
+	}
+	return nil
+}
+
+// ReadOutput 逐行解析平台批量任务的输出 JSONL，对每一行调用 fn。空行会被跳过。
+func ReadOutput(r io.Reader, fn func(line *OutputLine) error) error {
+	return readLines(r, func(lineNo int, data []byte) error {
+		var line OutputLine
+		if err := json.Unmarshal(data, &line); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInvalidResponse, fmt.Sprintf("invalid batch output on line %d", lineNo))
+		}
+		return fn(&line)
+	})
+}
+
+// WriteResults 将批量任务的结果写为 JSONL，每行是一个 models.BatchResult。
+func WriteResults(w io.Writer, results []models.BatchResult) error {
+	enc := json.NewEncoder(w)
+	for _, result := range results {
+		if err := enc.Encode(result); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, fmt.Sprintf("failed to encode batch result %q", result.CustomID))
+		}
+	}
+	return nil
+}
+
+// readLines 逐行读取 r，不限制单行长度，对每个非空行调用 fn。lineNo 从 1 开始。
+func readLines(r io.Reader, fn func(lineNo int, line []byte) error) error {
+	br := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if fnErr := fn(lineNo, line); fnErr != nil {
+				return fnErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "failed to read JSONL")
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// batch/local.go
+package batch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+// LocalJobIDPrefix 是 LocalRunner 创建的任务 ID 的前缀，用于与平台任务区分。
+const LocalJobIDPrefix = "local-batch-"
+
+// DefaultConcurrency 是 LocalRunner 默认的并发请求数。
+const DefaultConcurrency = 8
+
+// DefaultRetention 是 LocalRunner 默认保留已结束任务的时长。
+const DefaultRetention = time.Hour
+
+// Executor 执行批量任务中的单个请求。item 已经过 NormalizeItems 处理，
+// 且在请求未指定模型时已填充任务的模型。
+type Executor func(ctx context.Context, op models.BatchOperation, item models.BatchRequestItem) models.BatchResult
+
+// LocalRunner 在进程内以有限的并发执行批量任务，用于不支持批量推理的平台或操作。
+// 任务状态只保存在内存中，进程退出后丢失；已结束的任务在保留时长 (默认 DefaultRetention) 过后被移除，
+// 也可以通过 DeleteBatchJob 提前移除。
+type LocalRunner struct {
+	exec        Executor
+	concurrency int
+	retention   time.Duration
+
+	mu   sync.Mutex
+	jobs map[string]*localJob
+	seq  atomic.Int64
+}
+
+type localJob struct {
+	job     models.BatchJob
+	results []models.BatchResult
+	cancel  context.CancelFunc
+}
+
+// NewLocalRunner 创建 LocalRunner，concurrency 小于 1 时使用 DefaultConcurrency。
+func NewLocalRunner(exec Executor, concurrency int) *LocalRunner {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	return &LocalRunner{
+		exec:        exec,
+		concurrency: concurrency,
+		retention:   DefaultRetention,
+		jobs:        make(map[string]*localJob),
+	}
+}
+
+// SetRetention 设置已结束任务的保留时长，d 小于等于 0 时任务一直保留到 DeleteBatchJob 被调用。
+func (l *LocalRunner) SetRetention(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.retention = d
+}
+
+var _ platform.BatchHandler = (*LocalRunner)(nil)
+
+// CreateBatchJob 创建任务并立即在后台开始执行。任务不受 ctx 取消的影响，需要通过 CancelBatchJob 取消。
+func (l *LocalRunner) CreateBatchJob(ctx context.Context, req *models.BatchJobRequest) (*models.BatchJob, error) {
+	if req == nil {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "batch job request cannot be nil")
+	}
+	items, err := NormalizeItems(req.Operation, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i] = WithDefaultModel(items[i], req.Model)
+	}
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	lj := &localJob{
+		job: models.BatchJob{
+			ID:            fmt.Sprintf("%s%d-%d", LocalJobIDPrefix, time.Now().UnixNano(), l.seq.Add(1)),
+			Model:         req.Model,
+			Operation:     req.Operation,
+			Status:        models.BatchJobRunning,
+			RequestCounts: models.BatchRequestCount{Total: len(items)},
+			CreatedAt:     time.Now(),
+		},
+		results: make([]models.BatchResult, len(items)),
+		cancel:  cancel,
+	}
+
+	l.mu.Lock()
+	l.evictLocked(time.Now())
+	l.jobs[lj.job.ID] = lj
+	job := lj.job
+	l.mu.Unlock()
+
+	go l.run(jobCtx, lj, req.Operation, items)
+	return &job, nil
+}
+
+// run 以 l.concurrency 的并发执行全部请求，并在结束后更新任务状态。
+func (l *LocalRunner) run(ctx context.Context, lj *localJob, op models.BatchOperation, items []models.BatchRequestItem) {
+	sem := make(chan struct{}, l.concurrency)
+	var wg sync.WaitGroup
+loop:
+	for i, item := range items {
+		select {
+		case <-ctx.Done():
+			break loop
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(i int, item models.BatchRequestItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result := l.exec(ctx, op, item)
+			result.CustomID = item.CustomID
+
+			l.mu.Lock()
+			lj.results[i] = result
+			if result.Error != nil {
+				lj.job.RequestCounts.Failed++
+			} else {
+				lj.job.RequestCounts.Completed++
+			}
+			l.mu.Unlock()
+		}(i, item)
+	}
+	wg.Wait()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// 因取消而未执行的请求也需要结果，便于调用方知道哪些请求需要重新提交。
+	for i, item := range items {
+		if lj.results[i].CustomID == "" {
+			lj.results[i] = models.BatchResult{
+				CustomID: item.CustomID,
+				Error:    &models.BatchError{Code: errors.ErrCodeCancelled, Message: "batch job was cancelled before the request was sent"},
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		lj.job.Status = models.BatchJobCancelled
+	} else {
+		lj.job.Status = models.BatchJobCompleted
+	}
+	lj.job.CompletedAt = time.Now()
+	lj.cancel()
+}
+
+// GetBatchJob 返回任务状态的快照。
+func (l *LocalRunner) GetBatchJob(ctx context.Context, id string) (*models.BatchJob, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lj, err := l.lookupLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	job := lj.job
+	return &job, nil
+}
+
+// CancelBatchJob 取消任务，已在执行的请求会收到 ctx 取消信号，尚未开始的请求不再执行。
+func (l *LocalRunner) CancelBatchJob(ctx context.Context, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lj, err := l.lookupLocked(id)
+	if err != nil {
+		return err
+	}
+	if !lj.job.Status.Terminal() {
+		lj.job.Status = models.BatchJobCancelling
+		lj.cancel()
+	}
+	return nil
+}
+
+// DeleteBatchJob 移除任务及其结果，仍在执行的任务会先被取消。
+func (l *LocalRunner) DeleteBatchJob(ctx context.Context, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lj, err := l.lookupLocked(id)
+	if err != nil {
+		return err
+	}
+	lj.cancel()
+	delete(l.jobs, id)
+	return nil
+}
+
+// lookupLocked 返回未过期的任务，调用方需持有 l.mu。
+func (l *LocalRunner) lookupLocked(id string) (*localJob, error) {
+	l.evictLocked(time.Now())
+	lj, ok := l.jobs[id]
+	if !ok {
+		return nil, errors.New(errors.ErrCodeNotFound, fmt.Sprintf("batch job %s not found", id))
+	}
+	return lj, nil
+}
+
+// evictLocked 移除结束时间早于保留时长的任务，调用方需持有 l.mu。
+func (l *LocalRunner) evictLocked(now time.Time) {
+	if l.retention <= 0 {
+		return
+	}
+	for id, lj := range l.jobs {
+		if lj.job.Status.Terminal() && now.Sub(lj.job.CompletedAt) > l.retention {
+			delete(l.jobs, id)
+		}
+	}
+}
+
+// BatchResults 按请求顺序返回已结束任务的结果。
+func (l *LocalRunner) BatchResults(ctx context.Context, id string) ([]models.BatchResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lj, err := l.lookupLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	if !lj.job.Status.Terminal() {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("batch job %s has not finished (status %s)", id, lj.job.Status))
+	}
+	return append([]models.BatchResult(nil), lj.results...), nil
+}
+
+// WithDefaultModel 在请求未指定模型时使用任务的模型 model，返回的 item 不与原请求共享请求结构体。
+func WithDefaultModel(item models.BatchRequestItem, model string) models.BatchRequestItem {
+	if item.TextGeneration != nil {
+		req := *item.TextGeneration
+		if req.Model == "" {
+			req.Model = model
+		}
+		item.TextGeneration = &req
+	}
+	if item.Embedding != nil {
+		req := *item.Embedding
+		if req.Model == "" {
+			req.Model = model
+		}
+		item.Embedding = &req
+	}
+	return item
+}
@@ -0,0 +1,127 @@
+package batch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+func echoExecutor(ctx context.Context, op models.BatchOperation, item models.BatchRequestItem) models.BatchResult {
+	return models.BatchResult{TextGeneration: &models.TextGenerationResponse{Choices: []models.Choice{{Text: item.TextGeneration.Prompt}}}}
+}
+
+func textItems(prompts ...string) []models.BatchRequestItem {
+	items := make([]models.BatchRequestItem, len(prompts))
+	for i, p := range prompts {
+		items[i] = models.BatchRequestItem{CustomID: p, TextGeneration: &models.TextGenerationRequest{Prompt: p}}
+	}
+	return items
+}
+
+// waitDone 等待任务结束。
+func waitDone(t *testing.T, l *LocalRunner, id string) *models.BatchJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := l.GetBatchJob(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetBatchJob: %v", err)
+		}
+		if job.Status.Terminal() {
+			return job
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+func TestLocalRunner(t *testing.T) {
+	l := NewLocalRunner(echoExecutor, 2)
+	job, err := l.CreateBatchJob(context.Background(), &models.BatchJobRequest{
+		Model:     "m",
+		Operation: models.BatchOperationTextGeneration,
+		Items:     textItems("a", "b", "c"),
+	})
+	if err != nil {
+		t.Fatalf("CreateBatchJob: %v", err)
+	}
+	job = waitDone(t, l, job.ID)
+	if job.Status != models.BatchJobCompleted || job.RequestCounts.Completed != 3 {
+		t.Errorf("job = %+v", job)
+	}
+	results, err := l.BatchResults(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("BatchResults: %v", err)
+	}
+	for i, want := range []string{"a", "b", "c"} {
+		if results[i].CustomID != want || results[i].TextGeneration.Choices[0].Text != want {
+			t.Errorf("result %d = %+v", i, results[i])
+		}
+	}
+}
+
+func TestLocalRunnerRetention(t *testing.T) {
+	l := NewLocalRunner(echoExecutor, 1)
+	l.SetRetention(0)
+	req := &models.BatchJobRequest{Operation: models.BatchOperationTextGeneration, Items: textItems("a")}
+
+	first, err := l.CreateBatchJob(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDone(t, l, first.ID)
+	l.SetRetention(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, err := l.GetBatchJob(context.Background(), first.ID); !errors.IsSDKError(err, errors.ErrCodeNotFound) {
+		t.Errorf("expired job: err = %v, want %s", err, errors.ErrCodeNotFound)
+	}
+	l.mu.Lock()
+	n := len(l.jobs)
+	l.mu.Unlock()
+	if n != 0 {
+		t.Errorf("%d jobs retained after expiry", n)
+	}
+
+	// 保留时长不大于 0 时任务一直保留，直到被显式删除。
+	l.SetRetention(0)
+	second, err := l.CreateBatchJob(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitDone(t, l, second.ID)
+	time.Sleep(5 * time.Millisecond)
+	if _, err := l.BatchResults(context.Background(), second.ID); err != nil {
+		t.Errorf("BatchResults: %v", err)
+	}
+	if err := l.DeleteBatchJob(context.Background(), second.ID); err != nil {
+		t.Fatalf("DeleteBatchJob: %v", err)
+	}
+	if _, err := l.GetBatchJob(context.Background(), second.ID); !errors.IsSDKError(err, errors.ErrCodeNotFound) {
+		t.Errorf("deleted job: err = %v, want %s", err, errors.ErrCodeNotFound)
+	}
+	if err := l.DeleteBatchJob(context.Background(), second.ID); !errors.IsSDKError(err, errors.ErrCodeNotFound) {
+		t.Errorf("second delete: err = %v, want %s", err, errors.ErrCodeNotFound)
+	}
+}
+
+func TestLocalRunnerDeleteRunningJob(t *testing.T) {
+	started := make(chan struct{})
+	l := NewLocalRunner(func(ctx context.Context, op models.BatchOperation, item models.BatchRequestItem) models.BatchResult {
+		close(started)
+		<-ctx.Done()
+		return models.BatchResult{Error: &models.BatchError{Code: errors.ErrCodeCancelled, Message: "cancelled"}}
+	}, 1)
+	job, err := l.CreateBatchJob(context.Background(), &models.BatchJobRequest{Operation: models.BatchOperationTextGeneration, Items: textItems("a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	// 删除仍在执行的任务会取消其请求，否则上面的 Executor 不会返回。
+	if err := l.DeleteBatchJob(context.Background(), job.ID); err != nil {
+		t.Fatalf("DeleteBatchJob: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+// client/batch.go
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/modelbridge/batch"
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+// DefaultBatchPollInterval 是 WaitBatchJob 默认的轮询间隔。
+const DefaultBatchPollInterval = 30 * time.Second
+
+// CreateBatchJob 创建批量任务。平台支持批量推理时提交到平台执行，
+// 否则 (或平台对该任务返回 ErrUnsupportedOperation) 在进程内以 WithBatchConcurrency 的并发执行。
+// 本地任务的 ID 以 batch.LocalJobIDPrefix 开头，仅在当前进程内有效。
+func (c *Client) CreateBatchJob(ctx context.Context, req *models.BatchJobRequest) (*models.BatchJob, error) {
+	if c.handler == nil {
+		err := fmt.Errorf("client not properly initialized or no handler set")
+		c.log.Error("CreateBatchJob failed", "error", err)
+		return nil, err
+	}
+	if req == nil {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "batch job request cannot be nil")
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "CreateBatchJob", resolved.Model)
+	cl.debug("Executing CreateBatchJob", slog.Int("requests", len(req.Items)), slog.String("batch_operation", string(req.Operation)))
+	resolved.Items = make([]models.BatchRequestItem, len(req.Items))
+	for i, item := range req.Items {
+		resolved.Items[i] = c.resolveBatchItemModel(item)
+	}
+
+	if bh, ok := c.handler.(platform.BatchHandler); ok {
+		job, err := bh.CreateBatchJob(ctx, &resolved)
+		if err == nil || !errors.IsSDKError(err, errors.ErrCodeUnsupported) {
+			if err != nil {
+				cl.end(err)
+			} else {
+				cl.end(nil, slog.String("job_id", job.ID), slog.Int("requests", len(req.Items)))
+			}
+			return job, err
+		}
+		cl.log.Warn("Platform batch inference unavailable, running batch job locally", "error", err)
+	}
+	job, err := c.localBatch.CreateBatchJob(ctx, &resolved)
+	if err != nil {
+		cl.end(err, slog.Bool("local", true))
+		return job, err
+	}
+	cl.end(nil, slog.String("job_id", job.ID), slog.Int("requests", len(req.Items)), slog.Bool("local", true))
+	return job, nil
+}
+
+// GetBatchJob 查询批量任务的状态。
+func (c *Client) GetBatchJob(ctx context.Context, id string) (*models.BatchJob, error) {
+	bh, err := c.batchHandlerFor(id)
+	if err != nil {
+		return nil, err
+	}
+	var job *models.BatchJob
+	err = c.withRetry(ctx, "GetBatchJob", func(ctx context.Context) error {
+		var opErr error
+		job, opErr = bh.GetBatchJob(ctx, id)
+		return opErr
+	})
+	return job, err
+}
+
+// CancelBatchJob 取消尚未结束的批量任务。
+func (c *Client) CancelBatchJob(ctx context.Context, id string) error {
+	bh, err := c.batchHandlerFor(id)
+	if err != nil {
+		return err
+	}
+	c.log.Info("Cancelling batch job", "job_id", id)
+	return bh.CancelBatchJob(ctx, id)
+}
+
+// BatchResults 返回已结束任务中每个请求的结果，通过 CustomID 与请求对应。
+func (c *Client) BatchResults(ctx context.Context, id string) ([]models.BatchResult, error) {
+	bh, err := c.batchHandlerFor(id)
+	if err != nil {
+		return nil, err
+	}
+	var results []models.BatchResult
+	err = c.withRetry(ctx, "BatchResults", func(ctx context.Context) error {
+		var opErr error
+		results, opErr = bh.BatchResults(ctx, id)
+		return opErr
+	})
+	return results, err
+}
+
+// WaitBatchJob 每隔 interval 查询一次任务状态，直到任务结束或 ctx 被取消。
+// interval 不大于 0 时使用 DefaultBatchPollInterval。
+func (c *Client) WaitBatchJob(ctx context.Context, id string, interval time.Duration) (*models.BatchJob, error) {
+	if interval <= 0 {
+		interval = DefaultBatchPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job, err := c.GetBatchJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status.Terminal() {
+			return job, nil
+		}
+		select {
+		case <-ctx.Done():
+			return job, errors.Wrap(ctx.Err(), errors.ErrCodeCancelled, fmt.Sprintf("stopped waiting for batch job %s", id))
+		case <-ticker.C:
+		}
+	}
+}
+
+// batchHandlerFor 根据任务 ID 选择执行该任务的处理器。
+func (c *Client) batchHandlerFor(id string) (platform.BatchHandler, error) {
+	if c.handler == nil {
+		return nil, fmt.Errorf("client not properly initialized or no handler set")
+	}
+	if strings.HasPrefix(id, batch.LocalJobIDPrefix) {
+		return c.localBatch, nil
+	}
+	if bh, ok := c.handler.(platform.BatchHandler); ok {
+		return bh, nil
+	}
+	return nil, errors.New(errors.ErrCodeNotFound, fmt.Sprintf("batch job %s not found", id))
+}
+
+// resolveBatchItemModel 解析单个批量请求中的模型别名，返回的 item 不与原请求共享请求结构体。
+func (c *Client) resolveBatchItemModel(item models.BatchRequestItem) models.BatchRequestItem {
+	if item.TextGeneration != nil {
+		req := *item.TextGeneration
+		req.Model = c.resolveModel(req.Model)
+		item.TextGeneration = &req
+	}
+	if item.Embedding != nil {
+		req := *item.Embedding
+		req.Model = c.resolveModel(req.Model)
+		item.Embedding = &req
+	}
+	return item
+}
+
+// executeBatchItem 是本地批量任务的执行器，复用客户端的重试策略。
+func (c *Client) executeBatchItem(ctx context.Context, op models.BatchOperation, item models.BatchRequestItem) models.BatchResult {
+	result := models.BatchResult{CustomID: item.CustomID}
+	var err error
+	switch op {
+	case models.BatchOperationTextGeneration:
+		req := *item.TextGeneration
+		req.Stream, req.OnStreamChunk = false, nil
+		result.TextGeneration, err = c.TextGeneration(ctx, &req)
+	case models.BatchOperationEmbedding:
+		result.Embedding, err = c.Embedding(ctx, item.Embedding)
+	default:
+		err = errors.New(errors.ErrCodeUnsupported, fmt.Sprintf("unsupported batch operation %q", op))
+	}
+	if err != nil {
+		result.Error = toBatchError(err)
+	}
+	return result
+}
+
+// toBatchError 将错误转换为 models.BatchError，SDK 错误保留其错误代码。
+func toBatchError(err error) *models.BatchError {
+	var sdkErr *errors.Error
+	if stderrors.As(err, &sdkErr) {
+		return &models.BatchError{Code: sdkErr.Code, Message: err.Error()}
+	}
+	return &models.BatchError{Code: errors.ErrCodePlatformError, Message: err.Error()}
+}
@@ -3,15 +3,27 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log" // 标准库 log
-	"os"  // 用于默认 logger
+	"log/slog"
+	"net/http"
+	"os" // 用于默认 logger
+	"sync"
+	"time"
 
+	"github.com/hewenyu/modelbridge/batch"
+	"github.com/hewenyu/modelbridge/config"
+	"github.com/hewenyu/modelbridge/errors"
 	"github.com/hewenyu/modelbridge/models"   // 假设的 module 路径
 	"github.com/hewenyu/modelbridge/platform" // 假设的 module 路径
+	"github.com/hewenyu/modelbridge/platform/rerank"
+	"github.com/hewenyu/modelbridge/platform/volcengine"
+	"github.com/hewenyu/modelbridge/redact"
+	"github.com/hewenyu/modelbridge/tracing"
+	"github.com/hewenyu/modelbridge/utils"
 	// 计划在这里导入具体的平台实现，例如：
 	// "github.com/hewenyu/modelbridge/platform/alibaba"
-	// "github.com/hewenyu/modelbridge/platform/volcengine"
 )
 
 // Logger 是一个简单的日志接口，允许用户提供自定义的日志实现。
@@ -22,8 +34,26 @@ type Logger interface {
 
 // Client 是与大模型平台交互的统一客户端。
 type Client struct {
-	handler platform.PlatformHandler // 内部持有一个特定平台的处理器
-	logger  Logger                   // 添加 logger 字段
+	handler      platform.PlatformHandler         // 内部持有一个特定平台的处理器
+	log          *slog.Logger                     // 结构化日志，WithLogger 设置的 Logger 通过 NewLoggerHandler 适配
+	modelAliases map[string]string                // 通用模型别名到平台模型 ID 的映射
+	retry        config.RetryPolicy               // 可重试错误的重试策略，零值表示不重试
+	httpClient   utils.HTTPClient                 // 可选，注入给平台处理器的 HTTP 客户端
+	transport    http.RoundTripper                // 可选，注入给平台处理器的底层 Transport
+	tlsConfig    *tls.Config                      // 可选，覆盖平台配置中的 TLS 设置
+	connPool     *platform.ConnectionPoolSettings // 可选，覆盖平台配置中的连接池设置
+	volcOpts     []volcengine.Option              // 可选，附加给火山方舟处理器的选项
+
+	batchConcurrency int                // 本地执行批量任务时的并发请求数
+	batchRetention   *time.Duration     // 本地批量任务结束后的保留时长，nil 时使用 batch.DefaultRetention
+	localBatch       *batch.LocalRunner // 平台不支持批量推理时使用的本地执行器
+	guardrail        *guardrail         // 可选，TextGeneration 前后的内容检查
+	redactor         redact.Redactor    // 识别日志与请求中的敏感内容，nil 表示使用 redact.Default()
+	redactPrompts    bool               // 是否在 TextGeneration 请求中使用可还原的占位符替换敏感内容
+	provider         string             // 平台名称，用于日志与追踪属性
+	tracer           tracing.Tracer     // 可选，为调用与 HTTP 请求创建 Span
+	metrics          *clientMetrics     // 可选，记录调用次数、耗时与 Token 用量
+	httpSpans        sync.Map           // *http.Request -> 进行中的 HTTP 请求 Span
 }
 
 // defaultLogger 是一个使用标准库 log.Logger 的默认实现。
@@ -39,25 +69,155 @@ func (l *defaultLogger) Println(v ...interface{}) {
 	l.stdlog.Println(v...)
 }
 
-// NewDefaultLogger 创建一个默认的 logger。
+// NewDefaultLogger 创建一个默认的 logger，输出到标准错误。日志经由 NewLoggerHandler 适配后由 SDK 内部统一调用，
+// 文件名与行号总是指向适配器本身，因此不再输出 (不使用 log.Lshortfile)。
 func NewDefaultLogger() Logger {
-	return &defaultLogger{stdlog: log.New(os.Stderr, "[ModelBridgeSDK] ", log.LstdFlags|log.Lshortfile)}
+	return &defaultLogger{stdlog: log.New(os.Stderr, "[ModelBridgeSDK] ", log.LstdFlags)}
 }
 
 // Option 是用于配置 Client 的函数选项类型。
 type Option func(*Client) error
 
-// WithLogger 设置自定义的 logger。
+// WithLogger 设置自定义的 logger，日志会以 key=value 文本的形式输出。为保持与以往相同的详细程度，
+// 包含 Debug 级别 (例如脱敏后的 Prompt 与每次 HTTP 请求)；只需要 Info 及以上级别或其他输出格式时，
+// 使用 WithSlogHandler(NewLoggerHandler(logger, nil)) 或其他 slog.Handler。未设置时默认 logger 只输出 Info 及以上级别。
 func WithLogger(logger Logger) Option {
 	return func(c *Client) error {
 		if logger == nil {
 			return fmt.Errorf("logger cannot be nil") // 或者可以允许 nil 并内部处理
 		}
-		c.logger = logger
+		c.log = slog.New(NewLoggerHandler(logger, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		return nil
+	}
+}
+
+// WithModelAliases 设置通用模型别名到平台模型 ID 的映射。
+// 请求中的 Model 命中别名时，会在发送给平台前替换为对应的模型 ID。
+func WithModelAliases(aliases map[string]string) Option {
+	return func(c *Client) error {
+		if c.modelAliases == nil {
+			c.modelAliases = make(map[string]string, len(aliases))
+		}
+		for alias, model := range aliases {
+			if model == "" {
+				return fmt.Errorf("model alias %q cannot map to an empty model ID", alias)
+			}
+			c.modelAliases[alias] = model
+		}
 		return nil
 	}
 }
 
+// WithRetryPolicy 设置对可重试错误 (限流、超时、平台错误) 的重试策略。
+func WithRetryPolicy(policy config.RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy.MaxAttempts < 0 {
+			return fmt.Errorf("retry max attempts cannot be negative")
+		}
+		c.retry = policy
+		return nil
+	}
+}
+
+// WithHTTPClient 设置平台处理器发送请求时使用的 HTTPClient，
+// 例如带有出口代理的客户端或测试中录制/回放请求的客户端。
+func WithHTTPClient(httpClient utils.HTTPClient) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return fmt.Errorf("http client cannot be nil")
+		}
+		c.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport 设置平台处理器使用的底层 http.RoundTripper，超时等其他设置保持平台默认值。
+// 同时设置 WithHTTPClient 时以 WithHTTPClient 为准。
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) error {
+		if transport == nil {
+			return fmt.Errorf("transport cannot be nil")
+		}
+		c.transport = transport
+		return nil
+	}
+}
+
+// WithTLSConfig 设置平台处理器使用的 TLS 配置，例如企业内部的根证书，会覆盖平台配置中的 TLS 设置。
+// 同时设置 WithTransport 或 WithHTTPClient 时，它们必须基于 *http.Transport，否则 NewClient 返回配置错误。
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *Client) error {
+		if tlsConfig == nil {
+			return fmt.Errorf("tls config cannot be nil")
+		}
+		c.tlsConfig = tlsConfig
+		return nil
+	}
+}
+
+// WithConnectionPool 设置平台处理器的连接池参数，会覆盖平台配置中的连接池设置，零值字段表示沿用默认设置。
+// 与 WithTLSConfig 一样要求底层 Transport 为 *http.Transport。
+func WithConnectionPool(pool platform.ConnectionPoolSettings) Option {
+	return func(c *Client) error {
+		if pool.MaxIdleConns < 0 || pool.MaxIdleConnsPerHost < 0 || pool.MaxConnsPerHost < 0 || pool.IdleConnTimeout < 0 {
+			return fmt.Errorf("connection pool settings cannot be negative")
+		}
+		c.connPool = &pool
+		return nil
+	}
+}
+
+// WithVolcengineOptions 设置创建火山方舟处理器时附加的选项，例如 volcengine.WithBatchStorage。
+// 仅在 Provider 为 volcengine 时生效。
+func WithVolcengineOptions(opts ...volcengine.Option) Option {
+	return func(c *Client) error {
+		c.volcOpts = append(c.volcOpts, opts...)
+		return nil
+	}
+}
+
+// WithBatchConcurrency 设置在本地执行批量任务时的并发请求数，默认为 batch.DefaultConcurrency。
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) error {
+		if n < 1 {
+			return fmt.Errorf("batch concurrency must be positive")
+		}
+		c.batchConcurrency = n
+		return nil
+	}
+}
+
+// WithBatchRetention 设置本地批量任务结束后的保留时长，默认为 batch.DefaultRetention；
+// d 小于等于 0 时任务一直保留，直到进程退出。
+func WithBatchRetention(d time.Duration) Option {
+	return func(c *Client) error {
+		c.batchRetention = &d
+		return nil
+	}
+}
+
+// NewClientFromConfig 从 YAML/JSON 配置文件创建客户端，使用文件中的默认平台。
+// 配置中的模型别名与重试策略会转换为对应的 Option，并先于 opts 应用。
+func NewClientFromConfig(path string, opts ...Option) (*Client, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	providerCfg, err := cfg.Provider("")
+	if err != nil {
+		return nil, err
+	}
+
+	var configOpts []Option
+	if len(providerCfg.ModelAliases) > 0 {
+		configOpts = append(configOpts, WithModelAliases(providerCfg.ModelAliases))
+	}
+	if providerCfg.Retry != nil {
+		configOpts = append(configOpts, WithRetryPolicy(*providerCfg.Retry))
+	}
+	return NewClient(providerCfg.PlatformConfig(), append(configOpts, opts...)...)
+}
+
 // NewClient 根据提供的平台配置创建一个新的客户端实例。
 // 可以通过传入 Option 函数来定制客户端，例如 WithLogger。
 func NewClient(config *platform.PlatformConfig, opts ...Option) (*Client, error) {
@@ -67,7 +227,7 @@ func NewClient(config *platform.PlatformConfig, opts ...Option) (*Client, error)
 
 	c := &Client{
 		// 设置默认 logger，后续可以被 Option覆盖
-		logger: NewDefaultLogger(),
+		log: slog.New(NewLoggerHandler(NewDefaultLogger(), nil)),
 	}
 
 	for _, opt := range opts {
@@ -79,37 +239,78 @@ func NewClient(config *platform.PlatformConfig, opts ...Option) (*Client, error)
 	var handler platform.PlatformHandler
 	var err error
 
-	c.logger.Printf("Initializing new client for provider: %s", config.Provider)
+	c.provider = string(config.Provider)
+	c.log = c.log.With(slog.String("provider", c.provider))
+	c.log.Debug("Initializing new client")
 
 	switch config.Provider {
 	case platform.ProviderAlibaba:
-		// handler, err = alibaba.NewHandler(config, c.logger) // 传递 logger 给 handler
-		c.logger.Println("Alibaba provider selected (not yet implemented)")
+		// handler, err = alibaba.NewHandler(config, c.log) // 传递 logger 给 handler
+		c.log.Warn("Alibaba provider selected (not yet implemented)")
 		// TODO: 实现阿里百炼平台的 Handler 初始化
 		err = fmt.Errorf("alibaba provider not yet implemented")
 	case platform.ProviderVolcengine:
-		// handler, err = volcengine.NewHandler(config, c.logger) // 传递 logger 给 handler
-		c.logger.Println("Volcengine provider selected (not yet implemented)")
-		// TODO: 实现火山方舟平台的 Handler 初始化
-		err = fmt.Errorf("volcengine provider not yet implemented")
+		// TODO: 传递 logger 给 handler
+		volcOpts := []volcengine.Option{volcengine.WithHooks(c.httpHooks())}
+		if c.transport != nil {
+			volcOpts = append(volcOpts, volcengine.WithTransport(c.transport))
+		}
+		if c.httpClient != nil {
+			volcOpts = append(volcOpts, volcengine.WithHTTPClient(c.httpClient))
+		}
+		if c.tlsConfig != nil {
+			volcOpts = append(volcOpts, volcengine.WithTLSConfig(c.tlsConfig))
+		}
+		if p := c.connPool; p != nil {
+			volcOpts = append(volcOpts,
+				volcengine.WithConnectionPool(p.MaxIdleConns, p.MaxIdleConnsPerHost, p.IdleConnTimeout),
+				volcengine.WithMaxConnsPerHost(p.MaxConnsPerHost))
+		}
+		volcOpts = append(volcOpts, c.volcOpts...)
+		handler, err = volcengine.NewHandler(config, volcOpts...)
+	case platform.ProviderRerank:
+		rerankOpts := []rerank.Option{rerank.WithHooks(c.httpHooks())}
+		if c.transport != nil {
+			rerankOpts = append(rerankOpts, rerank.WithTransport(c.transport))
+		}
+		if c.httpClient != nil {
+			rerankOpts = append(rerankOpts, rerank.WithHTTPClient(c.httpClient))
+		}
+		if c.tlsConfig != nil {
+			rerankOpts = append(rerankOpts, rerank.WithTLSConfig(c.tlsConfig))
+		}
+		if p := c.connPool; p != nil {
+			rerankOpts = append(rerankOpts,
+				rerank.WithConnectionPool(p.MaxIdleConns, p.MaxIdleConnsPerHost, p.IdleConnTimeout),
+				rerank.WithMaxConnsPerHost(p.MaxConnsPerHost))
+		}
+		handler, err = rerank.NewHandler(config, rerankOpts...)
 	default:
 		err = fmt.Errorf("unsupported provider: %s", config.Provider)
 	}
 
 	if err != nil {
-		c.logger.Printf("Failed to create platform handler for %s: %v", config.Provider, err)
+		c.log.Error("Failed to create platform handler", "error", err)
 		return nil, fmt.Errorf("failed to create platform handler for %s: %w", config.Provider, err) // 可以使用 errors.Wrap
 	}
 	// 理论上，如果上面的 case 没有正确实现，handler 可能依然是 nil
 	// 当具体 handler 实现后，这部分逻辑需要调整
 	if handler == nil && err == nil { // 确保如果没错误，handler 必须被设置
 		err = fmt.Errorf("handler not initialized for provider %s despite no error", config.Provider)
-		c.logger.Printf("Error: %v", err)
+		c.log.Error("Failed to create platform handler", "error", err)
 		return nil, err
 	}
 
 	c.handler = handler
-	c.logger.Println("Client initialized successfully.")
+	if err := c.checkGuardrail(); err != nil {
+		c.log.Error("Invalid guardrail configuration", "error", err)
+		return nil, err
+	}
+	c.localBatch = batch.NewLocalRunner(c.executeBatchItem, c.batchConcurrency)
+	if c.batchRetention != nil {
+		c.localBatch.SetRetention(*c.batchRetention)
+	}
+	c.log.Info("Client initialized successfully")
 	return c, nil
 }
 
@@ -117,50 +318,409 @@ func NewClient(config *platform.PlatformConfig, opts ...Option) (*Client, error)
 func (c *Client) TextGeneration(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
 	if c.handler == nil {
 		err := fmt.Errorf("client not properly initialized or no handler set")
-		c.logger.Printf("Error in TextGeneration: %v", err)
+		c.log.Error("TextGeneration failed", "error", err)
 		return nil, err // 可以返回自定义的 SDK Error
 	}
-	c.logger.Printf("Executing TextGeneration for model '%s' with prompt: \"%s...\"", req.Model, truncateForLog(req.Prompt, 30))
-	resp, err := c.handler.TextGeneration(ctx, req)
+	if req == nil {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "text generation request cannot be nil")
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "TextGeneration", resolved.Model)
+	cl.setAttributes(textGenerationRequestAttrs(&resolved)...)
+	// 先替换敏感内容，护栏的审核请求同样只会看到占位符。
+	var vault *redact.Vault
+	if c.redactPrompts {
+		vault = c.tokenizeInput(&resolved)
+	}
+	if c.guardrail != nil {
+		if err := c.guardInput(ctx, &resolved); err != nil {
+			cl.end(err, slog.String("stage", "input"))
+			return nil, err
+		}
+	}
+	cl.debug("Executing TextGeneration", slog.String("prompt", c.logText(resolved.Prompt)), slog.Bool("stream", req.OnStreamChunk != nil))
+	streamed := false
+	if req.OnStreamChunk != nil {
+		onChunk := req.OnStreamChunk
+		if vault != nil && vault.Len() > 0 {
+			onChunk = restoringStream(vault, onChunk)
+		}
+		resolved.OnStreamChunk = func(chunk *models.TextGenerationStreamChunk) error {
+			streamed = true
+			cl.streamChunk(chunk)
+			return onChunk(chunk)
+		}
+	}
+	var resp *models.TextGenerationResponse
+	err := c.withRetry(ctx, "TextGeneration", func(ctx context.Context) error {
+		cl.startAttempt()
+		var opErr error
+		resp, opErr = c.handler.TextGeneration(ctx, &resolved)
+		if opErr != nil && streamed {
+			// 已经向调用方输出过流式块，重试会导致内容重复。
+			return &noRetryError{err: opErr}
+		}
+		return opErr
+	})
+	if err == nil && resp == nil {
+		err = errors.New(errors.ErrCodeInvalidResponse, "platform returned no text generation response")
+	}
 	if err != nil {
-		c.logger.Printf("Error from platform handler in TextGeneration: %v", err)
+		cl.end(err)
+		return nil, err
+	}
+	cl.setUsage(resp.TokenUsage)
+	if c.guardrail != nil {
+		if err := c.guardOutput(ctx, resp); err != nil {
+			cl.end(err, slog.String("stage", "output"))
+			return nil, err
+		}
+	}
+	if vault != nil && vault.Len() > 0 {
+		restoreOutput(vault, resp)
 	}
-	return resp, err
+	cl.setAttributes(textGenerationResponseAttrs(resp)...)
+	cl.end(nil, slog.String("finish_reason", string(resp.FinishReason)))
+	return resp, nil
 }
 
 // ImageGeneration 使用配置的平台执行图片生成任务。
 func (c *Client) ImageGeneration(ctx context.Context, req *models.ImageGenerationRequest) (*models.ImageGenerationResponse, error) {
 	if c.handler == nil {
 		err := fmt.Errorf("client not properly initialized or no handler set")
-		c.logger.Printf("Error in ImageGeneration: %v", err)
+		c.log.Error("ImageGeneration failed", "error", err)
 		return nil, err
 	}
-	c.logger.Printf("Executing ImageGeneration for model '%s' with prompt: \"%s...\"", req.Model, truncateForLog(req.Prompt, 30))
-	resp, err := c.handler.ImageGeneration(ctx, req)
+	if req.Mask != "" && len(req.Images) != 1 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "image generation with a mask requires exactly one reference image")
+	}
+	if st := req.Strength; st != nil && (*st < 0 || *st > 1) {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("image generation strength must be in [0, 1], got %v", *st))
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "ImageGeneration", resolved.Model)
+	cl.debug("Executing ImageGeneration", slog.String("prompt", c.logText(req.Prompt)), slog.Int("reference_images", len(req.Images)))
+	var resp *models.ImageGenerationResponse
+	err := c.withRetry(ctx, "ImageGeneration", func(ctx context.Context) error {
+		var opErr error
+		resp, opErr = c.handler.ImageGeneration(ctx, &resolved)
+		return opErr
+	})
 	if err != nil {
-		c.logger.Printf("Error from platform handler in ImageGeneration: %v", err)
+		cl.end(err)
+		return resp, err
 	}
-	return resp, err
+	cl.end(nil, slog.Int("images", len(resp.Images)))
+	return resp, nil
 }
 
 // Embedding 使用配置的平台执行向量嵌入任务。
 func (c *Client) Embedding(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
 	if c.handler == nil {
 		err := fmt.Errorf("client not properly initialized or no handler set")
-		c.logger.Printf("Error in Embedding: %v", err)
+		c.log.Error("Embedding failed", "error", err)
 		return nil, err
 	}
-	inputCount := len(req.Input)
+	if req == nil || len(req.Input) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "embedding request requires at least one input")
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "Embedding", resolved.Model)
 	firstInput := ""
-	if inputCount > 0 {
+	if len(req.Input) > 0 {
 		firstInput = req.Input[0]
 	}
-	c.logger.Printf("Executing Embedding for model '%s' with %d inputs, first input: \"%s...\"", req.Model, inputCount, truncateForLog(firstInput, 30))
-	resp, err := c.handler.Embedding(ctx, req)
+	cl.debug("Executing Embedding", slog.Int("inputs", len(req.Input)), slog.String("first_input", c.logText(firstInput)))
+	var resp *models.EmbeddingResponse
+	err := c.withRetry(ctx, "Embedding", func(ctx context.Context) error {
+		var opErr error
+		resp, opErr = c.handler.Embedding(ctx, &resolved)
+		return opErr
+	})
+	if err == nil && resp == nil {
+		err = errors.New(errors.ErrCodeInvalidResponse, "platform returned no embedding response")
+	}
+	if err != nil {
+		cl.end(err)
+		return nil, err
+	}
+	cl.setUsage(resp.TokenUsage)
+	cl.end(nil, slog.Int("inputs", len(req.Input)))
+	return resp, nil
+}
+
+// Rerank 使用配置的平台对候选文档按与查询的相关性重新排序。
+func (c *Client) Rerank(ctx context.Context, req *models.RerankRequest) (*models.RerankResponse, error) {
+	if c.handler == nil {
+		err := fmt.Errorf("client not properly initialized or no handler set")
+		c.log.Error("Rerank failed", "error", err)
+		return nil, err
+	}
+	reranker, ok := c.handler.(platform.RerankHandler)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnsupported, "rerank is not supported by the configured platform")
+	}
+	if req == nil || req.Query == "" || len(req.Documents) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "rerank request requires a query and at least one document")
+	}
+	if req.TopN < 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "rerank top_n cannot be negative")
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "Rerank", resolved.Model)
+	cl.debug("Executing Rerank", slog.String("query", c.logText(req.Query)), slog.Int("documents", len(req.Documents)))
+	var resp *models.RerankResponse
+	err := c.withRetry(ctx, "Rerank", func(ctx context.Context) error {
+		var opErr error
+		resp, opErr = reranker.Rerank(ctx, &resolved)
+		return opErr
+	})
+	if err == nil && resp == nil {
+		err = errors.New(errors.ErrCodeInvalidResponse, "platform returned no rerank response")
+	}
+	if err != nil {
+		cl.end(err)
+		return nil, err
+	}
+	cl.setUsage(resp.TokenUsage)
+	cl.end(nil, slog.Int("documents", len(req.Documents)))
+	return resp, nil
+}
+
+// TextToSpeech 使用配置的平台将文本合成为语音。
+// req.Stream 为 true 时音频通过 resp.AudioStream 返回，调用方读取完毕后需关闭；
+// 重试只覆盖建立连接阶段，读取音频流时出现的错误不会重试。
+func (c *Client) TextToSpeech(ctx context.Context, req *models.TTSRequest) (*models.TTSResponse, error) {
+	if c.handler == nil {
+		err := fmt.Errorf("client not properly initialized or no handler set")
+		c.log.Error("TextToSpeech failed", "error", err)
+		return nil, err
+	}
+	speaker, ok := c.handler.(platform.SpeechHandler)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnsupported, "text to speech is not supported by the configured platform")
+	}
+	if req == nil || req.Text == "" {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "text to speech request requires text")
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "TextToSpeech", resolved.Model)
+	cl.debug("Executing TextToSpeech", slog.String("text", c.logText(req.Text)), slog.String("voice", req.Voice), slog.Bool("stream", req.Stream))
+	var resp *models.TTSResponse
+	err := c.withRetry(ctx, "TextToSpeech", func(ctx context.Context) error {
+		var opErr error
+		resp, opErr = speaker.TextToSpeech(ctx, &resolved)
+		return opErr
+	})
+	if err != nil {
+		cl.end(err)
+		return resp, err
+	}
+	cl.end(nil, slog.Int("characters", len([]rune(req.Text))), slog.Int("audio_bytes", len(resp.Audio)))
+	return resp, nil
+}
+
+// AudioTranscription 使用配置的平台识别音频中的语音。
+// req.AudioStream 不为 nil 时为流式识别，结果通过 req.OnStreamChunk 实时返回；
+// 流式识别的音频只能读取一次，因此不会重试。
+func (c *Client) AudioTranscription(ctx context.Context, req *models.AudioTranscriptionRequest) (*models.AudioTranscriptionResponse, error) {
+	if c.handler == nil {
+		err := fmt.Errorf("client not properly initialized or no handler set")
+		c.log.Error("AudioTranscription failed", "error", err)
+		return nil, err
+	}
+	transcriber, ok := c.handler.(platform.TranscriptionHandler)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnsupported, "audio transcription is not supported by the configured platform")
+	}
+	if req == nil {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "audio transcription request cannot be nil")
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "AudioTranscription", resolved.Model)
+	cl.debug("Executing AudioTranscription", slog.Int("audio_bytes", len(req.Audio)), slog.Bool("url", req.AudioURL != ""), slog.Bool("stream", req.AudioStream != nil))
+	var resp *models.AudioTranscriptionResponse
+	var err error
+	if req.AudioStream != nil {
+		resp, err = transcriber.AudioTranscription(ctx, &resolved)
+	} else {
+		err = c.withRetry(ctx, "AudioTranscription", func(ctx context.Context) error {
+			var opErr error
+			resp, opErr = transcriber.AudioTranscription(ctx, &resolved)
+			return opErr
+		})
+	}
+	if err != nil {
+		cl.end(err)
+		return resp, err
+	}
+	cl.end(nil, slog.Duration("audio_duration", resp.Duration))
+	return resp, nil
+}
+
+// Moderation 使用配置的平台审核文本内容。
+func (c *Client) Moderation(ctx context.Context, req *models.ModerationRequest) (*models.ModerationResponse, error) {
+	if c.handler == nil {
+		err := fmt.Errorf("client not properly initialized or no handler set")
+		c.log.Error("Moderation failed", "error", err)
+		return nil, err
+	}
+	moderator, ok := c.handler.(platform.ModerationHandler)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnsupported, "moderation is not supported by the configured platform")
+	}
+	if req == nil || len(req.Input) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "moderation request requires at least one input")
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "Moderation", resolved.Model)
+	cl.debug("Executing Moderation", slog.Int("inputs", len(req.Input)))
+	var resp *models.ModerationResponse
+	err := c.withRetry(ctx, "Moderation", func(ctx context.Context) error {
+		var opErr error
+		resp, opErr = moderator.Moderation(ctx, &resolved)
+		return opErr
+	})
+	if err == nil && resp == nil {
+		err = errors.New(errors.ErrCodeInvalidResponse, "platform returned no moderation response")
+	}
+	if err == nil && len(resp.Results) != len(req.Input) {
+		err = errors.New(errors.ErrCodeInvalidResponse, fmt.Sprintf("expected %d moderation results, got %d", len(req.Input), len(resp.Results)))
+	}
+	if err != nil {
+		cl.end(err)
+		return nil, err
+	}
+	flagged := 0
+	for _, result := range resp.Results {
+		if result.Flagged {
+			flagged++
+		}
+	}
+	cl.end(nil, slog.Int("inputs", len(req.Input)), slog.Int("flagged", flagged))
+	return resp, nil
+}
+
+// CreateContextCache 在支持上下文缓存的平台上创建缓存，返回的缓存 ID 可在 TextGenerationRequest.ContextCacheID 中引用。
+// 平台不支持时返回 ErrUnsupportedOperation 错误。
+func (c *Client) CreateContextCache(ctx context.Context, req *models.ContextCacheRequest) (*models.ContextCache, error) {
+	if c.handler == nil {
+		err := fmt.Errorf("client not properly initialized or no handler set")
+		c.log.Error("CreateContextCache failed", "error", err)
+		return nil, err
+	}
+	cacher, ok := c.handler.(platform.ContextCacheHandler)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeUnsupported, "context caching is not supported by the configured platform")
+	}
+	if req == nil {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "context cache request cannot be nil")
+	}
+	resolved := *req
+	resolved.Model = c.resolveModel(req.Model)
+	ctx, cl := c.beginCall(ctx, "CreateContextCache", resolved.Model)
+	cl.debug("Executing CreateContextCache", slog.Int("messages", len(req.Messages)))
+	var cache *models.ContextCache
+	err := c.withRetry(ctx, "CreateContextCache", func(ctx context.Context) error {
+		var opErr error
+		cache, opErr = cacher.CreateContextCache(ctx, &resolved)
+		return opErr
+	})
+	if err == nil && cache == nil {
+		err = errors.New(errors.ErrCodeInvalidResponse, "platform returned no context cache")
+	}
 	if err != nil {
-		c.logger.Printf("Error from platform handler in Embedding: %v", err)
+		cl.end(err)
+		return nil, err
+	}
+	cl.setUsage(cache.Usage)
+	cl.end(nil, slog.String("cache_id", cache.ID))
+	return cache, nil
+}
+
+// httpHooks 返回记录每次 HTTP 调用结果的回调。
+func (c *Client) httpHooks() utils.Hooks {
+	return utils.Hooks{
+		BeforeRequest: c.traceHTTPRequest,
+		AfterResponse: func(ctx context.Context, req *http.Request, meta *utils.ResponseMeta, err error) {
+			if meta == nil {
+				c.traceHTTPResponse(req, 0, "", err)
+				c.log.DebugContext(ctx, "HTTP request failed", "method", req.Method, "url", req.URL.Redacted(), "error", err)
+				return
+			}
+			c.traceHTTPResponse(req, meta.StatusCode, meta.RequestID, err)
+			recordCallResponse(ctx, meta.RequestID)
+			c.log.DebugContext(ctx, "HTTP request completed", "method", req.Method, "url", req.URL.Redacted(),
+				"status", meta.StatusCode, "latency", meta.Latency, "request_id", meta.RequestID)
+		},
+	}
+}
+
+// resolveModel 将模型别名解析为平台模型 ID，未命中别名时原样返回。
+func (c *Client) resolveModel(model string) string {
+	if resolved, ok := c.modelAliases[model]; ok {
+		return resolved
+	}
+	return model
+}
+
+// withRetry 按照客户端的重试策略执行 fn，只有可重试的错误才会触发重试。
+// fn 收到的 ctx 中包含本次尝试的 Span (如果开启了追踪)，平台处理器需要使用此 ctx。
+func (c *Client) withRetry(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	return c.withRetryPolicy(ctx, c.retry, operation, fn)
+}
+
+// withRetryPolicy 按照 policy 执行 fn，用于需要与客户端默认策略不同的操作。
+func (c *Client) withRetryPolicy(ctx context.Context, policy config.RetryPolicy, operation string, fn func(ctx context.Context) error) error {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		attemptCtx, span := c.startSpan(ctx, operation+" attempt", tracing.SpanKindInternal, tracing.Int(tracing.AttrAttempt, attempt))
+		err := fn(attemptCtx)
+		if nr, ok := err.(*noRetryError); ok {
+			span.End(nr.err)
+			return nr.err
+		}
+		span.End(err)
+		if err == nil || attempt >= maxAttempts || !isRetryable(err) {
+			return err
+		}
+		backoff := policy.Backoff(attempt)
+		c.log.WarnContext(ctx, "Retrying after retryable error", "operation", operation, "attempt", attempt, "max_attempts", maxAttempts,
+			"backoff", backoff, "error_code", errorCode(err), "error", err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
 	}
-	return resp, err
+}
+
+// noRetryError 包装不应被重试的错误。
+type noRetryError struct {
+	err error
+}
+
+func (e *noRetryError) Error() string {
+	return e.err.Error()
+}
+
+// isRetryable 判断错误是否值得重试：限流、超时与通用平台错误 (通常是 5xx)。
+func isRetryable(err error) bool {
+	return errors.IsSDKError(err, errors.ErrCodeRateLimited) ||
+		errors.IsSDKError(err, errors.ErrCodeTimeout) ||
+		errors.IsSDKError(err, errors.ErrCodePlatformError)
 }
 
 // truncateForLog is a helper function to truncate strings for logging.
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/config"
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+// fakeHandler 是测试用的平台处理器，未设置的操作会因嵌入的 nil 接口而 panic。
+type fakeHandler struct {
+	platform.PlatformHandler
+	textGeneration func(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error)
+	embedding      func(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error)
+	moderation     func(ctx context.Context, req *models.ModerationRequest) (*models.ModerationResponse, error)
+	limits         models.EmbeddingLimits
+}
+
+func (h *fakeHandler) TextGeneration(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+	return h.textGeneration(ctx, req)
+}
+
+func (h *fakeHandler) Embedding(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+	return h.embedding(ctx, req)
+}
+
+func (h *fakeHandler) Moderation(ctx context.Context, req *models.ModerationRequest) (*models.ModerationResponse, error) {
+	return h.moderation(ctx, req)
+}
+
+func (h *fakeHandler) EmbeddingLimits(model string) models.EmbeddingLimits {
+	return h.limits
+}
+
+// testConfig 返回指向 baseURL 的火山方舟配置，baseURL 为空时使用默认地址。
+func testConfig(baseURL string) *platform.PlatformConfig {
+	return &platform.PlatformConfig{
+		Provider:       platform.ProviderVolcengine,
+		Credentials:    map[string]string{"apiKey": "test-key"},
+		SpecificConfig: platform.ProviderSettings{BaseURL: baseURL},
+	}
+}
+
+// newFakeClient 创建使用 handler 的客户端，日志被丢弃。opts 在替换 handler 之后应用，
+// 因此护栏等依赖平台能力的检查针对的是 handler。
+func newFakeClient(t *testing.T, handler platform.PlatformHandler, opts ...Option) *Client {
+	t.Helper()
+	c, err := NewClient(testConfig(""), WithSlogHandler(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	c.handler = handler
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			t.Fatalf("option: %v", err)
+		}
+	}
+	if err := c.checkGuardrail(); err != nil {
+		t.Fatalf("checkGuardrail: %v", err)
+	}
+	return c
+}
+
+func TestTextGenerationRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		stream    bool
+		wantCalls int
+	}{
+		// 尚未输出流式块时，可重试错误会被重试。
+		{name: "before any chunk", wantCalls: 3},
+		// 已经输出过流式块时重试会导致内容重复，因此直接返回错误。
+		{name: "after a chunk", stream: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := &fakeHandler{textGeneration: func(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+				calls++
+				if tt.stream {
+					if err := req.OnStreamChunk(&models.TextGenerationStreamChunk{Delta: "partial"}); err != nil {
+						return nil, err
+					}
+				}
+				return nil, errors.New(errors.ErrCodePlatformError, "upstream reset")
+			}}
+			c := newFakeClient(t, handler, WithRetryPolicy(config.RetryPolicy{MaxAttempts: 3}))
+			_, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{
+				Model: "m", Prompt: "hi", Stream: tt.stream,
+				OnStreamChunk: func(*models.TextGenerationStreamChunk) error { return nil },
+			})
+			if !errors.IsSDKError(err, errors.ErrCodePlatformError) {
+				t.Errorf("err = %v, want %s", err, errors.ErrCodePlatformError)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// coreHandler 只实现 PlatformHandler 的必需方法，不实现任何可选接口。
+type coreHandler struct {
+	platform.PlatformHandler
+}
+
+func TestOptionalOperationsUnsupported(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient(t, &coreHandler{})
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "rerank", call: func() error {
+			_, err := c.Rerank(ctx, &models.RerankRequest{Model: "m", Query: "q", Documents: []string{"d"}})
+			return err
+		}},
+		{name: "text to speech", call: func() error {
+			_, err := c.TextToSpeech(ctx, &models.TTSRequest{Model: "m", Text: "hi"})
+			return err
+		}},
+		{name: "audio transcription", call: func() error {
+			_, err := c.AudioTranscription(ctx, &models.AudioTranscriptionRequest{Model: "m", Audio: []byte{0}})
+			return err
+		}},
+		{name: "moderation", call: func() error {
+			_, err := c.Moderation(ctx, &models.ModerationRequest{Model: "m", Input: []string{"hi"}})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); !errors.IsSDKError(err, errors.ErrCodeUnsupported) {
+			t.Errorf("%s: err = %v, want %s", tt.name, err, errors.ErrCodeUnsupported)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+func TestContextCache(t *testing.T) {
+	var paths []string
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		switch r.URL.Path {
+		case "/context/create":
+			fmt.Fprint(w, `{"id":"ctx-1","model":"doubao-pro","mode":"common_prefix","ttl":3600,"usage":{"prompt_tokens":120,"total_tokens":120}}`)
+		case "/context/chat/completions":
+			fmt.Fprint(w, `{"id":"chat-1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],`+
+				`"usage":{"prompt_tokens":130,"completion_tokens":1,"total_tokens":131,"prompt_tokens_details":{"cached_tokens":120}}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(testConfig(server.URL), WithSlogHandler(slog.NewTextHandler(io.Discard, nil)), WithModelAliases(map[string]string{"chat": "doubao-pro"}))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+
+	// 创建缓存时解析模型别名。
+	cache, err := c.CreateContextCache(ctx, &models.ContextCacheRequest{
+		Model:    "chat",
+		Messages: []models.Message{{Role: models.RoleSystem, Content: "You are a long system prompt."}},
+		Mode:     models.ContextCacheModeCommonPrefix,
+		TTL:      time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("CreateContextCache: %v", err)
+	}
+	if cache.ID != "ctx-1" || cache.Model != "doubao-pro" || cache.Usage.PromptTokens != 120 {
+		t.Errorf("cache = %+v", cache)
+	}
+	if bodies[0]["model"] != "doubao-pro" || bodies[0]["ttl"] != float64(3600) {
+		t.Errorf("create body = %v", bodies[0])
+	}
+
+	// 引用缓存的请求发往上下文缓存对话接口，命中的 Token 数量体现在 CachedTokens 中。
+	resp, err := c.TextGeneration(ctx, &models.TextGenerationRequest{Model: "chat", Prompt: "hi", ContextCacheID: cache.ID})
+	if err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	if paths[1] != "/context/chat/completions" || bodies[1]["context_id"] != "ctx-1" {
+		t.Errorf("chat request: path %s, body %v", paths[1], bodies[1])
+	}
+	if resp.TokenUsage.CachedTokens != 120 {
+		t.Errorf("CachedTokens = %d, want 120", resp.TokenUsage.CachedTokens)
+	}
+
+	// 应用 (Bot) 不能使用上下文缓存，请求在发送前被拒绝。
+	_, err = c.TextGeneration(ctx, &models.TextGenerationRequest{Model: "bot-20240101-abc", Prompt: "hi", ContextCacheID: cache.ID})
+	if !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("bot with cache: err = %v, want %s", err, errors.ErrCodeInvalidRequest)
+	}
+	if len(paths) != 2 {
+		t.Errorf("requests = %v, want no request for the bot", paths)
+	}
+}
+
+func TestContextCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient(t, &fakeHandler{})
+	if _, err := c.CreateContextCache(ctx, &models.ContextCacheRequest{Model: "m"}); !errors.IsSDKError(err, errors.ErrCodeUnsupported) {
+		t.Errorf("handler without caching: err = %v, want %s", err, errors.ErrCodeUnsupported)
+	}
+
+	c = newFakeClient(t, &fakeCacheHandler{})
+	if _, err := c.CreateContextCache(ctx, nil); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("nil request: err = %v, want %s", err, errors.ErrCodeInvalidRequest)
+	}
+	if _, err := c.TextGeneration(ctx, nil); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("nil text generation request: err = %v, want %s", err, errors.ErrCodeInvalidRequest)
+	}
+	cache, err := c.CreateContextCache(ctx, &models.ContextCacheRequest{Model: "m", Messages: []models.Message{{Role: models.RoleSystem, Content: "x"}}})
+	if cache != nil || !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+		t.Errorf("nil cache: cache = %v, err = %v, want %s", cache, err, errors.ErrCodeInvalidResponse)
+	}
+}
+
+// fakeCacheHandler 是支持上下文缓存但不返回缓存的处理器。
+type fakeCacheHandler struct {
+	fakeHandler
+}
+
+func (h *fakeCacheHandler) CreateContextCache(ctx context.Context, req *models.ContextCacheRequest) (*models.ContextCache, error) {
+	return nil, nil
+}
@@ -0,0 +1,259 @@
+// client/embed.go
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/modelbridge/config"
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+const (
+	// DefaultEmbeddingBatchSize 是平台未声明输入条数限制时 EmbedAll 每个请求的输入条数。
+	DefaultEmbeddingBatchSize = 64
+	// DefaultEmbeddingConcurrency 是 EmbedAll 默认的并发请求数。
+	DefaultEmbeddingConcurrency = 4
+)
+
+// defaultEmbeddingRetry 是客户端未配置重试策略时 EmbedAll 对单个批次使用的重试策略。
+var defaultEmbeddingRetry = config.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: config.Duration(500 * time.Millisecond),
+	MaxBackoff:     config.Duration(10 * time.Second),
+}
+
+// EmbedOption 是用于配置 EmbedAll 的函数选项类型。
+type EmbedOption func(*embedOptions)
+
+type embedOptions struct {
+	model          string
+	encodingFormat string
+	batchSize      int
+	concurrency    int
+	retry          *config.RetryPolicy
+	truncate       bool
+	platformParams map[string]interface{}
+}
+
+// WithEmbeddingModel 设置使用的模型 ID 或别名。
+func WithEmbeddingModel(model string) EmbedOption {
+	return func(o *embedOptions) {
+		o.model = model
+	}
+}
+
+// WithEmbeddingEncodingFormat 设置向量的编码格式，例如 "float"。
+func WithEmbeddingEncodingFormat(format string) EmbedOption {
+	return func(o *embedOptions) {
+		o.encodingFormat = format
+	}
+}
+
+// WithEmbeddingBatchSize 设置每个请求的输入条数，超过平台限制时按平台限制切分。
+func WithEmbeddingBatchSize(n int) EmbedOption {
+	return func(o *embedOptions) {
+		o.batchSize = n
+	}
+}
+
+// WithEmbeddingConcurrency 设置同时进行的请求数，默认为 DefaultEmbeddingConcurrency。
+func WithEmbeddingConcurrency(n int) EmbedOption {
+	return func(o *embedOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithEmbeddingRetry 设置单个批次失败时的重试策略。
+// 默认使用客户端的重试策略；客户端未配置重试时每个批次最多尝试 3 次。
+func WithEmbeddingRetry(policy config.RetryPolicy) EmbedOption {
+	return func(o *embedOptions) {
+		o.retry = &policy
+	}
+}
+
+// WithTruncateInputs 将超过平台单条输入 Token 限制的文本截断后再发送，而不是交由平台报错。
+// 由于客户端无法精确计算 Token，截断按字符数保守估计 (每个字符至少计为一个 Token)。
+func WithTruncateInputs() EmbedOption {
+	return func(o *embedOptions) {
+		o.truncate = true
+	}
+}
+
+// WithEmbeddingPlatformParams 设置每个请求的平台特定参数。
+func WithEmbeddingPlatformParams(params map[string]interface{}) EmbedOption {
+	return func(o *embedOptions) {
+		o.platformParams = params
+	}
+}
+
+// EmbedAll 为任意数量的文本生成向量嵌入。输入会按平台限制切分为多个请求并发执行，
+// 失败的批次按重试策略重试；返回的 Embeddings 按 Index 排序，Index 对应 texts 中的位置，
+// TokenUsage 为所有批次用量之和。任一批次最终失败时返回错误并取消其余批次。
+func (c *Client) EmbedAll(ctx context.Context, texts []string, opts ...EmbedOption) (*models.EmbeddingResponse, error) {
+	if c.handler == nil {
+		err := fmt.Errorf("client not properly initialized or no handler set")
+		c.log.Error("EmbedAll failed", "error", err)
+		return nil, err
+	}
+	o := embedOptions{concurrency: DefaultEmbeddingConcurrency}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.concurrency < 1 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "embedding concurrency must be positive")
+	}
+	if o.batchSize < 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "embedding batch size cannot be negative")
+	}
+	if len(texts) == 0 {
+		return &models.EmbeddingResponse{Embeddings: []models.Embedding{}}, nil
+	}
+
+	model := c.resolveModel(o.model)
+	var limits models.EmbeddingLimits
+	if limiter, ok := c.handler.(platform.EmbeddingLimiter); ok {
+		limits = limiter.EmbeddingLimits(model)
+	}
+	batchSize := o.batchSize
+	if batchSize == 0 {
+		batchSize = limits.MaxInputs
+	}
+	if batchSize == 0 {
+		batchSize = DefaultEmbeddingBatchSize
+	}
+	if limits.MaxInputs > 0 && batchSize > limits.MaxInputs {
+		batchSize = limits.MaxInputs
+	}
+	retry := c.retry
+	if o.retry != nil {
+		retry = *o.retry
+	} else if retry.MaxAttempts <= 1 {
+		retry = defaultEmbeddingRetry
+	}
+
+	inputs := texts
+	if o.truncate && limits.MaxInputTokens > 0 {
+		inputs = make([]string, len(texts))
+		for i, text := range texts {
+			inputs[i] = truncateRunes(text, limits.MaxInputTokens)
+		}
+	}
+
+	batches := (len(inputs) + batchSize - 1) / batchSize
+	ctx, cl := c.beginCall(ctx, "EmbedAll", model)
+	cl.debug("Executing EmbedAll", slog.Int("inputs", len(inputs)), slog.Int("batches", batches),
+		slog.Int("batch_size", batchSize), slog.Int("concurrency", o.concurrency))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		merged   = &models.EmbeddingResponse{Embeddings: make([]models.Embedding, 0, len(inputs))}
+		sem      = make(chan struct{}, o.concurrency)
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+loop:
+	for start := 0; start < len(inputs); start += batchSize {
+		end := start + batchSize
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		select {
+		case <-ctx.Done():
+			break loop
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			req := &models.EmbeddingRequest{
+				Input:                  inputs[start:end],
+				Model:                  model,
+				EncodingFormat:         o.encodingFormat,
+				PlatformSpecificParams: o.platformParams,
+			}
+			operation := fmt.Sprintf("EmbedAll batch [%d, %d)", start, end)
+			var resp *models.EmbeddingResponse
+			err := c.withRetryPolicy(ctx, retry, operation, func(ctx context.Context) error {
+				var opErr error
+				resp, opErr = c.handler.Embedding(ctx, req)
+				return opErr
+			})
+			if err == nil && resp == nil {
+				err = errors.New(errors.ErrCodeInvalidResponse, "platform returned no embedding response")
+			}
+			if err == nil && len(resp.Embeddings) != end-start {
+				err = errors.New(errors.ErrCodeInvalidResponse, fmt.Sprintf("expected %d embeddings, got %d", end-start, len(resp.Embeddings)))
+			}
+			if err != nil {
+				fail(wrapPreservingCode(err, fmt.Sprintf("embedding inputs [%d, %d) failed", start, end)))
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, emb := range resp.Embeddings {
+				emb.Index += start
+				merged.Embeddings = append(merged.Embeddings, emb)
+			}
+			merged.TokenUsage.Add(resp.TokenUsage)
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		// 调用方取消了 ctx，部分批次可能没有执行。
+		firstErr = errors.Wrap(ctx.Err(), errors.ErrCodeCancelled, "EmbedAll cancelled")
+	}
+	if firstErr != nil {
+		cl.end(firstErr)
+		return nil, firstErr
+	}
+	sort.Slice(merged.Embeddings, func(i, j int) bool { return merged.Embeddings[i].Index < merged.Embeddings[j].Index })
+	cl.setUsage(merged.TokenUsage)
+	cl.end(nil, slog.Int("inputs", len(inputs)), slog.Int("batches", batches))
+	return merged, nil
+}
+
+// wrapPreservingCode 为错误补充上下文，并保留 SDK 错误代码。
+func wrapPreservingCode(err error, message string) error {
+	code := errors.ErrCodePlatformError
+	var sdkErr *errors.Error
+	if stderrors.As(err, &sdkErr) {
+		code = sdkErr.Code
+	}
+	return errors.Wrap(err, code, message)
+}
+
+// truncateRunes 将 s 截断为最多 n 个字符。
+func truncateRunes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
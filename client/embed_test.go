@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/modelbridge/config"
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// embedIndex 返回以输入文本 (数字) 作为向量的响应，用于检查结果顺序。
+func embedIndex(req *models.EmbeddingRequest) *models.EmbeddingResponse {
+	resp := &models.EmbeddingResponse{TokenUsage: models.Usage{PromptTokens: len(req.Input), TotalTokens: len(req.Input)}}
+	for i, text := range req.Input {
+		n, _ := strconv.Atoi(text)
+		resp.Embeddings = append(resp.Embeddings, models.Embedding{Index: i, Embedding: []float32{float32(n)}})
+	}
+	return resp
+}
+
+func numberTexts(n int) []string {
+	texts := make([]string, n)
+	for i := range texts {
+		texts[i] = strconv.Itoa(i)
+	}
+	return texts
+}
+
+func TestEmbedAllOrderAcrossBatches(t *testing.T) {
+	var calls atomic.Int32
+	h := &fakeHandler{
+		limits: models.EmbeddingLimits{MaxInputs: 3},
+		embedding: func(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+			calls.Add(1)
+			// 先发出的批次更晚完成，使合并顺序与完成顺序不同。
+			first, _ := strconv.Atoi(req.Input[0])
+			time.Sleep(time.Duration(20-first) * time.Millisecond)
+			return embedIndex(req), nil
+		},
+	}
+	c := newFakeClient(t, h)
+
+	resp, err := c.EmbedAll(context.Background(), numberTexts(10), WithEmbeddingBatchSize(5), WithEmbeddingConcurrency(4))
+	if err != nil {
+		t.Fatalf("EmbedAll: %v", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("calls = %d, want 4 (batch size capped by the platform limit of 3)", got)
+	}
+	if len(resp.Embeddings) != 10 {
+		t.Fatalf("got %d embeddings", len(resp.Embeddings))
+	}
+	for i, emb := range resp.Embeddings {
+		if emb.Index != i || emb.Embedding[0] != float32(i) {
+			t.Errorf("embedding %d = {Index: %d, Embedding: %v}", i, emb.Index, emb.Embedding)
+		}
+	}
+	if resp.TokenUsage.PromptTokens != 10 {
+		t.Errorf("usage = %+v, want the sum of all batches", resp.TokenUsage)
+	}
+}
+
+func TestEmbedAllRetry(t *testing.T) {
+	var calls atomic.Int32
+	h := &fakeHandler{
+		embedding: func(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+			if calls.Add(1) == 1 {
+				return nil, errors.New(errors.ErrCodeRateLimited, "slow down")
+			}
+			return embedIndex(req), nil
+		},
+	}
+	c := newFakeClient(t, h)
+	retry := WithEmbeddingRetry(config.RetryPolicy{MaxAttempts: 2, InitialBackoff: config.Duration(time.Millisecond)})
+
+	resp, err := c.EmbedAll(context.Background(), numberTexts(3), retry)
+	if err != nil {
+		t.Fatalf("EmbedAll: %v", err)
+	}
+	if calls.Load() != 2 || len(resp.Embeddings) != 3 {
+		t.Errorf("calls = %d, embeddings = %d", calls.Load(), len(resp.Embeddings))
+	}
+
+	// 不可重试的错误立即返回，并保留错误代码。
+	calls.Store(0)
+	h.embedding = func(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+		calls.Add(1)
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "bad input")
+	}
+	_, err = c.EmbedAll(context.Background(), numberTexts(3), retry)
+	if !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) || calls.Load() != 1 {
+		t.Errorf("err = %v after %d calls, want ErrInvalidRequest after 1 call", err, calls.Load())
+	}
+}
+
+func TestEmbedAllCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	h := &fakeHandler{
+		embedding: func(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+			if calls.Add(1) == 1 {
+				cancel()
+			}
+			<-ctx.Done()
+			return nil, errors.Wrap(ctx.Err(), errors.ErrCodeCancelled, "cancelled")
+		},
+	}
+	c := newFakeClient(t, h)
+
+	_, err := c.EmbedAll(ctx, numberTexts(100), WithEmbeddingBatchSize(1), WithEmbeddingConcurrency(2))
+	if !errors.IsSDKError(err, errors.ErrCodeCancelled) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodeCancelled)
+	}
+	if got := calls.Load(); got > 2 {
+		t.Errorf("%d batches started after cancellation, want at most the 2 in flight", got)
+	}
+}
+
+func TestEmbedAllInvalidResponses(t *testing.T) {
+	tests := map[string]func(*models.EmbeddingRequest) (*models.EmbeddingResponse, error){
+		"nil response": func(*models.EmbeddingRequest) (*models.EmbeddingResponse, error) { return nil, nil },
+		"missing embeddings": func(req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+			resp := embedIndex(req)
+			resp.Embeddings = resp.Embeddings[1:]
+			return resp, nil
+		},
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &fakeHandler{embedding: func(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+				return fn(req)
+			}}
+			c := newFakeClient(t, h)
+			if _, err := c.EmbedAll(context.Background(), numberTexts(2)); !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+				t.Errorf("EmbedAll err = %v, want %s", err, errors.ErrCodeInvalidResponse)
+			}
+		})
+	}
+
+	c := newFakeClient(t, &fakeHandler{embedding: func(context.Context, *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+		return nil, nil
+	}})
+	if _, err := c.Embedding(context.Background(), &models.EmbeddingRequest{Input: []string{"a"}}); !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+		t.Errorf("Embedding err = %v, want %s", err, errors.ErrCodeInvalidResponse)
+	}
+	if _, err := c.Embedding(context.Background(), nil); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("Embedding(nil) err = %v, want %s", err, errors.ErrCodeInvalidRequest)
+	}
+}
@@ -0,0 +1,257 @@
+// client/guardrail.go
+package client
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+// GuardrailAction 是护栏规则命中时的处理方式。
+type GuardrailAction int
+
+const (
+	GuardrailBlock  GuardrailAction = iota // 拒绝请求或响应，返回 ErrContentRefused 错误
+	GuardrailRedact                        // 将命中的内容替换为 Replacement 后继续
+)
+
+// GuardrailScope 指定护栏规则检查的内容，可以按位组合。
+type GuardrailScope int
+
+const (
+	GuardrailInput  GuardrailScope = 1 << iota // 检查发送给模型的 Prompt 与文本内容
+	GuardrailOutput                            // 检查模型生成的文本
+
+	GuardrailBoth = GuardrailInput | GuardrailOutput
+)
+
+// DefaultRedaction 是 GuardrailRedact 规则未设置 Replacement 时使用的替换文本。
+const DefaultRedaction = "***"
+
+// GuardrailRule 是一条基于正则表达式或关键词的护栏规则。Pattern 与 Keywords 至少设置一个。
+type GuardrailRule struct {
+	Name        string         // 规则名称，拒绝时出现在错误的 PlatformDetails 中
+	Pattern     *regexp.Regexp // 匹配的正则表达式
+	Keywords    []string       // 匹配的关键词，不区分大小写
+	Action      GuardrailAction
+	Replacement string         // GuardrailRedact 时的替换文本，默认为 DefaultRedaction
+	Scope       GuardrailScope // 检查的内容，零值表示 GuardrailBoth
+}
+
+// Guardrail 配置了 TextGeneration 前后的内容检查。规则按顺序执行，之后再调用平台的 Moderation 接口 (如果开启)。
+// 命中拒绝时返回 ErrContentRefused 错误；审核失败时请求也会失败，而不是跳过审核 (fail closed)。
+// 开启审核但平台不提供 Moderation 时 NewClient 返回 ErrConfiguration 错误。火山方舟目前没有审核接口，在该平台上只能使用 Rules。
+//
+// 流式请求的输出检查在生成结束后对完整文本进行，已经通过 OnStreamChunk 输出的内容无法撤回或脱敏。
+type Guardrail struct {
+	Rules []GuardrailRule
+
+	ModerateInput   bool   // 是否在请求前审核输入
+	ModerateOutput  bool   // 是否在返回前审核输出
+	ModerationModel string // 审核使用的模型 ID 或别名，为空时使用平台默认
+	// BlockedCategories 指定需要拒绝的审核类别，为空时任何被标记的内容都会被拒绝。
+	BlockedCategories []string
+}
+
+// WithGuardrail 为 TextGeneration (以及基于它的 GenerateJSON 与本地批量任务) 启用护栏。
+func WithGuardrail(g Guardrail) Option {
+	return func(c *Client) error {
+		compiled := &guardrail{Guardrail: g}
+		for i, rule := range g.Rules {
+			cr, err := compileGuardrailRule(rule)
+			if err != nil {
+				return fmt.Errorf("guardrail rule %d (%s): %w", i, rule.Name, err)
+			}
+			compiled.rules = append(compiled.rules, cr)
+		}
+		c.guardrail = compiled
+		return nil
+	}
+}
+
+// checkGuardrail 检查护栏能否用于当前平台。开启审核而平台不提供 Moderation 时每次调用都会被拒绝，
+// 因此在创建客户端时返回配置错误。
+func (c *Client) checkGuardrail() error {
+	if c.guardrail == nil || (!c.guardrail.ModerateInput && !c.guardrail.ModerateOutput) {
+		return nil
+	}
+	if _, ok := c.handler.(platform.ModerationHandler); !ok {
+		return errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("guardrail moderation is enabled but provider %s does not support moderation, use Rules only", c.provider))
+	}
+	return nil
+}
+
+// guardrail 是编译后的护栏配置。
+type guardrail struct {
+	Guardrail
+	rules []compiledGuardrailRule
+}
+
+type compiledGuardrailRule struct {
+	name        string
+	patterns    []*regexp.Regexp
+	action      GuardrailAction
+	replacement string
+	scope       GuardrailScope
+}
+
+func compileGuardrailRule(rule GuardrailRule) (compiledGuardrailRule, error) {
+	cr := compiledGuardrailRule{name: rule.Name, action: rule.Action, replacement: rule.Replacement, scope: rule.Scope}
+	if cr.replacement == "" {
+		cr.replacement = DefaultRedaction
+	}
+	if cr.scope == 0 {
+		cr.scope = GuardrailBoth
+	}
+	if rule.Action != GuardrailBlock && rule.Action != GuardrailRedact {
+		return cr, fmt.Errorf("unknown action %d", rule.Action)
+	}
+	if rule.Pattern != nil {
+		cr.patterns = append(cr.patterns, rule.Pattern)
+	}
+	var quoted []string
+	for _, kw := range rule.Keywords {
+		if kw != "" {
+			quoted = append(quoted, regexp.QuoteMeta(kw))
+		}
+	}
+	if len(quoted) > 0 {
+		cr.patterns = append(cr.patterns, regexp.MustCompile("(?i)(?:"+strings.Join(quoted, "|")+")"))
+	}
+	if len(cr.patterns) == 0 {
+		return cr, fmt.Errorf("rule requires a pattern or keywords")
+	}
+	return cr, nil
+}
+
+// applyRules 依次对 text 执行 scope 内的规则，返回脱敏后的文本；命中拒绝规则时返回错误。
+func (g *guardrail) applyRules(stage string, scope GuardrailScope, text string) (string, error) {
+	for _, rule := range g.rules {
+		if rule.scope&scope == 0 {
+			continue
+		}
+		for _, re := range rule.patterns {
+			if !re.MatchString(text) {
+				continue
+			}
+			if rule.action == GuardrailBlock {
+				return "", refusal(stage, fmt.Sprintf("matched rule %q", rule.name), map[string]interface{}{"rule": rule.name})
+			}
+			text = re.ReplaceAllLiteralString(text, rule.replacement)
+		}
+	}
+	return text, nil
+}
+
+// checkModeration 调用平台审核 texts，任一文本命中需要拒绝的类别时返回错误。
+func (c *Client) checkModeration(ctx context.Context, stage string, texts []string) error {
+	var inputs []string
+	for _, t := range texts {
+		if strings.TrimSpace(t) != "" {
+			inputs = append(inputs, t)
+		}
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+	resp, err := c.Moderation(ctx, &models.ModerationRequest{Input: inputs, Model: c.guardrail.ModerationModel})
+	if err != nil {
+		return wrapPreservingCode(err, fmt.Sprintf("guardrail moderation of %s failed", stage))
+	}
+	for _, result := range resp.Results {
+		if !result.Flagged {
+			continue
+		}
+		flagged := result.FlaggedCategories()
+		if blocked := c.guardrail.blocked(flagged); len(blocked) > 0 || len(c.guardrail.BlockedCategories) == 0 {
+			if len(blocked) == 0 {
+				blocked = flagged
+			}
+			return refusal(stage, fmt.Sprintf("flagged by moderation (%s)", strings.Join(blocked, ", ")), map[string]interface{}{"categories": blocked})
+		}
+	}
+	return nil
+}
+
+// blocked 返回 flagged 中需要拒绝的类别。
+func (g *guardrail) blocked(flagged []string) []string {
+	var blocked []string
+	for _, category := range flagged {
+		for _, b := range g.BlockedCategories {
+			if category == b {
+				blocked = append(blocked, category)
+			}
+		}
+	}
+	return blocked
+}
+
+// guardInput 对请求的 Prompt 与文本内容执行输入规则与审核，脱敏直接修改 req。
+func (c *Client) guardInput(ctx context.Context, req *models.TextGenerationRequest) error {
+	g := c.guardrail
+	var err error
+	if req.Prompt, err = g.applyRules("input", GuardrailInput, req.Prompt); err != nil {
+		return err
+	}
+	texts := []string{req.Prompt}
+	if len(req.Parts) > 0 {
+		parts := make([]models.ContentPart, len(req.Parts))
+		copy(parts, req.Parts)
+		for i := range parts {
+			if parts[i].Type != models.ContentPartText {
+				continue
+			}
+			if parts[i].Text, err = g.applyRules("input", GuardrailInput, parts[i].Text); err != nil {
+				return err
+			}
+			texts = append(texts, parts[i].Text)
+		}
+		req.Parts = parts
+	}
+	if g.ModerateInput {
+		return c.checkModeration(ctx, "input", texts)
+	}
+	return nil
+}
+
+// guardOutput 对生成的每个候选结果执行输出规则与审核，脱敏直接修改 resp。
+func (c *Client) guardOutput(ctx context.Context, resp *models.TextGenerationResponse) error {
+	g := c.guardrail
+	var err error
+	if len(resp.Choices) == 0 {
+		if resp.GeneratedText, err = g.applyRules("output", GuardrailOutput, resp.GeneratedText); err != nil {
+			return err
+		}
+	} else {
+		for i := range resp.Choices {
+			if resp.Choices[i].Text, err = g.applyRules("output", GuardrailOutput, resp.Choices[i].Text); err != nil {
+				return err
+			}
+		}
+		resp.SetChoices(resp.Choices)
+	}
+	if !g.ModerateOutput {
+		return nil
+	}
+	texts := []string{resp.GeneratedText}
+	if len(resp.Choices) > 1 {
+		texts = texts[:0]
+		for _, choice := range resp.Choices {
+			texts = append(texts, choice.Text)
+		}
+	}
+	return c.checkModeration(ctx, "output", texts)
+}
+
+// refusal 创建 ErrContentRefused 错误。错误中不包含命中的原文，避免敏感内容进入日志。
+func refusal(stage, reason string, details map[string]interface{}) *errors.Error {
+	err := errors.New(errors.ErrCodeContentRefused, fmt.Sprintf("%s refused by guardrail: %s", stage, reason))
+	details["stage"] = stage
+	err.PlatformDetails = details
+	return err
+}
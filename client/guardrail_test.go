@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// echoGeneration 返回的文本是收到的 Prompt 加上 suffix，并记录发给平台的请求。
+func echoGeneration(sent *[]*models.TextGenerationRequest, suffix string) func(context.Context, *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+	return func(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+		*sent = append(*sent, req)
+		return &models.TextGenerationResponse{GeneratedText: req.Prompt + suffix, FinishReason: models.FinishReasonStop}, nil
+	}
+}
+
+func TestGuardrailRules(t *testing.T) {
+	var sent []*models.TextGenerationRequest
+	h := &fakeHandler{textGeneration: echoGeneration(&sent, " reply 13812345678 secret")}
+	c := newFakeClient(t, h, WithGuardrail(Guardrail{Rules: []GuardrailRule{
+		{Name: "project", Keywords: []string{"Project X"}, Action: GuardrailBlock, Scope: GuardrailInput},
+		{Name: "phone", Pattern: regexp.MustCompile(`1[3-9]\d{9}`), Action: GuardrailRedact, Replacement: "[PHONE]"},
+		{Name: "secret", Keywords: []string{"SECRET"}, Action: GuardrailRedact, Scope: GuardrailOutput},
+	}}))
+
+	// 关键词不区分大小写，拒绝时不调用平台，错误中不包含原文。
+	_, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Prompt: "tell me about project x"})
+	if !errors.IsSDKError(err, errors.ErrCodeContentRefused) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodeContentRefused)
+	}
+	details := err.(*errors.Error).PlatformDetails
+	if details["rule"] != "project" || details["stage"] != "input" || strings.Contains(err.Error(), "project x") {
+		t.Errorf("refusal = %v, details = %v", err, details)
+	}
+	if len(sent) != 0 {
+		t.Errorf("blocked request reached the platform")
+	}
+
+	// 输入规则不作用于输出，输出规则不作用于输入。
+	resp, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{
+		Prompt: "call 13900001111 about the secret",
+		Parts:  []models.ContentPart{models.TextPart("or 13700002222"), models.ImagePart("https://x/13600003333.png")},
+	})
+	if err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	if got := sent[0].Prompt; got != "call [PHONE] about the secret" {
+		t.Errorf("prompt sent = %q", got)
+	}
+	if sent[0].Parts[0].Text != "or [PHONE]" || sent[0].Parts[1].ImageURL != "https://x/13600003333.png" {
+		t.Errorf("parts sent = %+v", sent[0].Parts)
+	}
+	if want := "call [PHONE] about the *** reply [PHONE] ***"; resp.GeneratedText != want {
+		t.Errorf("output = %q, want %q", resp.GeneratedText, want)
+	}
+
+	// 输出中命中仅作用于输入的拒绝规则不会被拒绝。
+	h.textGeneration = func(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+		return &models.TextGenerationResponse{Choices: []models.Choice{{Index: 0, Text: "Project X a"}, {Index: 1, Text: "b SeCrEt"}}}, nil
+	}
+	resp, err = c.TextGeneration(context.Background(), &models.TextGenerationRequest{Prompt: "hi"})
+	if err != nil || resp.Choices[0].Text != "Project X a" || resp.Choices[1].Text != "b ***" || resp.GeneratedText != "Project X a" {
+		t.Errorf("resp = %+v, err = %v", resp, err)
+	}
+}
+
+func TestGuardrailOutputBlock(t *testing.T) {
+	var sent []*models.TextGenerationRequest
+	c := newFakeClient(t, &fakeHandler{textGeneration: echoGeneration(&sent, " DROP TABLE")}, WithGuardrail(Guardrail{Rules: []GuardrailRule{
+		{Name: "sql", Pattern: regexp.MustCompile(`(?i)drop\s+table`), Action: GuardrailBlock, Scope: GuardrailOutput},
+	}}))
+	_, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Prompt: "drop table is fine in input"})
+	if !errors.IsSDKError(err, errors.ErrCodeContentRefused) || err.(*errors.Error).PlatformDetails["stage"] != "output" {
+		t.Errorf("err = %v, want an output refusal", err)
+	}
+}
+
+func TestGuardrailInvalidRules(t *testing.T) {
+	for _, rule := range []GuardrailRule{
+		{Name: "empty"},
+		{Name: "blank keywords", Keywords: []string{""}},
+		{Name: "action", Keywords: []string{"x"}, Action: GuardrailAction(9)},
+	} {
+		if _, err := NewClient(testConfig(""), WithGuardrail(Guardrail{Rules: []GuardrailRule{rule}})); err == nil {
+			t.Errorf("rule %q: NewClient succeeded", rule.Name)
+		}
+	}
+}
+
+func TestGuardrailModeration(t *testing.T) {
+	var moderated [][]string
+	var sent []*models.TextGenerationRequest
+	h := &fakeHandler{
+		textGeneration: echoGeneration(&sent, " and violence"),
+		moderation: func(ctx context.Context, req *models.ModerationRequest) (*models.ModerationResponse, error) {
+			moderated = append(moderated, req.Input)
+			resp := &models.ModerationResponse{}
+			for _, text := range req.Input {
+				result := models.ModerationResult{Categories: map[string]bool{
+					models.ModerationViolence: strings.Contains(text, "violence"),
+					models.ModerationHate:     strings.Contains(text, "hate"),
+				}}
+				result.Flagged = result.Categories[models.ModerationViolence] || result.Categories[models.ModerationHate]
+				resp.Results = append(resp.Results, result)
+			}
+			return resp, nil
+		},
+	}
+	c := newFakeClient(t, h, WithGuardrail(Guardrail{
+		ModerateInput:     true,
+		ModerateOutput:    true,
+		ModerationModel:   "moderation-model",
+		BlockedCategories: []string{models.ModerationViolence},
+	}))
+
+	// 输出命中需要拒绝的类别。
+	_, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Prompt: "peace"})
+	if !errors.IsSDKError(err, errors.ErrCodeContentRefused) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodeContentRefused)
+	}
+	details := err.(*errors.Error).PlatformDetails
+	if details["stage"] != "output" || strings.Join(details["categories"].([]string), ",") != models.ModerationViolence {
+		t.Errorf("details = %v", details)
+	}
+	if len(moderated) != 2 || moderated[0][0] != "peace" || moderated[1][0] != "peace and violence" {
+		t.Errorf("moderated = %v", moderated)
+	}
+
+	// 被标记但不在 BlockedCategories 中的内容放行。
+	h.textGeneration = echoGeneration(&sent, "")
+	if _, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Prompt: "hate"}); err != nil {
+		t.Errorf("non-blocked category: err = %v", err)
+	}
+
+	// 输入被拒绝时不调用平台生成。
+	sent = nil
+	_, err = c.TextGeneration(context.Background(), &models.TextGenerationRequest{Prompt: "violence"})
+	if !errors.IsSDKError(err, errors.ErrCodeContentRefused) || err.(*errors.Error).PlatformDetails["stage"] != "input" || len(sent) != 0 {
+		t.Errorf("input: err = %v, platform calls = %d", err, len(sent))
+	}
+}
+
+func TestGuardrailModerationFailsClosed(t *testing.T) {
+	var sent []*models.TextGenerationRequest
+	h := &fakeHandler{
+		textGeneration: echoGeneration(&sent, ""),
+		moderation: func(ctx context.Context, req *models.ModerationRequest) (*models.ModerationResponse, error) {
+			return nil, errors.New(errors.ErrCodeUnsupported, "moderation is not supported")
+		},
+	}
+	c := newFakeClient(t, h, WithGuardrail(Guardrail{ModerateInput: true}))
+	_, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Prompt: "hi"})
+	if !errors.IsSDKError(err, errors.ErrCodeUnsupported) || len(sent) != 0 {
+		t.Errorf("err = %v, platform calls = %d, want the request to fail without reaching the platform", err, len(sent))
+	}
+
+	h.moderation = func(ctx context.Context, req *models.ModerationRequest) (*models.ModerationResponse, error) {
+		return nil, nil
+	}
+	if _, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Prompt: "hi"}); !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+		t.Errorf("nil moderation response: err = %v, want %s", err, errors.ErrCodeInvalidResponse)
+	}
+}
+
+func TestGuardrailModerationRequiresSupport(t *testing.T) {
+	discard := WithSlogHandler(slog.NewTextHandler(io.Discard, nil))
+	tests := []struct {
+		name      string
+		guardrail Guardrail
+		wantErr   bool
+	}{
+		{name: "input moderation", guardrail: Guardrail{ModerateInput: true}, wantErr: true},
+		{name: "output moderation", guardrail: Guardrail{ModerateOutput: true}, wantErr: true},
+		{name: "rules only", guardrail: Guardrail{Rules: []GuardrailRule{{Name: "k", Keywords: []string{"secret"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 火山方舟没有审核接口，开启审核的护栏在创建客户端时被拒绝，而不是让每次调用失败。
+			c, err := NewClient(testConfig(""), discard, WithGuardrail(tt.guardrail))
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("NewClient: %v", err)
+				}
+				return
+			}
+			if c != nil || !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+				t.Errorf("client = %v, err = %v, want %s", c, err, errors.ErrCodeConfiguration)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// client/log.go
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/tracing"
+)
+
+// WithSlogHandler 使用 slog.Handler 输出结构化日志，会覆盖 WithLogger 的设置。
+// 每次调用结束时在 Info 级别 (失败时为 Warn 或 Error) 记录 provider、operation、model、request_id、latency、
+// usage 与 error_code 等属性；Prompt 等请求内容只在 Debug 级别记录，并且总是经过 WithRedactor 脱敏。
+func WithSlogHandler(h slog.Handler) Option {
+	return func(c *Client) error {
+		if h == nil {
+			return stderrors.New("slog handler cannot be nil")
+		}
+		c.log = slog.New(h)
+		return nil
+	}
+}
+
+// NewLoggerHandler 将 Logger 适配为 slog.Handler，每条记录格式化为 key=value 形式的一行文本后通过 Printf 输出。
+// 时间由 Logger 自行输出，因此记录中的时间会被省略。opts 为 nil 时输出 Info 及以上级别。
+func NewLoggerHandler(logger Logger, opts *slog.HandlerOptions) slog.Handler {
+	var o slog.HandlerOptions
+	if opts != nil {
+		o = *opts
+	}
+	replace := o.ReplaceAttr
+	o.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		if replace != nil {
+			return replace(groups, a)
+		}
+		return a
+	}
+	return slog.NewTextHandler(&loggerWriter{logger: logger}, &o)
+}
+
+// loggerWriter 将 slog.TextHandler 输出的每一行转交给 Logger。TextHandler 每条记录只调用一次 Write。
+type loggerWriter struct {
+	logger Logger
+}
+
+var _ io.Writer = (*loggerWriter)(nil)
+
+func (w *loggerWriter) Write(p []byte) (int, error) {
+	w.logger.Printf("%s", strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// callInfoKey 是 context 中保存 *callInfo 的键。
+type callInfoKey struct{}
+
+// callInfo 收集一次客户端调用中由 HTTP 回调记录的信息。
+type callInfo struct {
+	mu        sync.Mutex
+	requestID string
+}
+
+// recordCallResponse 将平台返回的请求 ID 记录到 ctx 所属的调用中，重试时保留最后一次的请求 ID。
+func recordCallResponse(ctx context.Context, requestID string) {
+	if info, ok := ctx.Value(callInfoKey{}).(*callInfo); ok && requestID != "" {
+		info.mu.Lock()
+		info.requestID = requestID
+		info.mu.Unlock()
+	}
+}
+
+// call 记录一次客户端调用的日志、Span 与指标。
+type call struct {
+	log       *slog.Logger
+	ctx       context.Context
+	operation string
+	model     string
+	provider  string
+	start     time.Time
+	info      *callInfo
+	span      tracing.Span
+	metrics   *clientMetrics
+	usage     models.Usage
+
+	attemptStart time.Time // 最近一次向平台发起请求的时间，首个文本块的时间从此计算
+	firstChunk   time.Time // 收到第一个流式文本块的时间
+	lastChunk    time.Time // 收到上一个流式文本块的时间
+}
+
+// beginCall 开始记录一次调用，返回的 ctx 需要传给平台处理器，以便关联平台返回的请求 ID 与追踪上下文。
+func (c *Client) beginCall(ctx context.Context, operation, model string) (context.Context, *call) {
+	info := &callInfo{}
+	ctx, span := c.startCallSpan(ctx, operation, model)
+	return context.WithValue(ctx, callInfoKey{}, info), &call{
+		log:       c.log.With(slog.String("operation", operation), slog.String("model", model)),
+		ctx:       ctx,
+		operation: operation,
+		model:     model,
+		provider:  c.provider,
+		start:     time.Now(),
+		info:      info,
+		span:      span,
+		metrics:   c.metrics,
+	}
+}
+
+// setUsage 记录调用的 Token 用量，在 end 时输出到日志、Span 与指标。
+func (cl *call) setUsage(u models.Usage) {
+	cl.usage = u
+}
+
+// startAttempt 在每次向平台发起请求前调用。首个文本块的时间从最近一次尝试开始计算，
+// 不包含护栏审核、失败的重试与重试前的等待。
+func (cl *call) startAttempt() {
+	cl.attemptStart = time.Now()
+}
+
+// timeToFirstToken 返回从最近一次尝试开始到收到第一个文本块的时间。
+func (cl *call) timeToFirstToken() time.Duration {
+	start := cl.attemptStart
+	if start.IsZero() {
+		start = cl.start
+	}
+	return cl.firstChunk.Sub(start)
+}
+
+// streamChunk 在收到流式块时调用，用于计算首个文本块的时间与相邻文本块的间隔。不含文本的块会被忽略。
+func (cl *call) streamChunk(chunk *models.TextGenerationStreamChunk) {
+	if chunk.Delta == "" && chunk.Reasoning == "" {
+		return
+	}
+	now := time.Now()
+	if cl.firstChunk.IsZero() {
+		cl.firstChunk = now
+		if cl.metrics != nil {
+			cl.metrics.timeToFirstToken.Observe(cl.timeToFirstToken().Seconds(), cl.provider, cl.model, cl.operation)
+		}
+	} else if cl.metrics != nil {
+		cl.metrics.interTokenLatency.Observe(now.Sub(cl.lastChunk).Seconds(), cl.provider, cl.model, cl.operation)
+	}
+	cl.lastChunk = now
+}
+
+// setAttributes 设置调用 Span 的属性。
+func (cl *call) setAttributes(attrs ...tracing.Attribute) {
+	cl.span.SetAttributes(attrs...)
+}
+
+// debug 在 Debug 级别记录调用的请求内容。
+func (cl *call) debug(msg string, args ...any) {
+	cl.log.DebugContext(cl.ctx, msg, args...)
+}
+
+// end 记录调用结果。调用方的问题 (请求无效、内容被拒绝、取消) 记录为 Warn，其余错误记录为 Error。
+func (cl *call) end(err error, args ...any) {
+	cl.info.mu.Lock()
+	requestID := cl.info.requestID
+	cl.info.mu.Unlock()
+	latency := time.Since(cl.start)
+	if cl.metrics != nil {
+		cl.metrics.observeCall(cl.provider, cl.model, cl.operation, latency, cl.usage, err)
+	}
+	attrs := []any{slog.Duration("latency", latency)}
+	if requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+		cl.span.SetAttributes(tracing.String(tracing.AttrRequestID, requestID))
+	}
+	if !cl.firstChunk.IsZero() {
+		attrs = append(attrs, slog.Duration("time_to_first_token", cl.timeToFirstToken()))
+	}
+	if cl.usage != (models.Usage{}) {
+		attrs = append(attrs, usageAttr(cl.usage))
+		cl.span.SetAttributes(usageSpanAttrs(cl.usage)...)
+	}
+	attrs = append(attrs, args...)
+	if err == nil {
+		cl.span.End(nil)
+		cl.log.InfoContext(cl.ctx, cl.operation+" completed", attrs...)
+		return
+	}
+	cl.span.SetAttributes(tracing.String(tracing.AttrErrorType, errorCode(err)))
+	cl.span.End(err)
+	attrs = append(attrs, slog.String("error_code", errorCode(err)), slog.Any("error", err))
+	level := slog.LevelError
+	if isCallerError(err) {
+		level = slog.LevelWarn
+	}
+	cl.log.Log(cl.ctx, level, cl.operation+" failed", attrs...)
+}
+
+// usageAttr 返回 Token 用量属性，平台未提供用量时返回空属性。
+func usageAttr(u models.Usage) slog.Attr {
+	if u == (models.Usage{}) {
+		return slog.Attr{}
+	}
+	return slog.Group("usage",
+		slog.Int("prompt_tokens", u.PromptTokens),
+		slog.Int("completion_tokens", u.CompletionTokens),
+		slog.Int("total_tokens", u.TotalTokens),
+	)
+}
+
+// errorCode 返回错误的 SDK 错误代码，非 SDK 错误返回 ErrInternal。
+func errorCode(err error) string {
+	var sdkErr *errors.Error
+	if stderrors.As(err, &sdkErr) {
+		return sdkErr.Code
+	}
+	return errors.ErrCodeInternal
+}
+
+func isCallerError(err error) bool {
+	switch errorCode(err) {
+	case errors.ErrCodeInvalidRequest, errors.ErrCodeContentRefused, errors.ErrCodeCancelled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// logCapture 以 JSON 记录 slog 输出，便于按属性断言。
+type logCapture struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *logCapture) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.Write(p)
+}
+
+func (l *logCapture) handler(level slog.Level) slog.Handler {
+	return slog.NewJSONHandler(l, &slog.HandlerOptions{Level: level})
+}
+
+// records 返回已记录的日志，每条为解码后的 JSON 对象。
+func (l *logCapture) records(t *testing.T) []map[string]interface{} {
+	t.Helper()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(l.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var r map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// find 返回消息为 msg 的日志。
+func (l *logCapture) find(t *testing.T, msg string) map[string]interface{} {
+	t.Helper()
+	for _, r := range l.records(t) {
+		if r[slog.MessageKey] == msg {
+			return r
+		}
+	}
+	t.Fatalf("no %q record in %s", msg, l.buf.String())
+	return nil
+}
+
+// newLogTestClient 创建请求发往 handler 的客户端，日志记录到返回的 logCapture。
+func newLogTestClient(t *testing.T, level slog.Level, handler http.HandlerFunc) (*Client, *logCapture) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	logs := &logCapture{}
+	c, err := NewClient(testConfig(server.URL), WithSlogHandler(logs.handler(level)))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c, logs
+}
+
+const secretPrompt = "call alice@example.com about the invoice"
+
+func TestCallLogAttributes(t *testing.T) {
+	c, logs := newLogTestClient(t, slog.LevelDebug, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-42")
+		fmt.Fprint(w, `{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],`+
+			`"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`)
+	})
+	if _, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "doubao-pro", Prompt: secretPrompt}); err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+
+	done := logs.find(t, "TextGeneration completed")
+	want := map[string]interface{}{
+		slog.LevelKey: "INFO",
+		"provider":    "volcengine",
+		"operation":   "TextGeneration",
+		"model":       "doubao-pro",
+		"request_id":  "req-42",
+	}
+	for k, v := range want {
+		if done[k] != v {
+			t.Errorf("%s = %v, want %v", k, done[k], v)
+		}
+	}
+	if _, ok := done["latency"].(float64); !ok {
+		t.Errorf("latency = %v, want a duration", done["latency"])
+	}
+	if usage, _ := done["usage"].(map[string]interface{}); usage["total_tokens"] != float64(7) || usage["prompt_tokens"] != float64(5) {
+		t.Errorf("usage = %v", done["usage"])
+	}
+	if _, ok := done["error_code"]; ok {
+		t.Errorf("successful call has error_code %v", done["error_code"])
+	}
+
+	// Prompt 只出现在 Debug 级别，并且经过脱敏。
+	debug := logs.find(t, "Executing TextGeneration")
+	prompt, _ := debug["prompt"].(string)
+	if debug[slog.LevelKey] != "DEBUG" || prompt == "" || strings.Contains(prompt, "alice@example.com") {
+		t.Errorf("debug record = %v, want a redacted prompt at DEBUG", debug)
+	}
+	for _, r := range logs.records(t) {
+		line, _ := json.Marshal(r)
+		if strings.Contains(string(line), "alice@example.com") {
+			t.Errorf("record leaks the prompt: %s", line)
+		}
+		if _, ok := r["prompt"]; ok && r[slog.LevelKey] != "DEBUG" {
+			t.Errorf("prompt logged at %v", r[slog.LevelKey])
+		}
+	}
+}
+
+func TestCallLogLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantCode  string
+		wantLevel string
+	}{
+		{name: "invalid request", status: http.StatusBadRequest, wantCode: errors.ErrCodeInvalidRequest, wantLevel: "WARN"},
+		{name: "authentication", status: http.StatusUnauthorized, wantCode: errors.ErrCodeAuthentication, wantLevel: "ERROR"},
+		{name: "platform error", status: http.StatusInternalServerError, wantCode: errors.ErrCodePlatformError, wantLevel: "ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, logs := newLogTestClient(t, slog.LevelInfo, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-err")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"error":{"code":"Failed","message":"failed"}}`)
+			})
+			_, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: secretPrompt})
+			if !errors.IsSDKError(err, tt.wantCode) {
+				t.Fatalf("err = %v, want %s", err, tt.wantCode)
+			}
+			if isCallerError(err) != (tt.wantLevel == "WARN") {
+				t.Errorf("isCallerError(%v) = %v", err, isCallerError(err))
+			}
+			failed := logs.find(t, "TextGeneration failed")
+			if failed[slog.LevelKey] != tt.wantLevel || failed["error_code"] != tt.wantCode || failed["request_id"] != "req-err" ||
+				failed["provider"] != "volcengine" || failed["model"] != "m" || failed["latency"] == nil {
+				t.Errorf("record = %v, want %s with error_code %s", failed, tt.wantLevel, tt.wantCode)
+			}
+			// Info 级别不输出请求内容。
+			for _, r := range logs.records(t) {
+				if r[slog.LevelKey] == "DEBUG" || r["prompt"] != nil {
+					t.Errorf("unexpected record at Info level: %v", r)
+				}
+			}
+		})
+	}
+
+	// 取消的调用同样按调用方错误记录。
+	c, logs := newLogTestClient(t, slog.LevelInfo, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a cancelled call must not reach the platform")
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.TextGeneration(ctx, &models.TextGenerationRequest{Model: "m", Prompt: "hi"})
+	if failed := logs.find(t, "TextGeneration failed"); !errors.IsSDKError(err, errors.ErrCodeCancelled) || failed[slog.LevelKey] != "WARN" || failed["error_code"] != errors.ErrCodeCancelled {
+		t.Errorf("cancelled: record = %v, err = %v", failed, err)
+	}
+}
+
+// lineLogger 记录 Logger 收到的每一行。
+type lineLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *lineLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func (l *lineLogger) Println(v ...interface{}) {
+	l.Printf("%s", fmt.Sprint(v...))
+}
+
+func (l *lineLogger) text() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+func TestNewLoggerHandler(t *testing.T) {
+	l := &lineLogger{}
+	log := slog.New(NewLoggerHandler(l, nil)).With("provider", "volcengine")
+	log.Debug("hidden")
+	log.Info("call completed", "request_id", "req-1", slog.Group("usage", slog.Int("total_tokens", 7)))
+	log.Error("call failed", "error_code", errors.ErrCodePlatformError)
+
+	if len(l.lines) != 2 {
+		t.Fatalf("lines = %q, want Info and Error only", l.lines)
+	}
+	want := []string{
+		`level=INFO msg="call completed" provider=volcengine request_id=req-1 usage.total_tokens=7`,
+		`level=ERROR msg="call failed" provider=volcengine error_code=` + errors.ErrCodePlatformError,
+	}
+	for i, line := range l.lines {
+		// 时间由 Logger 自行输出，每条记录恰好一行。
+		if line != want[i] {
+			t.Errorf("line %d = %q, want %q", i, line, want[i])
+		}
+	}
+
+	l = &lineLogger{}
+	slog.New(NewLoggerHandler(l, &slog.HandlerOptions{Level: slog.LevelDebug})).Debug("shown")
+	if l.text() != `level=DEBUG msg=shown` {
+		t.Errorf("debug line = %q", l.text())
+	}
+}
+
+func TestWithLoggerKeepsDebugOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+	l := &lineLogger{}
+	c, err := NewClient(testConfig(server.URL), WithLogger(l))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: secretPrompt}); err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	out := l.text()
+	for _, want := range []string{`msg="Executing TextGeneration"`, `msg="HTTP request completed"`, `msg="TextGeneration completed"`, "provider=volcengine"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %s:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "alice@example.com") {
+		t.Errorf("output leaks the prompt:\n%s", out)
+	}
+}
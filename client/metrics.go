@@ -0,0 +1,65 @@
+// client/metrics.go
+package client
+
+import (
+	stderrors "errors"
+	"time"
+
+	"github.com/hewenyu/modelbridge/metrics"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// 客户端记录的指标名称。所有指标都带有 provider、model 与 operation 标签。
+const (
+	MetricRequests          = "modelbridge_requests_total"              // 调用次数
+	MetricRequestErrors     = "modelbridge_request_errors_total"        // 失败的调用次数，带有 code 标签 (SDK 错误代码)
+	MetricRequestDuration   = "modelbridge_request_duration_seconds"    // 调用的端到端耗时，包含重试
+	MetricTimeToFirstToken  = "modelbridge_time_to_first_token_seconds" // 流式调用从向平台发起请求 (最后一次尝试) 到收到第一个文本块的时间
+	MetricInterTokenLatency = "modelbridge_inter_token_latency_seconds" // 流式调用中相邻文本块的间隔
+	MetricTokens            = "modelbridge_tokens_total"                // Token 用量，带有 type 标签 (prompt 或 completion)
+)
+
+// WithMetrics 开启指标记录。使用 metrics.NewRegistry() 时可以通过其 ServeHTTP 以 Prometheus 文本格式导出；
+// 多个客户端可以共用同一个 Metrics。流式输出的间隔按平台返回的文本块计算，一个块可能包含多个 Token。
+func WithMetrics(m metrics.Metrics) Option {
+	return func(c *Client) error {
+		if m == nil {
+			return stderrors.New("metrics cannot be nil")
+		}
+		labels := []string{"provider", "model", "operation"}
+		c.metrics = &clientMetrics{
+			requests:          m.Counter(MetricRequests, "Number of model calls.", labels...),
+			errors:            m.Counter(MetricRequestErrors, "Number of failed model calls by SDK error code.", append(labels, "code")...),
+			duration:          m.Histogram(MetricRequestDuration, "End-to-end latency of model calls in seconds, including retries.", metrics.LatencyBuckets, labels...),
+			timeToFirstToken:  m.Histogram(MetricTimeToFirstToken, "Time from the provider request of a streaming call to its first text chunk in seconds, excluding guardrails and failed attempts.", metrics.LatencyBuckets, labels...),
+			interTokenLatency: m.Histogram(MetricInterTokenLatency, "Interval between consecutive text chunks of a streaming call in seconds.", metrics.TokenLatencyBuckets, labels...),
+			tokens:            m.Counter(MetricTokens, "Number of tokens reported by the platform.", append(labels, "type")...),
+		}
+		return nil
+	}
+}
+
+// clientMetrics 是客户端使用的指标。
+type clientMetrics struct {
+	requests          metrics.Counter
+	errors            metrics.Counter
+	duration          metrics.Histogram
+	timeToFirstToken  metrics.Histogram
+	interTokenLatency metrics.Histogram
+	tokens            metrics.Counter
+}
+
+// observeCall 记录一次调用结束时的指标。
+func (m *clientMetrics) observeCall(provider, model, operation string, latency time.Duration, usage models.Usage, err error) {
+	m.requests.Add(1, provider, model, operation)
+	m.duration.Observe(latency.Seconds(), provider, model, operation)
+	if err != nil {
+		m.errors.Add(1, provider, model, operation, errorCode(err))
+	}
+	if usage.PromptTokens > 0 {
+		m.tokens.Add(float64(usage.PromptTokens), provider, model, operation, "prompt")
+	}
+	if usage.CompletionTokens > 0 {
+		m.tokens.Add(float64(usage.CompletionTokens), provider, model, operation, "completion")
+	}
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/modelbridge/config"
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/metrics"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// recordingMetrics 记录每个指标收到的值，键为指标名称与以逗号连接的标签值。
+type recordingMetrics struct {
+	mu     sync.Mutex
+	values map[string][]float64
+}
+
+type recordingInstrument struct {
+	m    *recordingMetrics
+	name string
+}
+
+func (i recordingInstrument) Add(value float64, labelValues ...string) {
+	i.record(value, labelValues)
+}
+
+func (i recordingInstrument) Observe(value float64, labelValues ...string) {
+	i.record(value, labelValues)
+}
+
+func (i recordingInstrument) record(value float64, labelValues []string) {
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	key := i.name + "{" + strings.Join(labelValues, ",") + "}"
+	i.m.values[key] = append(i.m.values[key], value)
+}
+
+func (m *recordingMetrics) Counter(name, help string, labelNames ...string) metrics.Counter {
+	return recordingInstrument{m: m, name: name}
+}
+
+func (m *recordingMetrics) Histogram(name, help string, buckets []float64, labelNames ...string) metrics.Histogram {
+	return recordingInstrument{m: m, name: name}
+}
+
+func (m *recordingMetrics) get(key string) []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[key]
+}
+
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestStreamingMetrics(t *testing.T) {
+	const slow = 100 * time.Millisecond
+	attempts := 0
+	h := &fakeHandler{
+		textGeneration: func(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+			attempts++
+			if attempts == 1 {
+				// 失败的尝试不计入首个文本块的时间。
+				time.Sleep(slow)
+				return nil, errors.New(errors.ErrCodePlatformError, "upstream reset")
+			}
+			for _, delta := range []string{"a", "b", "c"} {
+				if err := req.OnStreamChunk(&models.TextGenerationStreamChunk{Delta: delta}); err != nil {
+					return nil, err
+				}
+			}
+			// 只携带用量的最后一块不参与间隔计算。
+			usage := models.Usage{PromptTokens: 5, CompletionTokens: 3, TotalTokens: 8}
+			if err := req.OnStreamChunk(&models.TextGenerationStreamChunk{IsFinal: true, FinishReason: models.FinishReasonStop, Usage: &usage}); err != nil {
+				return nil, err
+			}
+			return &models.TextGenerationResponse{GeneratedText: "abc", FinishReason: models.FinishReasonStop, TokenUsage: usage}, nil
+		},
+		moderation: func(ctx context.Context, req *models.ModerationRequest) (*models.ModerationResponse, error) {
+			// 护栏审核同样不计入首个文本块的时间。
+			time.Sleep(slow)
+			return &models.ModerationResponse{Results: make([]models.ModerationResult, len(req.Input))}, nil
+		},
+	}
+	m := &recordingMetrics{values: make(map[string][]float64)}
+	c := newFakeClient(t, h, WithMetrics(m), WithGuardrail(Guardrail{ModerateInput: true}),
+		WithRetryPolicy(config.RetryPolicy{MaxAttempts: 2}), WithModelAliases(map[string]string{"chat": "doubao-pro"}))
+
+	_, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{
+		Model: "chat", Prompt: "hi",
+		OnStreamChunk: func(*models.TextGenerationStreamChunk) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+
+	labels := "volcengine,doubao-pro,TextGeneration"
+	if got := m.get(MetricRequests + "{" + labels + "}"); sum(got) != 1 {
+		t.Errorf("requests = %v, want 1", got)
+	}
+	if got := m.get(MetricRequestDuration + "{" + labels + "}"); len(got) != 1 || got[0] < (2*slow).Seconds() {
+		t.Errorf("duration = %v, want one observation including guardrail and retry", got)
+	}
+	ttft := m.get(MetricTimeToFirstToken + "{" + labels + "}")
+	if len(ttft) != 1 || ttft[0] >= slow.Seconds() {
+		t.Errorf("time to first token = %v, want one observation below %v", ttft, slow)
+	}
+	if got := m.get(MetricInterTokenLatency + "{" + labels + "}"); len(got) != 2 {
+		t.Errorf("inter-token latency = %v, want 2 observations for 3 text chunks", got)
+	}
+	if got := m.get(MetricTokens + "{" + labels + ",prompt}"); sum(got) != 5 {
+		t.Errorf("prompt tokens = %v, want 5", got)
+	}
+	if got := m.get(MetricTokens + "{" + labels + ",completion}"); sum(got) != 3 {
+		t.Errorf("completion tokens = %v, want 3", got)
+	}
+	if got := m.get(MetricRequestErrors + "{" + labels + "," + errors.ErrCodePlatformError + "}"); len(got) != 0 {
+		t.Errorf("errors = %v, want none for a call that succeeded after a retry", got)
+	}
+	// 护栏的审核调用按自己的 operation 记录。
+	if got := m.get(MetricRequests + "{volcengine,,Moderation}"); sum(got) != 1 {
+		t.Errorf("moderation requests = %v, want 1", got)
+	}
+}
+
+func TestErrorMetrics(t *testing.T) {
+	h := &fakeHandler{textGeneration: func(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+		if req.Model == "limited" {
+			return nil, errors.New(errors.ErrCodeRateLimited, "slow down")
+		}
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "bad prompt")
+	}}
+	m := &recordingMetrics{values: make(map[string][]float64)}
+	c := newFakeClient(t, h, WithMetrics(m))
+	for _, model := range []string{"limited", "limited", "strict"} {
+		c.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: model, Prompt: "hi"})
+	}
+
+	tests := []struct {
+		key  string
+		want float64
+	}{
+		{MetricRequests + "{volcengine,limited,TextGeneration}", 2},
+		{MetricRequestErrors + "{volcengine,limited,TextGeneration," + errors.ErrCodeRateLimited + "}", 2},
+		{MetricRequests + "{volcengine,strict,TextGeneration}", 1},
+		{MetricRequestErrors + "{volcengine,strict,TextGeneration," + errors.ErrCodeInvalidRequest + "}", 1},
+		{MetricRequestErrors + "{volcengine,strict,TextGeneration," + errors.ErrCodeRateLimited + "}", 0},
+	}
+	for _, tt := range tests {
+		if got := sum(m.get(tt.key)); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+	// 失败的调用没有用量与流式指标。
+	for key := range m.values {
+		if strings.HasPrefix(key, MetricTokens) || strings.HasPrefix(key, MetricTimeToFirstToken) {
+			t.Errorf("unexpected metric %s", key)
+		}
+	}
+}
+
+func TestMetricsRegistryExport(t *testing.T) {
+	reg := metrics.NewRegistry()
+	h := &fakeHandler{textGeneration: func(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+		return &models.TextGenerationResponse{GeneratedText: "ok", TokenUsage: models.Usage{PromptTokens: 4, CompletionTokens: 1, TotalTokens: 5}}, nil
+	}}
+	// 多个客户端共用一个 Registry。
+	for i := 0; i < 2; i++ {
+		c := newFakeClient(t, h, WithMetrics(reg))
+		if _, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: "hi"}); err != nil {
+			t.Fatalf("TextGeneration: %v", err)
+		}
+	}
+	var out strings.Builder
+	if err := reg.WritePrometheus(&out); err != nil {
+		t.Fatalf("WritePrometheus: %v", err)
+	}
+	for _, want := range []string{
+		`modelbridge_requests_total{provider="volcengine",model="m",operation="TextGeneration"} 2`,
+		`modelbridge_tokens_total{provider="volcengine",model="m",operation="TextGeneration",type="prompt"} 8`,
+		`modelbridge_request_duration_seconds_count{provider="volcengine",model="m",operation="TextGeneration"} 2`,
+		`modelbridge_request_duration_seconds_bucket{provider="volcengine",model="m",operation="TextGeneration",le="+Inf"} 2`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("export lacks %s:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// client/redact.go
+package client
+
+import (
+	"fmt"
+
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/redact"
+)
+
+// logTextLimit 是日志中输出请求文本的最大字节数。
+const logTextLimit = 30
+
+// WithRedactor 设置识别敏感内容的 Redactor，默认为 redact.Default()。
+// 日志中的请求文本总是先脱敏再截断；传入空的 redact.Rules{} 可以关闭日志脱敏。
+// 同时设置 WithPromptRedaction 时，发送给模型的内容也使用此 Redactor 识别。
+func WithRedactor(r redact.Redactor) Option {
+	return func(c *Client) error {
+		if r == nil {
+			return fmt.Errorf("redactor cannot be nil")
+		}
+		c.redactor = r
+		return nil
+	}
+}
+
+// WithPromptRedaction 在 TextGeneration 发送请求前将 Prompt 与文本内容中的敏感内容替换为占位符 (例如 [EMAIL_1])，
+// 并在响应 (包括流式块) 中将模型原样输出的占位符还原为原文，使原文不会发送给平台。
+// 模型改写占位符时无法还原。同时启用护栏时，护栏的规则与审核检查的是替换后的内容。其他操作 (例如 Embedding) 不受影响。
+func WithPromptRedaction() Option {
+	return func(c *Client) error {
+		c.redactPrompts = true
+		return nil
+	}
+}
+
+// logText 返回脱敏并截断后的文本，用于日志输出。
+func (c *Client) logText(s string) string {
+	r := c.redactor
+	if r == nil {
+		r = redact.Default()
+	}
+	return truncateForLog(redact.String(r, s), logTextLimit)
+}
+
+// tokenizeInput 将 req 的 Prompt 与文本内容替换为占位符，返回用于还原的 Vault。req.Parts 会被复制后修改。
+func (c *Client) tokenizeInput(req *models.TextGenerationRequest) *redact.Vault {
+	r := c.redactor
+	if r == nil {
+		r = redact.Default()
+	}
+	vault := redact.NewVault()
+	req.Prompt = vault.Tokenize(r, req.Prompt)
+	if len(req.Parts) > 0 {
+		parts := make([]models.ContentPart, len(req.Parts))
+		copy(parts, req.Parts)
+		for i := range parts {
+			if parts[i].Type == models.ContentPartText {
+				parts[i].Text = vault.Tokenize(r, parts[i].Text)
+			}
+		}
+		req.Parts = parts
+	}
+	return vault
+}
+
+// restoringStream 包装 onChunk，在流式块中还原占位符。每个候选结果的正文与思考过程各使用独立的 StreamRestorer，
+// 最终块会带上同一候选结果暂存的剩余内容，其他候选结果的剩余内容在最终块之前以单独的块输出。
+func restoringStream(vault *redact.Vault, onChunk func(*models.TextGenerationStreamChunk) error) func(*models.TextGenerationStreamChunk) error {
+	type choiceRestorer struct {
+		delta, reasoning *redact.StreamRestorer
+	}
+	restorers := make(map[int]*choiceRestorer)
+	var order []int
+	return func(chunk *models.TextGenerationStreamChunk) error {
+		cr, ok := restorers[chunk.Index]
+		if !ok {
+			cr = &choiceRestorer{delta: vault.NewStreamRestorer(), reasoning: vault.NewStreamRestorer()}
+			restorers[chunk.Index] = cr
+			order = append(order, chunk.Index)
+		}
+		restored := *chunk
+		restored.Delta = cr.delta.Write(chunk.Delta)
+		restored.Reasoning = cr.reasoning.Write(chunk.Reasoning)
+		if chunk.IsFinal {
+			restored.Delta += cr.delta.Flush()
+			restored.Reasoning += cr.reasoning.Flush()
+			for _, index := range order {
+				rest := &models.TextGenerationStreamChunk{ID: chunk.ID, Index: index, Delta: restorers[index].delta.Flush(), Reasoning: restorers[index].reasoning.Flush()}
+				if rest.Delta == "" && rest.Reasoning == "" {
+					continue
+				}
+				if err := onChunk(rest); err != nil {
+					return err
+				}
+			}
+		}
+		return onChunk(&restored)
+	}
+}
+
+// restoreOutput 将响应中的占位符还原为原文。
+func restoreOutput(vault *redact.Vault, resp *models.TextGenerationResponse) {
+	if len(resp.Choices) == 0 {
+		resp.GeneratedText = vault.Restore(resp.GeneratedText)
+		resp.Reasoning = vault.Restore(resp.Reasoning)
+		return
+	}
+	for i := range resp.Choices {
+		resp.Choices[i].Text = vault.Restore(resp.Choices[i].Text)
+		resp.Choices[i].Reasoning = vault.Restore(resp.Choices[i].Reasoning)
+	}
+	resp.SetChoices(resp.Choices)
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/redact"
+)
+
+func TestRestoringStream(t *testing.T) {
+	vault := redact.NewVault()
+	vault.Tokenize(redact.Default(), "a@example.com 13812345678")
+
+	type key struct {
+		index     int
+		reasoning bool
+	}
+	got := make(map[key]string)
+	onChunk := restoringStream(vault, func(chunk *models.TextGenerationStreamChunk) error {
+		got[key{chunk.Index, false}] += chunk.Delta
+		got[key{chunk.Index, true}] += chunk.Reasoning
+		return nil
+	})
+	// 占位符在两个候选结果与思考过程中交错地跨块拆分。
+	chunks := []*models.TextGenerationStreamChunk{
+		{Index: 0, Reasoning: "user is [EMA", Delta: "mail [EMAIL"},
+		{Index: 1, Delta: "call [PHO"},
+		{Index: 0, Reasoning: "IL_1]", Delta: "_1] now"},
+		{Index: 1, Delta: "NE_1", Reasoning: "phone [PHONE_"},
+		{Index: 1, Delta: "] ok [EMAIL_"},
+		{Index: 0, Delta: " bye", IsFinal: true},
+	}
+	for _, c := range chunks {
+		if err := onChunk(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[key]string{
+		{0, false}: "mail a@example.com now bye",
+		{0, true}:  "user is a@example.com",
+		{1, false}: "call 13812345678 ok [EMAIL_",
+		{1, true}:  "phone [PHONE_",
+	}
+	for k, w := range want {
+		if got[k] != w {
+			t.Errorf("choice %d reasoning=%v = %q, want %q", k.index, k.reasoning, got[k], w)
+		}
+	}
+}
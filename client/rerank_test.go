@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+func TestRerankProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/rerank" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"id":"r1","results":[{"index":1,"relevance_score":0.8},{"index":0,"relevance_score":0.2}]}`)
+	}))
+	defer server.Close()
+
+	c, err := NewClient(&platform.PlatformConfig{
+		Provider:       platform.ProviderRerank,
+		SpecificConfig: platform.ProviderSettings{BaseURL: server.URL + "/v1"},
+	}, WithSlogHandler(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	ctx := context.Background()
+	resp, err := c.Rerank(ctx, &models.RerankRequest{Model: "bge-reranker", Query: "q", Documents: []string{"a", "b"}, TopN: 1})
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Index != 1 {
+		t.Errorf("results = %+v", resp.Results)
+	}
+
+	// 排序平台不提供其他操作。
+	if _, err := c.TextGeneration(ctx, &models.TextGenerationRequest{Model: "m", Prompt: "hi"}); !errors.IsSDKError(err, errors.ErrCodeUnsupported) {
+		t.Errorf("text generation: err = %v, want %s", err, errors.ErrCodeUnsupported)
+	}
+	if _, err := NewClient(&platform.PlatformConfig{Provider: platform.ProviderRerank}, WithSlogHandler(slog.NewTextHandler(io.Discard, nil))); !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("missing base URL: err = %v, want %s", err, errors.ErrCodeConfiguration)
+	}
+}
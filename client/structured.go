@@ -0,0 +1,164 @@
+// client/structured.go
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/jsonschema"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// JSONOption 是用于配置 GenerateJSON 的函数选项类型。
+type JSONOption func(*jsonOptions)
+
+type jsonOptions struct {
+	schemaName     string
+	description    string
+	strict         bool
+	repairAttempts int
+	jsonObjectMode bool
+}
+
+// WithSchemaName 设置传递给平台的 Schema 名称，默认使用类型名。
+func WithSchemaName(name string) JSONOption {
+	return func(o *jsonOptions) {
+		o.schemaName = name
+	}
+}
+
+// WithSchemaDescription 设置 Schema 的用途说明，帮助模型理解输出含义。
+func WithSchemaDescription(description string) JSONOption {
+	return func(o *jsonOptions) {
+		o.description = description
+	}
+}
+
+// WithStrictSchema 要求平台严格遵循 Schema (如果平台支持)。
+func WithStrictSchema() JSONOption {
+	return func(o *jsonOptions) {
+		o.strict = true
+	}
+}
+
+// WithRepairAttempts 设置输出无法解析或不符合 Schema 时重新询问模型的次数，默认为 0 (不重新询问)。
+func WithRepairAttempts(n int) JSONOption {
+	return func(o *jsonOptions) {
+		o.repairAttempts = n
+	}
+}
+
+// WithJSONObjectMode 使用 json_object 输出格式并将 Schema 写入提示中，
+// 适用于不支持 json_schema 输出格式的模型。
+func WithJSONObjectMode() JSONOption {
+	return func(o *jsonOptions) {
+		o.jsonObjectMode = true
+	}
+}
+
+// GenerateJSON 让模型输出符合类型 T 的 JSON，并将其解析为 T。
+// Schema 由 jsonschema.For[T] 根据结构体字段推导；模型输出会先按 Schema 校验，
+// 校验失败时按 WithRepairAttempts 的设置附带错误信息重新询问模型。
+// 最终仍无法得到合法输出时返回 ErrInvalidResponse 错误，同时返回最后一次的原始响应便于排查。
+func GenerateJSON[T any](ctx context.Context, c *Client, req *models.TextGenerationRequest, opts ...JSONOption) (*T, *models.TextGenerationResponse, error) {
+	if req == nil {
+		return nil, nil, errors.New(errors.ErrCodeInvalidRequest, "text generation request cannot be nil")
+	}
+
+	o := jsonOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.repairAttempts < 0 {
+		return nil, nil, errors.New(errors.ErrCodeInvalidRequest, "repair attempts cannot be negative")
+	}
+
+	schema := jsonschema.For[T]()
+	if o.schemaName == "" {
+		o.schemaName = schemaNameFor(reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	base := *req
+	if o.jsonObjectMode {
+		schemaJSON, err := json.Marshal(schema)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, "failed to marshal JSON schema")
+		}
+		base.Prompt = fmt.Sprintf("%s\n\nRespond with only a JSON object that conforms to this JSON Schema:\n%s", req.Prompt, schemaJSON)
+		base.ResponseFormat = &models.ResponseFormat{Type: models.ResponseFormatJSONObject}
+	} else {
+		base.ResponseFormat = &models.ResponseFormat{
+			Type: models.ResponseFormatJSONSchema,
+			JSONSchema: &models.JSONSchemaFormat{
+				Name:        o.schemaName,
+				Description: o.description,
+				Schema:      schema,
+				Strict:      o.strict,
+			},
+		}
+	}
+
+	attempt := base
+	for i := 0; ; i++ {
+		resp, err := c.TextGeneration(ctx, &attempt)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		raw := extractJSON(resp.GeneratedText)
+		validationErr := jsonschema.ValidateJSON(schema, []byte(raw))
+		if validationErr == nil {
+			var out T
+			if validationErr = json.Unmarshal([]byte(raw), &out); validationErr == nil {
+				return &out, resp, nil
+			}
+		}
+
+		if i >= o.repairAttempts {
+			sdkErr := errors.Wrap(validationErr, errors.ErrCodeInvalidResponse, "model output does not match the expected JSON schema")
+			sdkErr.PlatformDetails = map[string]interface{}{"output": resp.GeneratedText, "attempts": i + 1}
+			return nil, resp, sdkErr
+		}
+
+		c.log.WarnContext(ctx, "GenerateJSON produced invalid output, asking the model to repair it",
+			"attempt", i+1, "max_attempts", o.repairAttempts+1, "error", validationErr)
+		attempt.Prompt = fmt.Sprintf("%s\n\nYour previous answer was:\n%s\n\nIt is invalid: %v\nRespond again with only valid JSON that fixes these problems.", base.Prompt, resp.GeneratedText, validationErr)
+	}
+}
+
+var codeFencePattern = regexp.MustCompile("(?s)^```[a-zA-Z]*\\s*(.*?)\\s*```$")
+
+// extractJSON 去掉模型输出中常见的 Markdown 代码块与前后说明文字。
+func extractJSON(text string) string {
+	text = strings.TrimSpace(text)
+	if m := codeFencePattern.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	if strings.HasPrefix(text, "{") || strings.HasPrefix(text, "[") {
+		return text
+	}
+	start, end := strings.IndexAny(text, "{["), strings.LastIndexAny(text, "}]")
+	if start >= 0 && end > start {
+		return text[start : end+1]
+	}
+	return text
+}
+
+var schemaNamePattern = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// schemaNameFor 根据类型名生成 Schema 名称，匿名类型使用 "response"。
+func schemaNameFor(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	name := schemaNamePattern.ReplaceAllString(t.Name(), "_")
+	if name == "" {
+		return "response"
+	}
+	return name
+}
@@ -0,0 +1,143 @@
+// client/trace.go
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/tracing"
+)
+
+// WithTracer 开启追踪：每次客户端调用、每次重试尝试与每个 HTTP 请求各创建一个 Span，
+// 模型调用的 Span 带有 GenAI 语义约定属性，追踪上下文通过 tracer.Inject 写入发往平台的请求头。
+// 测试中可以使用 tracing.NewRecorder() 检查生成的 Span。
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(c *Client) error {
+		if tracer == nil {
+			return stderrors.New("tracer cannot be nil")
+		}
+		c.tracer = tracer
+		return nil
+	}
+}
+
+// genAIOperations 是客户端操作对应的 gen_ai.operation.name，语义约定未定义的操作使用 SDK 自己的名称。
+var genAIOperations = map[string]string{
+	"TextGeneration":     "chat",
+	"Embedding":          "embeddings",
+	"EmbedAll":           "embeddings",
+	"ImageGeneration":    "image_generation",
+	"Rerank":             "rerank",
+	"TextToSpeech":       "text_to_speech",
+	"AudioTranscription": "audio_transcription",
+	"Moderation":         "moderation",
+	"CreateContextCache": "create_context_cache",
+	"CreateBatchJob":     "create_batch_job",
+}
+
+// noopSpan 是未开启追踪时使用的 Span。
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...tracing.Attribute) {}
+func (noopSpan) End(error)                          {}
+
+// startSpan 在开启追踪时创建 Span，否则返回原 ctx 与 noopSpan。
+func (c *Client) startSpan(ctx context.Context, name string, kind tracing.SpanKind, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return c.tracer.Start(ctx, name, kind, attrs...)
+}
+
+// startCallSpan 创建一次模型调用的 Span，名称按语义约定为 "{operation} {model}"。
+func (c *Client) startCallSpan(ctx context.Context, operation, model string) (context.Context, tracing.Span) {
+	name, ok := genAIOperations[operation]
+	if !ok {
+		name = operation
+	}
+	spanName := name
+	if model != "" {
+		spanName += " " + model
+	}
+	return c.startSpan(ctx, spanName, tracing.SpanKindClient,
+		tracing.String(tracing.AttrGenAISystem, c.provider),
+		tracing.String(tracing.AttrGenAIOperationName, name),
+		tracing.String(tracing.AttrGenAIRequestModel, model),
+	)
+}
+
+// traceHTTPRequest 为 HTTP 请求创建 Span 并注入追踪上下文，Span 在 traceHTTPResponse 中结束。
+func (c *Client) traceHTTPRequest(ctx context.Context, req *http.Request) {
+	if c.tracer == nil {
+		return
+	}
+	ctx, span := c.tracer.Start(ctx, "HTTP "+req.Method, tracing.SpanKindClient,
+		tracing.String(tracing.AttrHTTPRequestMethod, req.Method),
+		tracing.String(tracing.AttrURLFull, req.URL.Redacted()),
+	)
+	c.tracer.Inject(ctx, req.Header)
+	c.httpSpans.Store(req, span)
+}
+
+// traceHTTPResponse 结束 req 对应的 Span。
+func (c *Client) traceHTTPResponse(req *http.Request, statusCode int, requestID string, err error) {
+	v, ok := c.httpSpans.LoadAndDelete(req)
+	if !ok {
+		return
+	}
+	span := v.(tracing.Span)
+	if statusCode != 0 {
+		span.SetAttributes(tracing.Int(tracing.AttrHTTPResponseStatus, statusCode))
+	}
+	if requestID != "" {
+		span.SetAttributes(tracing.String(tracing.AttrRequestID, requestID))
+	}
+	if err != nil {
+		span.SetAttributes(tracing.String(tracing.AttrErrorType, errorCode(err)))
+	}
+	span.End(err)
+}
+
+// textGenerationRequestAttrs 返回文本生成请求的 GenAI 属性。
+func textGenerationRequestAttrs(req *models.TextGenerationRequest) []tracing.Attribute {
+	var attrs []tracing.Attribute
+	if req.MaxTokens > 0 {
+		attrs = append(attrs, tracing.Int(tracing.AttrGenAIRequestMaxTokens, req.MaxTokens))
+	}
+	if req.Temperature != nil {
+		attrs = append(attrs, tracing.Float64(tracing.AttrGenAIRequestTemperature, float64(*req.Temperature)))
+	}
+	if req.TopP != nil {
+		attrs = append(attrs, tracing.Float64(tracing.AttrGenAIRequestTopP, float64(*req.TopP)))
+	}
+	return attrs
+}
+
+// textGenerationResponseAttrs 返回文本生成响应的 GenAI 属性。
+func textGenerationResponseAttrs(resp *models.TextGenerationResponse) []tracing.Attribute {
+	attrs := []tracing.Attribute{tracing.String(tracing.AttrGenAIResponseID, resp.ID)}
+	var reasons []string
+	for _, choice := range resp.Choices {
+		reasons = append(reasons, string(choice.FinishReason))
+	}
+	if len(reasons) == 0 && resp.FinishReason != "" {
+		reasons = append(reasons, string(resp.FinishReason))
+	}
+	if len(reasons) > 0 {
+		attrs = append(attrs, tracing.StringSlice(tracing.AttrGenAIResponseFinishReason, reasons))
+	}
+	return attrs
+}
+
+// usageSpanAttrs 返回 Token 用量的 GenAI 属性，平台未提供用量时返回 nil。
+func usageSpanAttrs(u models.Usage) []tracing.Attribute {
+	if u == (models.Usage{}) {
+		return nil
+	}
+	return []tracing.Attribute{
+		tracing.Int(tracing.AttrGenAIUsageInputTokens, u.PromptTokens),
+		tracing.Int(tracing.AttrGenAIUsageOutputTokens, u.CompletionTokens),
+	}
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/config"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/tracing"
+)
+
+func TestTracingSpanTree(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		calls       int
+		traceparent []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		traceparent = append(traceparent, r.Header.Get(tracing.TraceParentHeader))
+		mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `{"error":{"code":"InternalServiceError","message":"try again"}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id":"chat-1","model":"doubao-pro","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}],`+
+			`"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`)
+	}))
+	defer server.Close()
+
+	recorder := tracing.NewRecorder()
+	c, err := NewClient(testConfig(server.URL),
+		WithSlogHandler(slog.NewTextHandler(io.Discard, nil)),
+		WithTracer(recorder),
+		WithRetryPolicy(config.RetryPolicy{MaxAttempts: 2}),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "doubao-pro", Prompt: "hello"}); err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+
+	spans := recorder.Spans()
+	byID := make(map[string]tracing.RecordedSpan, len(spans))
+	var call tracing.RecordedSpan
+	var attempts, requests []tracing.RecordedSpan
+	for _, s := range spans {
+		byID[s.SpanID] = s
+		switch {
+		case s.ParentSpanID == "":
+			call = s
+		case strings.HasSuffix(s.Name, " attempt"):
+			attempts = append(attempts, s)
+		case strings.HasPrefix(s.Name, "HTTP "):
+			requests = append(requests, s)
+		}
+	}
+	if len(spans) != 5 || len(attempts) != 2 || len(requests) != 2 {
+		t.Fatalf("got %d spans (%d attempts, %d requests), want 1 call, 2 attempts and 2 requests", len(spans), len(attempts), len(requests))
+	}
+
+	// 调用 Span 带有 GenAI 语义约定的属性。
+	if call.Name != "chat doubao-pro" || call.Kind != tracing.SpanKindClient || call.Err != nil {
+		t.Errorf("call span = %q kind %v err %v", call.Name, call.Kind, call.Err)
+	}
+	wantAttrs := map[string]interface{}{
+		tracing.AttrGenAISystem:               "volcengine",
+		tracing.AttrGenAIOperationName:        "chat",
+		tracing.AttrGenAIRequestModel:         "doubao-pro",
+		tracing.AttrGenAIResponseID:           "chat-1",
+		tracing.AttrGenAIResponseFinishReason: []string{"stop"},
+		tracing.AttrGenAIUsageInputTokens:     5,
+		tracing.AttrGenAIUsageOutputTokens:    2,
+	}
+	for k, want := range wantAttrs {
+		if got := call.Attributes[k]; !reflect.DeepEqual(got, want) {
+			t.Errorf("call attribute %s = %#v, want %#v", k, got, want)
+		}
+	}
+
+	// 每次尝试都是调用 Span 的子 Span，HTTP 请求是尝试的子 Span。
+	for i, attempt := range attempts {
+		if attempt.ParentSpanID != call.SpanID || attempt.TraceID != call.TraceID {
+			t.Errorf("attempt %d is not a child of the call span", i+1)
+		}
+		if got := attempt.Attributes[tracing.AttrAttempt]; got != i+1 {
+			t.Errorf("attempt %d has %s = %v", i+1, tracing.AttrAttempt, got)
+		}
+		req := requests[i]
+		if req.ParentSpanID != attempt.SpanID {
+			t.Errorf("request %d parent = %s, want attempt %s", i+1, req.ParentSpanID, attempt.SpanID)
+		}
+		if req.Attributes[tracing.AttrHTTPRequestMethod] != http.MethodPost {
+			t.Errorf("request %d method = %v", i+1, req.Attributes[tracing.AttrHTTPRequestMethod])
+		}
+		// 请求头中的 traceparent 指向对应的 HTTP Span。
+		if want := fmt.Sprintf("00-%s-%s-01", req.TraceID, req.SpanID); traceparent[i] != want {
+			t.Errorf("request %d traceparent = %q, want %q", i+1, traceparent[i], want)
+		}
+	}
+	if attempts[0].Err == nil || requests[0].Attributes[tracing.AttrHTTPResponseStatus] != http.StatusInternalServerError {
+		t.Errorf("first attempt should record the server error, got err %v status %v", attempts[0].Err, requests[0].Attributes[tracing.AttrHTTPResponseStatus])
+	}
+	if attempts[1].Err != nil || requests[1].Attributes[tracing.AttrHTTPResponseStatus] != http.StatusOK {
+		t.Errorf("second attempt should succeed, got err %v status %v", attempts[1].Err, requests[1].Attributes[tracing.AttrHTTPResponseStatus])
+	}
+}
@@ -0,0 +1,455 @@
+// config/config.go
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+// Format 表示配置文件的格式。
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// Config 是多平台配置文件的顶层结构。
+//
+// 一个典型的 YAML 配置如下：
+//
+//	default_provider: volcengine
+//	providers:
+//	  volcengine:
+//	    credentials:
+//	      apiKey: ${ARK_API_KEY}
+//	    region: cn-shanghai
+//	    timeout: 30s
+//	    retry:
+//	      max_attempts: 3
+//	      initial_backoff: 500ms
+//	    model_aliases:
+//	      chat: doubao-1.5-pro-32k-250115
+type Config struct {
+	DefaultProvider string                     `json:"default_provider,omitempty"` // 未指定平台时使用的配置名称
+	Providers       map[string]*ProviderConfig `json:"providers"`                  // 以配置名称为键的平台配置
+}
+
+// ProviderConfig 描述了单个平台的配置。
+type ProviderConfig struct {
+	Provider     platform.Provider `json:"provider,omitempty"`        // 平台提供商，为空时使用配置名称
+	Credentials  map[string]string `json:"credentials"`               // 平台凭证，支持 ${ENV} 与 ${ENV:-default} 形式的环境变量插值
+	Endpoint     string            `json:"endpoint,omitempty"`        // 可选，覆盖平台默认的 API 根地址
+	Region       string            `json:"region,omitempty"`          // 可选，平台区域
+	APIVersion   string            `json:"api_version,omitempty"`     // 可选，API 版本
+	Headers      map[string]string `json:"headers,omitempty"`         // 可选，附加到每个请求上的自定义请求头，支持环境变量插值
+	Proxy        string            `json:"proxy,omitempty"`           // 可选，HTTP 代理地址，支持环境变量插值
+	TLS          *TLSConfig        `json:"tls,omitempty"`             // 可选，TLS 设置，例如企业内部的根证书
+	Pool         *ConnectionPool   `json:"connection_pool,omitempty"` // 可选，HTTP 连接池设置
+	Timeout      Duration          `json:"timeout,omitempty"`         // 可选，单次 HTTP 请求的超时时间
+	Retry        *RetryPolicy      `json:"retry,omitempty"`           // 可选，客户端的重试策略
+	ModelAliases map[string]string `json:"model_aliases,omitempty"`   // 可选，通用别名到平台模型 ID 的映射
+}
+
+// TLSConfig 描述了平台请求使用的 TLS 设置，文件路径支持环境变量插值。
+type TLSConfig struct {
+	CAFile             string `json:"ca_file,omitempty"`              // PEM 格式的根证书文件，为空时使用系统根证书
+	CertFile           string `json:"cert_file,omitempty"`            // PEM 格式的客户端证书文件，需与 key_file 同时设置
+	KeyFile            string `json:"key_file,omitempty"`             // PEM 格式的客户端私钥文件
+	ServerName         string `json:"server_name,omitempty"`          // 覆盖用于校验服务端证书的主机名
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"` // 跳过服务端证书校验，仅用于测试环境
+}
+
+// ConnectionPool 描述了平台请求使用的 HTTP 连接池设置，零值字段表示沿用默认设置。
+type ConnectionPool struct {
+	MaxIdleConns        int      `json:"max_idle_conns,omitempty"`          // 所有主机的最大空闲连接数
+	MaxIdleConnsPerHost int      `json:"max_idle_conns_per_host,omitempty"` // 每个主机的最大空闲连接数
+	MaxConnsPerHost     int      `json:"max_conns_per_host,omitempty"`      // 每个主机的最大连接数 (包含活跃连接)
+	IdleConnTimeout     Duration `json:"idle_conn_timeout,omitempty"`       // 空闲连接的保持时间
+}
+
+// RetryPolicy 定义了客户端对可重试错误的重试策略。
+type RetryPolicy struct {
+	MaxAttempts    int      `json:"max_attempts"`              // 最大尝试次数 (包含首次请求)，小于等于 1 表示不重试
+	InitialBackoff Duration `json:"initial_backoff,omitempty"` // 首次重试前的等待时间
+	MaxBackoff     Duration `json:"max_backoff,omitempty"`     // 单次等待时间的上限，零值表示不设上限
+	Multiplier     float64  `json:"multiplier,omitempty"`      // 每次重试后等待时间的增长倍数，零值视为 2
+}
+
+// Backoff 返回第 attempt 次重试 (从 1 开始) 前应等待的时间。
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	backoff := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		backoff *= multiplier
+		if p.MaxBackoff > 0 && backoff >= float64(p.MaxBackoff) {
+			return time.Duration(p.MaxBackoff)
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		return time.Duration(p.MaxBackoff)
+	}
+	return time.Duration(backoff)
+}
+
+// Duration 是可以从 "30s"、"500ms" 这类带单位的字符串解析的 time.Duration。
+// 没有单位的数字 (例如 YAML 中的 timeout: 30) 含义不明确，会被拒绝。
+type Duration time.Duration
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口。
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case float64:
+		return fmt.Errorf("invalid duration %s: a unit is required, e.g. \"%ss\"", string(data), string(data))
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		*d = Duration(parsed)
+	default:
+		return fmt.Errorf("invalid duration %s", string(data))
+	}
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler 接口。
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Load 从指定路径加载配置文件，根据扩展名 (.yaml/.yml/.json) 判断格式，
+// 完成环境变量插值后进行校验。
+func Load(path string) (*Config, error) {
+	var format Format
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	case ".json":
+		format = FormatJSON
+	default:
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("config: unsupported config file extension %q (expected .yaml, .yml or .json)", filepath.Ext(path)))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfiguration, fmt.Sprintf("config: failed to read config file %s", path))
+	}
+
+	cfg, err := Parse(data, format)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Parse 解析给定格式的配置内容，完成环境变量插值后进行校验。
+func Parse(data []byte, format Format) (*Config, error) {
+	jsonData := data
+	switch format {
+	case FormatJSON:
+	case FormatYAML:
+		// YAML 先解码为通用结构再转换为 JSON，这样结构体只需维护一套 json 标签。
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeConfiguration, "config: failed to parse YAML")
+		}
+		converted, err := json.Marshal(raw)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeConfiguration, "config: failed to convert YAML to JSON")
+		}
+		jsonData = converted
+	default:
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("config: unsupported config format %q", format))
+	}
+
+	// 拒绝未知字段，避免拼写错误的配置项 (例如 retyr) 被静默忽略。
+	var cfg Config
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfiguration, "config: failed to decode config")
+	}
+
+	var issues []string
+	for _, name := range cfg.providerNames() {
+		p := cfg.Providers[name]
+		if p == nil {
+			continue
+		}
+		for key, value := range p.Credentials {
+			expanded, missing := expandEnv(value)
+			for _, env := range missing {
+				issues = append(issues, fmt.Sprintf("providers.%s.credentials.%s: environment variable %s is not set", name, key, env))
+			}
+			p.Credentials[key] = expanded
+		}
+		for key, value := range p.Headers {
+			expanded, missing := expandEnv(value)
+			for _, env := range missing {
+				issues = append(issues, fmt.Sprintf("providers.%s.headers.%s: environment variable %s is not set", name, key, env))
+			}
+			p.Headers[key] = expanded
+		}
+		expanded, missing := expandEnv(p.Endpoint)
+		for _, env := range missing {
+			issues = append(issues, fmt.Sprintf("providers.%s.endpoint: environment variable %s is not set", name, env))
+		}
+		p.Endpoint = expanded
+		expanded, missing = expandEnv(p.Proxy)
+		for _, env := range missing {
+			issues = append(issues, fmt.Sprintf("providers.%s.proxy: environment variable %s is not set", name, env))
+		}
+		p.Proxy = expanded
+		if t := p.TLS; t != nil {
+			for field, value := range map[string]*string{"ca_file": &t.CAFile, "cert_file": &t.CertFile, "key_file": &t.KeyFile} {
+				expanded, missing := expandEnv(*value)
+				for _, env := range missing {
+					issues = append(issues, fmt.Sprintf("providers.%s.tls.%s: environment variable %s is not set", name, field, env))
+				}
+				*value = expanded
+			}
+		}
+	}
+	if len(issues) > 0 {
+		return nil, newValidationError(issues)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate 校验配置的完整性，所有问题会合并到同一个错误中返回。
+func (c *Config) Validate() error {
+	var issues []string
+	if len(c.Providers) == 0 {
+		issues = append(issues, "providers: at least one provider must be configured")
+	}
+	if c.DefaultProvider != "" {
+		if _, ok := c.Providers[c.DefaultProvider]; !ok {
+			issues = append(issues, fmt.Sprintf("default_provider: provider %q is not defined in providers", c.DefaultProvider))
+		}
+	} else if len(c.Providers) > 1 {
+		issues = append(issues, "default_provider: must be set when more than one provider is configured")
+	}
+
+	for _, name := range c.providerNames() {
+		p := c.Providers[name]
+		prefix := "providers." + name
+		if p == nil {
+			issues = append(issues, prefix+": provider config cannot be empty")
+			continue
+		}
+		if issue := checkProvider(name, p.Provider); issue != "" {
+			issues = append(issues, issue)
+		}
+		if len(p.Credentials) == 0 {
+			issues = append(issues, prefix+".credentials: at least one credential must be set")
+		}
+		for key, value := range p.Credentials {
+			if value == "" {
+				issues = append(issues, fmt.Sprintf("%s.credentials.%s: value cannot be empty", prefix, key))
+			}
+		}
+		if p.Timeout < 0 {
+			issues = append(issues, prefix+".timeout: cannot be negative")
+		}
+		if t := p.TLS; t != nil && (t.CertFile == "") != (t.KeyFile == "") {
+			issues = append(issues, prefix+".tls: cert_file and key_file must be set together")
+		}
+		if pool := p.Pool; pool != nil {
+			if pool.MaxIdleConns < 0 || pool.MaxIdleConnsPerHost < 0 || pool.MaxConnsPerHost < 0 || pool.IdleConnTimeout < 0 {
+				issues = append(issues, prefix+".connection_pool: values cannot be negative")
+			}
+		}
+		if r := p.Retry; r != nil {
+			if r.MaxAttempts < 0 {
+				issues = append(issues, prefix+".retry.max_attempts: cannot be negative")
+			}
+			if r.InitialBackoff < 0 || r.MaxBackoff < 0 {
+				issues = append(issues, prefix+".retry: backoff durations cannot be negative")
+			}
+			if r.MaxBackoff > 0 && r.InitialBackoff > r.MaxBackoff {
+				issues = append(issues, prefix+".retry.initial_backoff: cannot exceed max_backoff")
+			}
+			if r.Multiplier != 0 && r.Multiplier < 1 {
+				issues = append(issues, prefix+".retry.multiplier: must be at least 1")
+			}
+		}
+		for alias, target := range p.ModelAliases {
+			if alias == "" || target == "" {
+				issues = append(issues, fmt.Sprintf("%s.model_aliases: alias %q must map to a non-empty model ID", prefix, alias))
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		return newValidationError(issues)
+	}
+	return nil
+}
+
+// Provider 返回指定名称的平台配置，name 为空时返回默认平台配置。
+func (c *Config) Provider(name string) (*ProviderConfig, error) {
+	if name == "" {
+		name = c.DefaultProvider
+	}
+	if name == "" && len(c.Providers) == 1 {
+		for only := range c.Providers {
+			name = only
+		}
+	}
+	p, ok := c.Providers[name]
+	if !ok || p == nil {
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("config: provider %q is not configured", name))
+	}
+	if p.Provider == "" {
+		p.Provider = platform.Provider(name)
+	}
+	return p, nil
+}
+
+// PlatformConfig 将平台配置转换为 platform.PlatformConfig。
+func (p *ProviderConfig) PlatformConfig() *platform.PlatformConfig {
+	credentials := make(map[string]string, len(p.Credentials))
+	for k, v := range p.Credentials {
+		credentials[k] = v
+	}
+	headers := make(map[string]string, len(p.Headers))
+	for k, v := range p.Headers {
+		headers[k] = v
+	}
+	settings := platform.ProviderSettings{
+		BaseURL:    p.Endpoint,
+		Region:     p.Region,
+		APIVersion: p.APIVersion,
+		Headers:    headers,
+		ProxyURL:   p.Proxy,
+	}
+	if t := p.TLS; t != nil {
+		settings.TLS = platform.TLSSettings(*t)
+	}
+	if pool := p.Pool; pool != nil {
+		settings.ConnectionPool = platform.ConnectionPoolSettings{
+			MaxIdleConns:        pool.MaxIdleConns,
+			MaxIdleConnsPerHost: pool.MaxIdleConnsPerHost,
+			MaxConnsPerHost:     pool.MaxConnsPerHost,
+			IdleConnTimeout:     time.Duration(pool.IdleConnTimeout),
+		}
+	}
+	return &platform.PlatformConfig{
+		Provider:       p.Provider,
+		Credentials:    credentials,
+		Timeout:        time.Duration(p.Timeout),
+		SpecificConfig: settings,
+	}
+}
+
+// FromEnv 从环境变量构建平台配置，用于没有配置文件的场景。
+// 读取 MODELBRIDGE_<PROVIDER>_API_KEY 作为 apiKey 凭证，
+// 并可选地读取 MODELBRIDGE_<PROVIDER>_ENDPOINT、MODELBRIDGE_<PROVIDER>_REGION 与 MODELBRIDGE_<PROVIDER>_TIMEOUT。
+func FromEnv(provider platform.Provider) (*platform.PlatformConfig, error) {
+	prefix := "MODELBRIDGE_" + strings.ToUpper(string(provider)) + "_"
+	apiKey := os.Getenv(prefix + "API_KEY")
+	if apiKey == "" {
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("config: environment variable %sAPI_KEY is not set", prefix))
+	}
+
+	cfg := &platform.PlatformConfig{
+		Provider:    provider,
+		Credentials: map[string]string{"apiKey": apiKey},
+		SpecificConfig: platform.ProviderSettings{
+			BaseURL: os.Getenv(prefix + "ENDPOINT"),
+			Region:  os.Getenv(prefix + "REGION"),
+		},
+	}
+	if raw := os.Getenv(prefix + "TIMEOUT"); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout < 0 {
+			return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("config: environment variable %sTIMEOUT has invalid duration %q", prefix, raw))
+		}
+		cfg.Timeout = timeout
+	}
+	return cfg, nil
+}
+
+// checkProvider 检查平台配置的提供商是否受支持，provider 为空时使用配置名称。
+// 受支持时返回空字符串，否则返回描述问题的信息。
+func checkProvider(name string, provider platform.Provider) string {
+	field := "providers." + name + ".provider"
+	if provider == "" {
+		provider = platform.Provider(name)
+		field = "providers." + name
+	}
+	known := platform.KnownProviders()
+	names := make([]string, len(known))
+	for i, k := range known {
+		if k == provider {
+			return ""
+		}
+		names[i] = string(k)
+	}
+	return fmt.Sprintf("%s: unknown provider %q (expected one of %s)", field, provider, strings.Join(names, ", "))
+}
+
+// providerNames 返回排序后的平台配置名称，保证错误信息的顺序稳定。
+func (c *Config) providerNames() []string {
+	names := make([]string, 0, len(c.Providers))
+	for name := range c.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv 替换 s 中的 ${VAR} 与 ${VAR:-default}，并返回未设置且没有默认值的变量名。
+// 与 shell 一致，${VAR} 接受设置为空字符串的变量，${VAR:-default} 在变量未设置或为空时使用默认值。
+func expandEnv(s string) (string, []string) {
+	var missing []string
+	expanded := envPattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := envPattern.FindStringSubmatch(match)
+		value, ok := os.LookupEnv(groups[1])
+		if ok && value != "" {
+			return value
+		}
+		if groups[2] != "" {
+			return groups[3]
+		}
+		if !ok {
+			missing = append(missing, groups[1])
+		}
+		return ""
+	})
+	return expanded, missing
+}
+
+// newValidationError 将多个校验问题合并为一个配置错误。
+func newValidationError(issues []string) *errors.Error {
+	sort.Strings(issues)
+	err := errors.New(errors.ErrCodeConfiguration, "config: invalid configuration: "+strings.Join(issues, "; "))
+	err.PlatformDetails = map[string]interface{}{"issues": issues}
+	return err
+}
@@ -0,0 +1,362 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+func TestParseYAML(t *testing.T) {
+	t.Setenv("TEST_ARK_API_KEY", "secret")
+	data := []byte(`
+default_provider: ark
+providers:
+  ark:
+    provider: volcengine
+    credentials:
+      apiKey: ${TEST_ARK_API_KEY}
+    region: cn-shanghai
+    timeout: 30s
+    retry:
+      max_attempts: 3
+      initial_backoff: 500ms
+    model_aliases:
+      chat: doubao-1.5-pro-32k-250115
+`)
+	cfg, err := Parse(data, FormatYAML)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	p, err := cfg.Provider("")
+	if err != nil {
+		t.Fatalf("Provider: %v", err)
+	}
+	if p.Provider != platform.ProviderVolcengine {
+		t.Errorf("Provider = %q, want %q", p.Provider, platform.ProviderVolcengine)
+	}
+	if got := p.Credentials["apiKey"]; got != "secret" {
+		t.Errorf("apiKey = %q, want %q", got, "secret")
+	}
+	if time.Duration(p.Timeout) != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", time.Duration(p.Timeout))
+	}
+	if p.Retry == nil || p.Retry.MaxAttempts != 3 || time.Duration(p.Retry.InitialBackoff) != 500*time.Millisecond {
+		t.Errorf("Retry = %+v", p.Retry)
+	}
+	if got := p.ModelAliases["chat"]; got != "doubao-1.5-pro-32k-250115" {
+		t.Errorf("model alias chat = %q", got)
+	}
+
+	pc := p.PlatformConfig()
+	if pc.SpecificConfig.Region != "cn-shanghai" || pc.Timeout != 30*time.Second {
+		t.Errorf("PlatformConfig = %+v", pc)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	data := []byte(`{"providers":{"volcengine":{"credentials":{"apiKey":"k"},"timeout":"1s"}}}`)
+	cfg, err := Parse(data, FormatJSON)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	p, err := cfg.Provider("")
+	if err != nil {
+		t.Fatalf("Provider: %v", err)
+	}
+	if p.Provider != platform.ProviderVolcengine {
+		t.Errorf("Provider = %q, want the config name", p.Provider)
+	}
+	if time.Duration(p.Timeout) != time.Second {
+		t.Errorf("Timeout = %v, want 1s", time.Duration(p.Timeout))
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("TEST_SET", "value")
+	t.Setenv("TEST_EMPTY", "")
+	os.Unsetenv("TEST_UNSET")
+
+	tests := []struct {
+		in      string
+		want    string
+		missing []string
+	}{
+		{in: "plain", want: "plain"},
+		{in: "${TEST_SET}", want: "value"},
+		{in: "a-${TEST_SET}-b", want: "a-value-b"},
+		{in: "${TEST_SET:-fallback}", want: "value"},
+		{in: "${TEST_UNSET:-fallback}", want: "fallback"},
+		{in: "${TEST_EMPTY:-fallback}", want: "fallback"},
+		{in: "${TEST_UNSET:-}", want: ""},
+		{in: "${TEST_UNSET:-http://x:1/y}", want: "http://x:1/y"},
+		{in: "${TEST_UNSET}", want: "", missing: []string{"TEST_UNSET"}},
+		{in: "${TEST_EMPTY}", want: ""},
+		{in: "$TEST_SET", want: "$TEST_SET"},
+	}
+	for _, tt := range tests {
+		got, missing := expandEnv(tt.in)
+		if got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if strings.Join(missing, ",") != strings.Join(tt.missing, ",") {
+			t.Errorf("expandEnv(%q) missing = %v, want %v", tt.in, missing, tt.missing)
+		}
+	}
+}
+
+func TestParseExpandsEnvInAllFields(t *testing.T) {
+	t.Setenv("TEST_TEAM", "search")
+	os.Unsetenv("TEST_ENDPOINT")
+	data := []byte(`
+providers:
+  volcengine:
+    credentials:
+      apiKey: k
+    endpoint: ${TEST_ENDPOINT:-http://127.0.0.1:8080}
+    proxy: ${TEST_PROXY:-}
+    headers:
+      X-Team: ${TEST_TEAM}
+`)
+	cfg, err := Parse(data, FormatYAML)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	p := cfg.Providers["volcengine"]
+	if p.Endpoint != "http://127.0.0.1:8080" {
+		t.Errorf("Endpoint = %q", p.Endpoint)
+	}
+	if p.Proxy != "" {
+		t.Errorf("Proxy = %q, want empty", p.Proxy)
+	}
+	if p.Headers["X-Team"] != "search" {
+		t.Errorf("X-Team = %q", p.Headers["X-Team"])
+	}
+}
+
+func TestParseMissingEnv(t *testing.T) {
+	os.Unsetenv("TEST_MISSING_KEY")
+	data := []byte(`
+providers:
+  volcengine:
+    credentials:
+      apiKey: ${TEST_MISSING_KEY}
+`)
+	_, err := Parse(data, FormatYAML)
+	assertConfigError(t, err, "providers.volcengine.credentials.apiKey: environment variable TEST_MISSING_KEY is not set")
+}
+
+func TestParseEmptyEnv(t *testing.T) {
+	// 设置为空的变量不是缺失的变量，由校验报告空凭证。
+	t.Setenv("TEST_EMPTY_KEY", "")
+	data := []byte(`
+providers:
+  volcengine:
+    credentials:
+      apiKey: ${TEST_EMPTY_KEY}
+`)
+	_, err := Parse(data, FormatYAML)
+	assertConfigError(t, err, "providers.volcengine.credentials.apiKey: value cannot be empty")
+	if strings.Contains(err.Error(), "is not set") {
+		t.Errorf("error = %q, want no missing variable", err)
+	}
+}
+
+func TestParseRejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format Format
+		want   string
+	}{
+		{"yaml typo", "providers:\n  volcengine:\n    credentials: {apiKey: k}\n    retyr: {max_attempts: 3}\n", FormatYAML, `unknown field "retyr"`},
+		{"yaml misplaced key", "base_url: http://x\nproviders:\n  volcengine:\n    credentials: {apiKey: k}\n", FormatYAML, `unknown field "base_url"`},
+		{"json nested", `{"providers":{"volcengine":{"credentials":{"apiKey":"k"},"retry":{"attempts":3}}}}`, FormatJSON, `unknown field "attempts"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), tt.format)
+			assertConfigError(t, err, tt.want)
+		})
+	}
+}
+
+func TestParseDurations(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr string
+	}{
+		{value: "30s", want: 30 * time.Second},
+		{value: "1m30s", want: 90 * time.Second},
+		{value: `"250ms"`, want: 250 * time.Millisecond},
+		{value: "30", wantErr: "a unit is required"},
+		{value: "0", wantErr: "a unit is required"},
+		{value: "soon", wantErr: `invalid duration "soon"`},
+		{value: "[1]", wantErr: "invalid duration"},
+	}
+	for _, tt := range tests {
+		data := []byte("providers:\n  volcengine:\n    credentials: {apiKey: k}\n    timeout: " + tt.value + "\n")
+		cfg, err := Parse(data, FormatYAML)
+		if tt.wantErr != "" {
+			assertConfigError(t, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			t.Errorf("timeout %s: %v", tt.value, err)
+			continue
+		}
+		if got := time.Duration(cfg.Providers["volcengine"].Timeout); got != tt.want {
+			t.Errorf("timeout %s = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{
+			name: "no providers",
+			yaml: `providers: {}`,
+			want: "providers: at least one provider must be configured",
+		},
+		{
+			name: "unknown provider name",
+			yaml: `
+providers:
+  openai:
+    credentials: {apiKey: k}
+`,
+			want: `providers.openai: unknown provider "openai" (expected one of alibaba, rerank, volcengine)`,
+		},
+		{
+			name: "unknown provider field",
+			yaml: `
+providers:
+  ark:
+    provider: volcano
+    credentials: {apiKey: k}
+`,
+			want: `providers.ark.provider: unknown provider "volcano" (expected one of alibaba, rerank, volcengine)`,
+		},
+		{
+			name: "undefined default provider",
+			yaml: `
+default_provider: alibaba
+providers:
+  volcengine:
+    credentials: {apiKey: k}
+`,
+			want: `default_provider: provider "alibaba" is not defined in providers`,
+		},
+		{
+			name: "ambiguous default provider",
+			yaml: `
+providers:
+  volcengine:
+    credentials: {apiKey: k}
+  alibaba:
+    credentials: {apiKey: k}
+`,
+			want: "default_provider: must be set when more than one provider is configured",
+		},
+		{
+			name: "missing credentials",
+			yaml: `
+providers:
+  volcengine:
+    region: cn-beijing
+`,
+			want: "providers.volcengine.credentials: at least one credential must be set",
+		},
+		{
+			name: "bad retry",
+			yaml: `
+providers:
+  volcengine:
+    credentials: {apiKey: k}
+    retry:
+      initial_backoff: 2s
+      max_backoff: 1s
+`,
+			want: "providers.volcengine.retry.initial_backoff: cannot exceed max_backoff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml), FormatYAML)
+			assertConfigError(t, err, tt.want)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "modelbridge.yml")
+	if err := os.WriteFile(path, []byte("providers:\n  volcengine:\n    credentials:\n      apiKey: k\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	_, err := Load(filepath.Join(dir, "modelbridge.toml"))
+	assertConfigError(t, err, "unsupported config file extension")
+}
+
+func assertConfigError(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected error containing %q", want)
+	}
+	if !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("error = %v, want code %s", err, errors.ErrCodeConfiguration)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err.Error(), want)
+	}
+}
+
+func TestParseTransportSettings(t *testing.T) {
+	t.Setenv("TEST_CA_DIR", "/etc/ssl")
+	data := []byte(`
+providers:
+  volcengine:
+    credentials: {apiKey: k}
+    tls:
+      ca_file: ${TEST_CA_DIR}/corp-ca.pem
+      server_name: ark.internal
+    connection_pool:
+      max_idle_conns_per_host: 32
+      max_conns_per_host: 64
+      idle_conn_timeout: 90s
+`)
+	cfg, err := Parse(data, FormatYAML)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	settings := cfg.Providers["volcengine"].PlatformConfig().SpecificConfig
+	if settings.TLS.CAFile != "/etc/ssl/corp-ca.pem" || settings.TLS.ServerName != "ark.internal" {
+		t.Errorf("TLS = %+v", settings.TLS)
+	}
+	want := platform.ConnectionPoolSettings{MaxIdleConnsPerHost: 32, MaxConnsPerHost: 64, IdleConnTimeout: 90 * time.Second}
+	if settings.ConnectionPool != want {
+		t.Errorf("ConnectionPool = %+v, want %+v", settings.ConnectionPool, want)
+	}
+
+	_, err = Parse([]byte(`
+providers:
+  volcengine:
+    credentials: {apiKey: k}
+    tls: {cert_file: client.pem}
+    connection_pool: {max_conns_per_host: -1}
+`), FormatYAML)
+	assertConfigError(t, err, "providers.volcengine.connection_pool: values cannot be negative; providers.volcengine.tls: cert_file and key_file must be set together")
+}
@@ -49,16 +49,18 @@ func Wrap(err error, code, message string) *Error {
 // /////////////////////////////////////////////////////////////////////////////
 
 const (
-	ErrCodeConfiguration  = "ErrConfiguration"
-	ErrCodeAuthentication = "ErrAuthentication"
-	ErrCodeInvalidRequest = "ErrInvalidRequest"
-	ErrCodeNotFound       = "ErrNotFound"
-	ErrCodeRateLimited    = "ErrRateLimited"
-	ErrCodePlatformError  = "ErrPlatformError" // 通用平台错误
-	ErrCodeUnsupported    = "ErrUnsupportedOperation"
-	ErrCodeInternal       = "ErrInternalSDK" // SDK 内部错误
-	ErrCodeTimeout        = "ErrTimeout"
-	ErrCodeCancelled      = "ErrCancelled"
+	ErrCodeConfiguration   = "ErrConfiguration"
+	ErrCodeAuthentication  = "ErrAuthentication"
+	ErrCodeInvalidRequest  = "ErrInvalidRequest"
+	ErrCodeNotFound        = "ErrNotFound"
+	ErrCodeRateLimited     = "ErrRateLimited"
+	ErrCodePlatformError   = "ErrPlatformError" // 通用平台错误
+	ErrCodeUnsupported     = "ErrUnsupportedOperation"
+	ErrCodeInternal        = "ErrInternalSDK" // SDK 内部错误
+	ErrCodeTimeout         = "ErrTimeout"
+	ErrCodeCancelled       = "ErrCancelled"
+	ErrCodeInvalidResponse = "ErrInvalidResponse" // 平台响应无法解析或不符合预期格式
+	ErrCodeContentRefused  = "ErrContentRefused"  // 内容被审核或护栏规则拒绝
 )
 
 // /////////////////////////////////////////////////////////////////////////////
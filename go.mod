@@ -1,3 +1,5 @@
 module github.com/hewenyu/modelbridge
 
 go 1.23.6
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,178 @@
+// jsonschema/schema.go
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema 是 JSON Schema 的一个子集，足以描述结构化输出所需的对象、数组与基本类型。
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"` // false 或 *Schema
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// For 根据类型 T 生成 Schema，规则与 Reflect 相同。
+func For[T any]() *Schema {
+	return ReflectType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect 根据 v 的类型生成 Schema。
+func Reflect(v interface{}) *Schema {
+	return ReflectType(reflect.TypeOf(v))
+}
+
+// ReflectType 根据 Go 类型生成 Schema：
+//   - 字段名取自 json 标签，"-" 的字段被忽略，匿名嵌入的结构体字段会被展开；
+//   - 带 omitempty 的字段与指针字段为可选字段，其余字段为必填字段；
+//   - description 标签作为字段描述，enum 标签 (以 "|" 分隔) 作为可选值，按字段类型转换为数字或布尔值，
+//     切片字段的 enum 约束数组元素，无法转换的 enum 值会导致 panic；
+//   - 对象默认不允许额外字段，map[string]T 使用 additionalProperties 描述值类型；
+//   - 递归引用的类型会退化为不限制类型的空 Schema。
+func ReflectType(t reflect.Type) *Schema {
+	return reflectType(t, map[reflect.Type]bool{})
+}
+
+func reflectType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"} // []byte 按 base64 字符串编码
+		}
+		return &Schema{Type: "array", Items: reflectType(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: reflectType(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		addStructFields(s, t, visiting)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// addStructFields 将结构体 t 的字段添加到对象 Schema s 中。
+func addStructFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// 嵌入的结构体同样需要检查递归引用，例如 type Node struct{ *Node }。
+				if !visiting[ft] {
+					visiting[ft] = true
+					addStructFields(s, ft, visiting)
+					delete(visiting, ft)
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := reflectType(field.Type, visiting)
+		if desc := field.Tag.Get("description"); desc != "" {
+			prop.Description = desc
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			addEnum(prop, field, enum)
+		}
+		s.Properties[name] = prop
+
+		optional := field.Type.Kind() == reflect.Ptr || strings.Contains(","+opts+",", ",omitempty,")
+		if !optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// addEnum 将 enum 标签的值按字段类型转换后设置到 prop 上，数组字段的 enum 设置到元素的 Schema 上。
+func addEnum(prop *Schema, field reflect.StructField, enum string) {
+	target, t := prop, field.Type
+	for {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if target.Type != "array" || target.Items == nil {
+			break
+		}
+		target, t = target.Items, t.Elem()
+	}
+	for _, raw := range strings.Split(enum, "|") {
+		v, err := enumValue(t, raw)
+		if err != nil {
+			panic(fmt.Sprintf("jsonschema: invalid enum value %q for field %s of type %s: %v", raw, field.Name, field.Type, err))
+		}
+		target.Enum = append(target.Enum, v)
+	}
+}
+
+// enumValue 将 enum 标签中的一个值转换为与 t 对应的 JSON 值。
+func enumValue(t reflect.Type, raw string) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(raw, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(raw, 10, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(raw, 64) // 按 float64 保存，避免 float32 舍入后与 JSON 中的值不相等
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,216 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Address struct {
+	City string `json:"city" description:"城市"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type Base struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Person struct {
+	Base
+	Name     string            `json:"name"`
+	Age      int               `json:"age" enum:"18|30|65"`
+	Score    float64           `json:"score,omitempty" enum:"0.5|1.5"`
+	Active   bool              `json:"active" enum:"true"`
+	Role     string            `json:"role" enum:"admin|user"`
+	Tags     []string          `json:"tags,omitempty" enum:"a|b"`
+	Nickname *string           `json:"nickname"`
+	Address  Address           `json:"address"`
+	Labels   map[string]int    `json:"labels,omitempty"`
+	Extra    json.RawMessage   `json:"extra,omitempty"`
+	Avatar   []byte            `json:"avatar,omitempty"`
+	Ignored  string            `json:"-"`
+	private  string            // 未导出字段被忽略
+	Meta     map[string]string `json:",omitempty"`
+}
+
+func TestReflectType(t *testing.T) {
+	s := For[Person]()
+	if s.Type != "object" || s.AdditionalProperties != false {
+		t.Fatalf("root = %+v", s)
+	}
+
+	wantProps := []string{"Meta", "active", "address", "age", "avatar", "created_at", "extra", "id", "labels", "name", "nickname", "role", "score", "tags"}
+	var gotProps []string
+	for name := range s.Properties {
+		gotProps = append(gotProps, name)
+	}
+	sort.Strings(gotProps)
+	if !reflect.DeepEqual(gotProps, wantProps) {
+		t.Errorf("properties = %v, want %v", gotProps, wantProps)
+	}
+
+	wantRequired := []string{"id", "created_at", "name", "age", "active", "role", "address"}
+	if !reflect.DeepEqual(s.Required, wantRequired) {
+		t.Errorf("required = %v, want %v", s.Required, wantRequired)
+	}
+
+	checks := map[string]*Schema{
+		"created_at": {Type: "string", Format: "date-time"},
+		"age":        {Type: "integer", Enum: []interface{}{int64(18), int64(30), int64(65)}},
+		"score":      {Type: "number", Enum: []interface{}{0.5, 1.5}},
+		"active":     {Type: "boolean", Enum: []interface{}{true}},
+		"role":       {Type: "string", Enum: []interface{}{"admin", "user"}},
+		"tags":       {Type: "array", Items: &Schema{Type: "string", Enum: []interface{}{"a", "b"}}},
+		"nickname":   {Type: "string"},
+		"labels":     {Type: "object", AdditionalProperties: &Schema{Type: "integer"}},
+		"extra":      {},
+		"avatar":     {Type: "string"},
+	}
+	for name, want := range checks {
+		if got := s.Properties[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %+v, want %+v", name, got, want)
+		}
+	}
+
+	addr := s.Properties["address"]
+	if addr.Properties["city"].Description != "城市" || !reflect.DeepEqual(addr.Required, []string{"city"}) {
+		t.Errorf("address = %+v", addr)
+	}
+}
+
+type Node struct {
+	Value    int     `json:"value"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+type Chain struct {
+	*Chain
+	Value int `json:"value"`
+}
+
+type LoopA struct {
+	LoopB
+	A string `json:"a"`
+}
+
+type LoopB struct {
+	*LoopA
+	B string `json:"b"`
+}
+
+func TestReflectTypeRecursion(t *testing.T) {
+	s := For[Node]()
+	children := s.Properties["children"]
+	if children.Type != "array" || !reflect.DeepEqual(children.Items, &Schema{}) {
+		t.Errorf("recursive field = %+v, want an array of unconstrained items", children)
+	}
+
+	// 匿名嵌入自身或相互嵌入的结构体不应无限递归。
+	chain := For[Chain]()
+	if _, ok := chain.Properties["value"]; !ok || len(chain.Properties) != 1 {
+		t.Errorf("Chain properties = %v", chain.Properties)
+	}
+	loop := For[LoopA]()
+	if len(loop.Properties) != 2 || loop.Properties["a"] == nil || loop.Properties["b"] == nil {
+		t.Errorf("LoopA properties = %v", loop.Properties)
+	}
+}
+
+func TestReflectTypeInvalidEnum(t *testing.T) {
+	type Bad struct {
+		Level int `json:"level" enum:"low|high"`
+	}
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), `invalid enum value "low"`) {
+			t.Errorf("recover() = %v, want invalid enum panic", r)
+		}
+	}()
+	For[Bad]()
+}
+
+func TestValidate(t *testing.T) {
+	s := For[Person]()
+	valid := `{
+		"id": "p1", "created_at": "2024-01-01T00:00:00Z", "name": "Ann", "age": 30, "active": true,
+		"role": "admin", "tags": ["a"], "nickname": null, "address": {"city": "杭州"},
+		"labels": {"x": 1}, "extra": {"anything": [1, "two"]}
+	}`
+	if err := ValidateJSON(s, []byte(valid)); err != nil {
+		t.Fatalf("valid document: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  string
+		want []string
+	}{
+		{
+			name: "wrong types",
+			doc:  `{"id": 1, "created_at": "t", "name": "n", "age": 30.5, "active": "yes", "role": "admin", "address": []}`,
+			want: []string{
+				"$.active: expected boolean, got string",
+				"$.active: value yes is not one of [true]",
+				"$.address: expected object, got array",
+				"$.age: expected integer, got number",
+				"$.age: value 30.5 is not one of [18 30 65]",
+				"$.id: expected string, got number",
+			},
+		},
+		{
+			name: "missing, extra and enum",
+			doc:  `{"id": "p", "created_at": "t", "name": "n", "age": 31, "active": true, "role": "root", "tags": ["c"], "labels": {"x": "y"}, "unknown": 1}`,
+			want: []string{
+				"$.age: value 31 is not one of [18 30 65]",
+				"$.labels.x: expected integer, got string",
+				"$.role: value root is not one of [admin user]",
+				"$.tags[0]: value c is not one of [a b]",
+				"$.unknown: unexpected property",
+				`$: missing required property "address"`,
+			},
+		},
+		{
+			name: "required field cannot be null",
+			doc:  `{"id": null, "created_at": "t", "name": "n", "age": 18, "active": true, "role": "user", "address": {"city": "c", "zip": null}}`,
+			want: []string{
+				"$.id: expected string, got null",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSON(s, []byte(tt.doc))
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("err = %v, want *ValidationError", err)
+			}
+			got := append([]string(nil), verr.Issues...)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	if err := ValidateJSON(s, []byte(`{`)); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("malformed JSON: err = %v", err)
+	}
+}
+
+func TestSchemaMarshalJSON(t *testing.T) {
+	type Item struct {
+		Count int `json:"count" enum:"1|2"`
+	}
+	data, err := json.Marshal(For[Item]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"object","properties":{"count":{"type":"integer","enum":[1,2]}},"required":["count"],"additionalProperties":false}`
+	if string(data) != want {
+		t.Errorf("got  %s\nwant %s", data, want)
+	}
+}
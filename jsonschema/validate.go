@@ -0,0 +1,177 @@
+// jsonschema/validate.go
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationError 列出了 JSON 数据不符合 Schema 的全部位置。
+type ValidationError struct {
+	Issues []string
+}
+
+// Error 实现 error 接口。
+func (e *ValidationError) Error() string {
+	return "jsonschema: " + strings.Join(e.Issues, "; ")
+}
+
+// ValidateJSON 解析 data 并按 schema 校验。
+func ValidateJSON(schema *Schema, data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("jsonschema: invalid JSON: %w", err)
+	}
+	return Validate(schema, v)
+}
+
+// Validate 校验由 encoding/json 解码得到的值 (map[string]interface{}、[]interface{}、float64 等) 是否符合 schema。
+func Validate(schema *Schema, v interface{}) error {
+	var issues []string
+	validate(schema, v, "$", &issues)
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
+func validate(s *Schema, v interface{}, path string, issues *[]string) {
+	if s == nil {
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		*issues = append(*issues, fmt.Sprintf("%s: value %v is not one of %v", path, v, s.Enum))
+	}
+
+	switch s.Type {
+	case "":
+		return
+	case "string":
+		if _, ok := v.(string); !ok {
+			*issues = append(*issues, fmt.Sprintf("%s: expected string, got %s", path, typeName(v)))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			*issues = append(*issues, fmt.Sprintf("%s: expected boolean, got %s", path, typeName(v)))
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			*issues = append(*issues, fmt.Sprintf("%s: expected number, got %s", path, typeName(v)))
+		}
+	case "integer":
+		if f, ok := v.(float64); !ok || f != math.Trunc(f) {
+			*issues = append(*issues, fmt.Sprintf("%s: expected integer, got %s", path, typeName(v)))
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			*issues = append(*issues, fmt.Sprintf("%s: expected array, got %s", path, typeName(v)))
+			return
+		}
+		for i, item := range items {
+			validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			*issues = append(*issues, fmt.Sprintf("%s: expected object, got %s", path, typeName(v)))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				*issues = append(*issues, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "." + k
+			if prop, ok := s.Properties[k]; ok {
+				if obj[k] == nil && !contains(s.Required, k) {
+					continue // 可选字段允许为 null
+				}
+				validate(prop, obj[k], child, issues)
+				continue
+			}
+			switch extra := s.AdditionalProperties.(type) {
+			case bool:
+				if !extra {
+					*issues = append(*issues, fmt.Sprintf("%s: unexpected property", child))
+				}
+			case *Schema:
+				validate(extra, obj[k], child, issues)
+			}
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// inEnum 判断 v 是否为 enum 中的值，数字按数值比较，因此 int64(1) 与解码得到的 float64(1) 相等。
+func inEnum(enum []interface{}, v interface{}) bool {
+	vf, vIsNumber := toFloat(v)
+	for _, e := range enum {
+		if ef, ok := toFloat(e); ok && vIsNumber {
+			if ef == vf {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
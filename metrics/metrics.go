@@ -0,0 +1,178 @@
+// metrics/metrics.go
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Counter 是只增不减的计数器。labelValues 与创建时的 labelNames 一一对应。
+type Counter interface {
+	Add(value float64, labelValues ...string)
+}
+
+// Histogram 按桶统计观测值的分布。labelValues 与创建时的 labelNames 一一对应。
+type Histogram interface {
+	Observe(value float64, labelValues ...string)
+}
+
+// Metrics 创建指标，可以实现此接口接入已有的指标系统 (例如 Prometheus 客户端库或 OpenTelemetry)。
+// 以相同名称多次创建时应返回同一个指标，使多个客户端可以共用一个 Metrics。
+type Metrics interface {
+	Counter(name, help string, labelNames ...string) Counter
+	Histogram(name, help string, buckets []float64, labelNames ...string) Histogram
+}
+
+// 默认的直方图桶，单位为秒。
+var (
+	// LatencyBuckets 适用于完整请求的耗时，覆盖从几十毫秒的向量请求到数分钟的长文本生成。
+	LatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+	// TokenLatencyBuckets 适用于流式输出中相邻文本块之间的间隔。
+	TokenLatencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+)
+
+// Registry 是在内存中保存指标的 Metrics 实现，可以通过 WritePrometheus 或 ServeHTTP 以 Prometheus 文本格式导出。
+// Registry 可以并发使用。以相同名称但不同类型、标签或桶重复创建指标时会 panic。
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry 创建一个空的 Registry。
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+var _ Metrics = (*Registry)(nil)
+
+const (
+	typeCounter   = "counter"
+	typeHistogram = "histogram"
+)
+
+// family 是同名指标的所有时间序列。
+type family struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+	buckets    []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series 是一组标签值对应的时间序列。counts[i] 为落入第 i 个桶 (非累计) 的观测次数。
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	sum         float64
+	count       uint64
+}
+
+// Counter 实现了 Metrics。
+func (r *Registry) Counter(name, help string, labelNames ...string) Counter {
+	return r.register(name, help, typeCounter, nil, labelNames)
+}
+
+// Histogram 实现了 Metrics。buckets 为各桶的上界，会按升序排列，+Inf 桶总是存在。
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) Histogram {
+	sorted := make([]float64, 0, len(buckets))
+	for _, b := range buckets {
+		if !math.IsInf(b, 1) {
+			sorted = append(sorted, b)
+		}
+	}
+	sort.Float64s(sorted)
+	return r.register(name, help, typeHistogram, sorted, labelNames)
+}
+
+func (r *Registry) register(name, help, typ string, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.typ != typ || !equalStrings(f.labelNames, labelNames) || !equalFloats(f.buckets, buckets) {
+			panic(fmt.Sprintf("metrics: %s already registered with a different type, labels or buckets", name))
+		}
+		return f
+	}
+	f := &family{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: append([]string(nil), labelNames...),
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// Add 实现了 Counter，负数会被忽略。
+func (f *family) Add(value float64, labelValues ...string) {
+	if value < 0 || math.IsNaN(value) {
+		return
+	}
+	f.mu.Lock()
+	f.get(labelValues).value += value
+	f.mu.Unlock()
+}
+
+// Observe 实现了 Histogram。
+func (f *family) Observe(value float64, labelValues ...string) {
+	if math.IsNaN(value) {
+		return
+	}
+	f.mu.Lock()
+	s := f.get(labelValues)
+	i := sort.SearchFloat64s(f.buckets, value) // 第一个不小于 value 的上界
+	s.counts[i]++
+	s.sum += value
+	s.count++
+	f.mu.Unlock()
+}
+
+// get 返回 labelValues 对应的时间序列，不存在时创建。调用方需持有 f.mu。
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.typ == typeHistogram {
+			s.counts = make([]uint64, len(f.buckets)+1)
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	r := NewRegistry()
+	requests := r.Counter("app_requests_total", "Number of requests.\nCounted per \\ model.", "provider", "model")
+	latency := r.Histogram("app_latency_seconds", "Request latency.", []float64{1, 0.1, 0.5}, "model")
+	r.Counter("app_unused_total", "Families without series are omitted.")
+	plain := r.Histogram("app_plain_seconds", "", []float64{1})
+
+	requests.Add(2, "volcengine", `say "hi"`)
+	requests.Add(1, "volcengine", "line\nbreak\\")
+	requests.Add(-1, "volcengine", "ignored") // 计数器忽略负数
+	latency.Observe(0.05, "m")
+	latency.Observe(0.1, "m") // 等于上界时计入该桶
+	latency.Observe(0.7, "m")
+	latency.Observe(3, "m")
+	plain.Observe(2)
+
+	// 指标按名称、时间序列按标签值排序；桶是累计的，+Inf 桶等于 _count。
+	want := `# HELP app_latency_seconds Request latency.
+# TYPE app_latency_seconds histogram
+app_latency_seconds_bucket{model="m",le="0.1"} 2
+app_latency_seconds_bucket{model="m",le="0.5"} 2
+app_latency_seconds_bucket{model="m",le="1"} 3
+app_latency_seconds_bucket{model="m",le="+Inf"} 4
+app_latency_seconds_sum{model="m"} 3.85
+app_latency_seconds_count{model="m"} 4
+# TYPE app_plain_seconds histogram
+app_plain_seconds_bucket{le="1"} 0
+app_plain_seconds_bucket{le="+Inf"} 1
+app_plain_seconds_sum 2
+app_plain_seconds_count 1
+# HELP app_requests_total Number of requests.\nCounted per \\ model.
+# TYPE app_requests_total counter
+app_requests_total{provider="volcengine",model="line\nbreak\\"} 1
+app_requests_total{provider="volcengine",model="say \"hi\""} 2
+`
+	var buf bytes.Buffer
+	if err := r.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus: %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("exposition mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Header().Get("Content-Type") != PrometheusContentType || rec.Body.String() != want {
+		t.Errorf("ServeHTTP: content type %q, body:\n%s", rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+}
+
+func TestRegistryReregistration(t *testing.T) {
+	r := NewRegistry()
+	a := r.Counter("shared_total", "Shared.", "model")
+	b := r.Counter("shared_total", "Shared.", "model")
+	a.Add(1, "m")
+	b.Add(2, "m")
+	var buf bytes.Buffer
+	r.WritePrometheus(&buf)
+	if !strings.Contains(buf.String(), `shared_total{model="m"} 3`) {
+		t.Errorf("same name must return the same counter:\n%s", buf.String())
+	}
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"different type", func() { r.Histogram("shared_total", "Shared.", LatencyBuckets, "model") }},
+		{"different labels", func() { r.Counter("shared_total", "Shared.", "provider") }},
+		{"wrong label count", func() { a.Add(1, "m", "extra") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
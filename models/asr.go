@@ -0,0 +1,62 @@
+// models/asr.go
+package models
+
+import (
+	"io"
+	"time"
+)
+
+// AudioTranscriptionRequest 定义了语音识别请求的结构。
+// 音频通过 Audio、AudioURL 或 AudioStream 三者之一提供；设置 AudioStream 时为流式识别，
+// 识别结果随音频输入通过 OnStreamChunk 实时返回。
+type AudioTranscriptionRequest struct {
+	Model              string      `json:"model,omitempty"`               // 平台特定的模型 (或资源) ID 或通用别名
+	Audio              []byte      `json:"audio,omitempty"`               // 完整的音频数据
+	AudioURL           string      `json:"audio_url,omitempty"`           // 音频文件的公网 URL
+	Format             AudioFormat `json:"format,omitempty"`              // 音频格式，零值表示由平台识别或使用平台默认
+	SampleRate         int         `json:"sample_rate,omitempty"`         // 采样率 (Hz)，pcm 格式时需要
+	Language           string      `json:"language,omitempty"`            // 语言，例如 "zh-CN"，零值表示自动识别
+	Timestamps         bool        `json:"timestamps,omitempty"`          // 是否返回逐词时间戳
+	SpeakerDiarization bool        `json:"speaker_diarization,omitempty"` // 是否区分说话人
+
+	// AudioStream 是流式识别的音频输入，读到 io.EOF 表示音频结束。识别提前结束 (出错或取消) 时 SDK 不再读取它，
+	// 但正在进行的 Read 无法被中断；可能长时间阻塞的输入 (例如麦克风) 应在调用返回后由调用方关闭。
+	AudioStream io.Reader `json:"-"`
+	// OnStreamChunk 在流式识别过程中收到识别结果时调用，返回错误会中止识别。
+	OnStreamChunk func(chunk *AudioTranscriptionStreamChunk) error `json:"-"`
+
+	PlatformSpecificParams map[string]interface{} `json:"platform_specific_params,omitempty"` // 用于覆盖平台特定参数
+}
+
+// AudioTranscriptionResponse 定义了语音识别响应的结构。
+type AudioTranscriptionResponse struct {
+	ID       string                 `json:"id"`                 // 请求的唯一标识符
+	Text     string                 `json:"text"`               // 完整的识别文本
+	Duration time.Duration          `json:"duration,omitempty"` // 音频时长 (如果平台提供)
+	Segments []TranscriptionSegment `json:"segments,omitempty"` // 按时间排列的分句
+}
+
+// TranscriptionSegment 是识别结果中的一个分句。
+type TranscriptionSegment struct {
+	Start    time.Duration    `json:"start"`              // 相对音频开头的起始时间
+	End      time.Duration    `json:"end"`                // 相对音频开头的结束时间
+	Text     string           `json:"text"`               // 分句文本
+	Speaker  string           `json:"speaker,omitempty"`  // 说话人标识，仅在 SpeakerDiarization 为 true 时返回
+	Words    []TranscriptWord `json:"words,omitempty"`    // 逐词时间戳，仅在 Timestamps 为 true 时返回
+	Definite bool             `json:"definite,omitempty"` // 流式识别中该分句是否已确定，不会再被后续结果修改
+}
+
+// TranscriptWord 是带时间戳的单个词 (中文为单个字)。
+type TranscriptWord struct {
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	Text  string        `json:"text"`
+}
+
+// AudioTranscriptionStreamChunk 是流式识别过程中返回的结果。
+// 每个 chunk 都包含截至目前的完整结果，而不是增量。
+type AudioTranscriptionStreamChunk struct {
+	Text     string                 `json:"text"`               // 截至目前的完整识别文本
+	Segments []TranscriptionSegment `json:"segments,omitempty"` // 截至目前的分句
+	IsFinal  bool                   `json:"is_final"`           // 是否为音频结束后的最终结果
+}
@@ -0,0 +1,84 @@
+// models/batch.go
+package models
+
+import "time"
+
+// BatchOperation 是批量任务中每个请求执行的操作。
+type BatchOperation string
+
+const (
+	BatchOperationTextGeneration BatchOperation = "text_generation" // 文本生成
+	BatchOperationEmbedding      BatchOperation = "embedding"       // 向量嵌入
+)
+
+// BatchRequestItem 是批量任务中的一个请求，根据任务的 Operation 设置对应的字段。
+type BatchRequestItem struct {
+	CustomID       string                 `json:"custom_id"`                 // 请求的自定义 ID，用于将结果与请求对应，为空时使用请求在列表中的序号
+	TextGeneration *TextGenerationRequest `json:"text_generation,omitempty"` // Operation 为 text_generation 时的请求
+	Embedding      *EmbeddingRequest      `json:"embedding,omitempty"`       // Operation 为 embedding 时的请求
+}
+
+// BatchJobRequest 定义了创建批量任务请求的结构。
+type BatchJobRequest struct {
+	Model                  string                 `json:"model"`                              // 批量任务使用的模型，请求中未指定模型时使用
+	Operation              BatchOperation         `json:"operation"`                          // 每个请求执行的操作
+	Items                  []BatchRequestItem     `json:"items"`                              // 批量请求列表
+	CompletionWindow       time.Duration          `json:"completion_window,omitempty"`        // 期望的完成时间窗口，零值使用平台默认值
+	PlatformSpecificParams map[string]interface{} `json:"platform_specific_params,omitempty"` // 用于覆盖平台特定参数
+}
+
+// BatchJobStatus 是批量任务的状态。
+type BatchJobStatus string
+
+const (
+	BatchJobQueued     BatchJobStatus = "queued"     // 排队中
+	BatchJobRunning    BatchJobStatus = "running"    // 执行中
+	BatchJobCompleted  BatchJobStatus = "completed"  // 已完成，部分请求仍可能失败
+	BatchJobFailed     BatchJobStatus = "failed"     // 任务整体失败
+	BatchJobCancelling BatchJobStatus = "cancelling" // 取消中
+	BatchJobCancelled  BatchJobStatus = "cancelled"  // 已取消
+	BatchJobExpired    BatchJobStatus = "expired"    // 未在完成时间窗口内完成
+)
+
+// Terminal 报告任务是否已经结束，结束后不会再变化。
+func (s BatchJobStatus) Terminal() bool {
+	switch s {
+	case BatchJobCompleted, BatchJobFailed, BatchJobCancelled, BatchJobExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// BatchJob 描述了一个批量任务。
+type BatchJob struct {
+	ID            string            `json:"id"`                     // 任务 ID
+	Model         string            `json:"model"`                  // 任务使用的模型
+	Operation     BatchOperation    `json:"operation"`              // 每个请求执行的操作
+	Status        BatchJobStatus    `json:"status"`                 // 任务状态
+	Message       string            `json:"message,omitempty"`      // 平台返回的状态说明，例如失败原因
+	RequestCounts BatchRequestCount `json:"request_counts"`         // 请求数量统计
+	CreatedAt     time.Time         `json:"created_at"`             // 创建时间
+	CompletedAt   time.Time         `json:"completed_at,omitempty"` // 结束时间，未结束时为零值
+}
+
+// BatchRequestCount 统计了批量任务中各状态的请求数量。
+type BatchRequestCount struct {
+	Total     int `json:"total"`     // 请求总数
+	Completed int `json:"completed"` // 成功的请求数
+	Failed    int `json:"failed"`    // 失败的请求数
+}
+
+// BatchResult 是批量任务中单个请求的结果，通过 CustomID 与请求对应。
+type BatchResult struct {
+	CustomID       string                  `json:"custom_id"`                 // 对应请求的自定义 ID
+	TextGeneration *TextGenerationResponse `json:"text_generation,omitempty"` // 文本生成的结果
+	Embedding      *EmbeddingResponse      `json:"embedding,omitempty"`       // 向量嵌入的结果
+	Error          *BatchError             `json:"error,omitempty"`           // 请求失败时的错误信息
+}
+
+// BatchError 是批量任务中单个请求失败的原因。
+type BatchError struct {
+	Code    string `json:"code"`    // 错误代码，SDK 错误使用 errors 包中的错误代码
+	Message string `json:"message"` // 错误信息
+}
@@ -0,0 +1,49 @@
+// models/content.go
+package models
+
+// ContentPartType 是多模态消息内容中一个部分的类型。
+type ContentPartType string
+
+const (
+	ContentPartText  ContentPartType = "text"  // 文本
+	ContentPartImage ContentPartType = "image" // 图片
+	ContentPartVideo ContentPartType = "video" // 视频 (视频文件或抽帧后的图片序列)
+)
+
+// ContentPart 是多模态消息内容的一个部分，按 Type 使用对应的字段。
+// 图片与视频可以是公网 URL，也可以是 data URL (例如 "data:image/jpeg;base64,...")。
+type ContentPart struct {
+	Type     ContentPartType `json:"type"`
+	Text     string          `json:"text,omitempty"`      // Type 为 text 时的文本
+	ImageURL string          `json:"image_url,omitempty"` // Type 为 image 时的图片地址
+	Video    *VideoInput     `json:"video,omitempty"`     // Type 为 video 时的视频
+}
+
+// VideoInput 描述视频输入，URL 与 Frames 二选一。
+type VideoInput struct {
+	URL    string   `json:"url,omitempty"`    // 视频文件地址，由平台抽帧
+	Frames []string `json:"frames,omitempty"` // 已抽帧的图片地址，按时间顺序排列
+	// FPS 是每秒的帧数：与 URL 一起使用时是平台抽帧的频率 (零值表示平台默认)，
+	// 与 Frames 一起使用时是抽帧时的采样频率，供支持的平台推断画面的时间间隔。
+	FPS float64 `json:"fps,omitempty"`
+}
+
+// TextPart 返回文本类型的 ContentPart。
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: ContentPartText, Text: text}
+}
+
+// ImagePart 返回图片类型的 ContentPart，url 可以是公网 URL 或 data URL。
+func ImagePart(url string) ContentPart {
+	return ContentPart{Type: ContentPartImage, ImageURL: url}
+}
+
+// VideoURLPart 返回由平台按 fps 抽帧的视频 ContentPart，fps 为 0 时使用平台默认频率。
+func VideoURLPart(url string, fps float64) ContentPart {
+	return ContentPart{Type: ContentPartVideo, Video: &VideoInput{URL: url, FPS: fps}}
+}
+
+// VideoFramesPart 返回由已抽帧图片组成的视频 ContentPart，fps 是抽帧时的采样频率。
+func VideoFramesPart(frames []string, fps float64) ContentPart {
+	return ContentPart{Type: ContentPartVideo, Video: &VideoInput{Frames: frames, FPS: fps}}
+}
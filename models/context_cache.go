@@ -0,0 +1,38 @@
+// models/context_cache.go
+package models
+
+import "time"
+
+// ContextCacheMode 是上下文缓存的模式。
+type ContextCacheMode string
+
+const (
+	// ContextCacheModeSession 会话缓存：每次对话的输入与输出都会自动追加到缓存中，适合多轮对话。
+	ContextCacheModeSession ContextCacheMode = "session"
+	// ContextCacheModeCommonPrefix 前缀缓存：缓存内容固定不变，适合多个请求共享较长的系统提示。
+	ContextCacheModeCommonPrefix ContextCacheMode = "common_prefix"
+)
+
+// ContextCacheRequest 定义了创建上下文缓存请求的结构。
+type ContextCacheRequest struct {
+	Model                  string                 `json:"model"`                              // 平台特定的模型 ID 或通用别名
+	Messages               []Message              `json:"messages"`                           // 需要缓存的消息前缀，通常是较长的系统提示
+	Mode                   ContextCacheMode       `json:"mode,omitempty"`                     // 缓存模式，为空时使用平台默认值
+	TTL                    time.Duration          `json:"ttl,omitempty"`                      // 缓存有效期，每次使用缓存都会重新计时；零值使用平台默认值
+	PlatformSpecificParams map[string]interface{} `json:"platform_specific_params,omitempty"` // 用于覆盖平台特定参数
+}
+
+// ContextCache 描述了已创建的上下文缓存。
+type ContextCache struct {
+	ID        string           `json:"id"`              // 缓存 ID，在 TextGenerationRequest.ContextCacheID 中引用
+	Model     string           `json:"model"`           // 缓存所属的模型
+	Mode      ContextCacheMode `json:"mode"`            // 缓存模式
+	TTL       time.Duration    `json:"ttl"`             // 缓存有效期
+	ExpiresAt time.Time        `json:"expires_at"`      // 按创建时间推算的过期时间，期间使用缓存会延后实际过期时间
+	Usage     Usage            `json:"usage,omitempty"` // 创建缓存消耗的 Token
+}
+
+// Expired 报告缓存在 now 时刻是否已按 ExpiresAt 过期。
+func (c *ContextCache) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
@@ -1,35 +1,238 @@
 // models/models.go
 package models
 
+import (
+	"sort"
+	"strings"
+)
+
 // TextGenerationRequest 定义了文本生成请求的结构。
 type TextGenerationRequest struct {
 	Prompt                 string                 `json:"prompt"`                             // 输入的提示文本
+	Parts                  []ContentPart          `json:"parts,omitempty"`                    // 与 Prompt 组成同一条用户消息的多模态内容 (图片、视频)，位于 Prompt 之前
 	Model                  string                 `json:"model,omitempty"`                    // 平台特定的模型 ID 或通用别名
 	MaxTokens              int                    `json:"max_tokens,omitempty"`               // 生成文本的最大长度
-	Temperature            float32                `json:"temperature,omitempty"`              // 控制生成文本的随机性
-	TopP                   float32                `json:"top_p,omitempty"`                    // 控制核心采样的概率阈值
+	Temperature            *float32               `json:"temperature,omitempty"`              // 控制生成文本的随机性，nil 表示使用平台默认值，可显式设置为 0
+	TopP                   *float32               `json:"top_p,omitempty"`                    // 控制核心采样的概率阈值，nil 表示使用平台默认值
+	FrequencyPenalty       *float32               `json:"frequency_penalty,omitempty"`        // 按出现频率惩罚重复 Token，通常取值 [-2, 2]
+	PresencePenalty        *float32               `json:"presence_penalty,omitempty"`         // 按是否出现过惩罚重复 Token，通常取值 [-2, 2]
+	Seed                   *int64                 `json:"seed,omitempty"`                     // 随机种子，相同种子与参数下尽量返回确定的结果 (如果平台支持)
+	LogitBias              map[string]int         `json:"logit_bias,omitempty"`               // Token ID 到偏置值 (通常为 [-100, 100]) 的映射
+	N                      int                    `json:"n,omitempty"`                        // 生成的候选数量，零值表示使用平台默认值 (通常为 1)
+	Logprobs               bool                   `json:"logprobs,omitempty"`                 // 是否返回输出 Token 的对数概率
+	TopLogprobs            int                    `json:"top_logprobs,omitempty"`             // 每个位置返回概率最高的候选 Token 数量，需要同时开启 Logprobs
 	StopSequences          []string               `json:"stop_sequences,omitempty"`           // 遇到即停止生成的序列
 	Stream                 bool                   `json:"stream,omitempty"`                   // 如果为 true，则响应将是流式传输
+	Thinking               ThinkingMode           `json:"thinking,omitempty"`                 // 推理模型是否进行深度思考，为空时使用平台默认行为
+	ResponseFormat         *ResponseFormat        `json:"response_format,omitempty"`          // 指定输出格式，例如 JSON 模式或 JSON Schema 结构化输出
+	ContextCacheID         string                 `json:"context_cache_id,omitempty"`         // 引用已创建的上下文缓存 (如果平台支持)，Prompt 会接在缓存内容之后
 	PlatformSpecificParams map[string]interface{} `json:"platform_specific_params,omitempty"` // 用于覆盖平台特定参数
+
+	// OnStreamChunk 在 Stream 为 true 时对每个收到的流式块调用，可用于实时展示生成内容。
+	// 返回错误会中止流式请求。无论是否设置，最终都会返回聚合后的完整响应。
+	OnStreamChunk func(chunk *TextGenerationStreamChunk) error `json:"-"`
+}
+
+// MessageRole 是对话消息的角色。
+type MessageRole string
+
+const (
+	RoleSystem    MessageRole = "system"    // 系统提示
+	RoleUser      MessageRole = "user"      // 用户输入
+	RoleAssistant MessageRole = "assistant" // 模型回复
+)
+
+// Message 是一条对话消息。
+type Message struct {
+	Role    MessageRole   `json:"role"`            // 消息角色
+	Content string        `json:"content"`         // 消息内容
+	Parts   []ContentPart `json:"parts,omitempty"` // 多模态内容 (图片、视频)，位于 Content 之前
+}
+
+// ThinkingMode 控制推理模型 (例如 DeepSeek R1、豆包深度思考模型) 是否输出思考过程。
+type ThinkingMode string
+
+const (
+	ThinkingEnabled  ThinkingMode = "enabled"  // 强制开启深度思考
+	ThinkingDisabled ThinkingMode = "disabled" // 关闭深度思考
+	ThinkingAuto     ThinkingMode = "auto"     // 由模型自行判断是否需要深度思考
+)
+
+// ResponseFormatType 是输出格式的类型。
+type ResponseFormatType string
+
+const (
+	ResponseFormatText       ResponseFormatType = "text"        // 普通文本 (默认)
+	ResponseFormatJSONObject ResponseFormatType = "json_object" // 输出任意合法的 JSON 对象
+	ResponseFormatJSONSchema ResponseFormatType = "json_schema" // 输出符合 JSONSchema 的 JSON
+)
+
+// ResponseFormat 指定模型的输出格式。
+type ResponseFormat struct {
+	Type       ResponseFormatType `json:"type"`                  // 输出格式类型
+	JSONSchema *JSONSchemaFormat  `json:"json_schema,omitempty"` // Type 为 json_schema 时必填
+}
+
+// JSONSchemaFormat 描述了结构化输出需要遵循的 JSON Schema。
+type JSONSchemaFormat struct {
+	Name        string      `json:"name"`                  // Schema 名称，例如 "weather_report"
+	Description string      `json:"description,omitempty"` // Schema 的用途说明，帮助模型理解输出含义
+	Schema      interface{} `json:"schema"`                // JSON Schema，可以是 *jsonschema.Schema 或 map[string]interface{}
+	Strict      bool        `json:"strict,omitempty"`      // 是否要求平台严格遵循 Schema (如果平台支持)
 }
 
 // TextGenerationResponse 定义了文本生成响应的结构。
 type TextGenerationResponse struct {
-	ID            string   `json:"id"`             // 请求的唯一标识符
-	GeneratedText string   `json:"generated_text"` // 生成的文本内容
-	FinishReason  string   `json:"finish_reason"`  // 完成原因，例如 "stop" (自然停止), "length" (达到最大长度)
-	TokenUsage    struct { // Token 使用情况 (如果平台提供)
-		PromptTokens     int `json:"prompt_tokens"`     // 输入提示的 Token 数量
-		CompletionTokens int `json:"completion_tokens"` // 生成文本的 Token 数量
-		TotalTokens      int `json:"total_tokens"`      // 总 Token 数量
-	} `json:"token_usage,omitempty"`
+	ID              string         `json:"id"`                          // 请求的唯一标识符
+	GeneratedText   string         `json:"generated_text"`              // 生成的文本内容
+	Reasoning       string         `json:"reasoning,omitempty"`         // 推理模型的思考过程 (如果平台提供)，不包含在 GeneratedText 中
+	FinishReason    FinishReason   `json:"finish_reason"`               // 归一化后的完成原因，例如 "stop" (自然停止), "length" (达到最大长度)
+	RawFinishReason string         `json:"raw_finish_reason,omitempty"` // 平台返回的原始完成原因
+	Logprobs        []TokenLogprob `json:"logprobs,omitempty"`          // 输出 Token 的对数概率，仅在请求开启 Logprobs 时返回
+	Choices         []Choice       `json:"choices,omitempty"`           // 全部候选结果 (按 Index 排序)，上面的字段与 Choices[0] 保持一致
+	TokenUsage      Usage          `json:"token_usage,omitempty"`       // Token 使用情况 (如果平台提供)
+	References      []Reference    `json:"references,omitempty"`        // 应用 (例如联网搜索、知识库插件) 返回的引用来源
+	BotUsage        *BotUsage      `json:"bot_usage,omitempty"`         // 应用调用各模型与插件的用量明细，仅在通过应用 ID 调用时返回
+}
+
+// SetChoices 按 Index 排序后设置 Choices，并将第一个候选结果同步到
+// GeneratedText、Reasoning、FinishReason 与 Logprobs，供只关心单个结果的调用方使用。
+func (r *TextGenerationResponse) SetChoices(choices []Choice) {
+	sort.Slice(choices, func(i, j int) bool { return choices[i].Index < choices[j].Index })
+	r.Choices = choices
+	if len(choices) == 0 {
+		return
+	}
+	first := choices[0]
+	r.GeneratedText = first.Text
+	r.Reasoning = first.Reasoning
+	r.FinishReason = first.FinishReason
+	r.RawFinishReason = first.RawFinishReason
+	r.Logprobs = first.Logprobs
+}
+
+// Choice 是文本生成的一个候选结果，请求中 N > 1 时会返回多个。
+type Choice struct {
+	Index           int            `json:"index"`                       // 候选结果的序号，从 0 开始
+	Text            string         `json:"text"`                        // 生成的文本内容
+	Reasoning       string         `json:"reasoning,omitempty"`         // 推理模型的思考过程 (如果平台提供)
+	FinishReason    FinishReason   `json:"finish_reason"`               // 归一化后的完成原因
+	RawFinishReason string         `json:"raw_finish_reason,omitempty"` // 平台返回的原始完成原因
+	Logprobs        []TokenLogprob `json:"logprobs,omitempty"`          // 输出 Token 的对数概率
+}
+
+// FinishReason 是归一化后的完成原因，各平台的原始取值会被映射为以下常量之一。
+type FinishReason string
+
+const (
+	FinishReasonStop          FinishReason = "stop"           // 自然停止或遇到停止序列
+	FinishReasonLength        FinishReason = "length"         // 达到最大长度限制
+	FinishReasonToolCalls     FinishReason = "tool_calls"     // 模型请求调用工具
+	FinishReasonContentFilter FinishReason = "content_filter" // 内容被安全策略拦截
+	FinishReasonError         FinishReason = "error"          // 平台在生成过程中出错
+	FinishReasonOther         FinishReason = "other"          // 无法识别的原始值，可查看 RawFinishReason
+)
+
+// NormalizeFinishReason 将平台返回的原始完成原因映射为 FinishReason，raw 为空时返回空值。
+func NormalizeFinishReason(raw string) FinishReason {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "":
+		return ""
+	case "stop", "stop_sequence", "end_turn", "eos", "complete":
+		return FinishReasonStop
+	case "length", "max_tokens", "model_length":
+		return FinishReasonLength
+	case "tool_calls", "tool_use", "function_call":
+		return FinishReasonToolCalls
+	case "content_filter", "sensitive", "safety", "blocked":
+		return FinishReasonContentFilter
+	case "error", "insufficient_system_resource":
+		return FinishReasonError
+	default:
+		return FinishReasonOther
+	}
+}
+
+// Usage 描述了一次请求的 Token 使用情况，各字段在平台未提供时为 0。
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`              // 输入的 Token 数量
+	CompletionTokens int `json:"completion_tokens"`          // 输出的 Token 数量 (包含思考过程)
+	TotalTokens      int `json:"total_tokens"`               // 总 Token 数量
+	CachedTokens     int `json:"cached_tokens,omitempty"`    // 输入中命中缓存的 Token 数量，已包含在 PromptTokens 中
+	ReasoningTokens  int `json:"reasoning_tokens,omitempty"` // 输出中用于思考过程的 Token 数量，已包含在 CompletionTokens 中
+}
+
+// Add 将 other 累加到 u 上，用于合并多次请求的用量。
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.CachedTokens += other.CachedTokens
+	u.ReasoningTokens += other.ReasoningTokens
 }
 
 // TextGenerationStreamChunk 定义了文本生成流式响应的块结构。
 type TextGenerationStreamChunk struct {
-	ID      string `json:"id"`       // 块的唯一标识符或关联请求的ID
-	Delta   string `json:"delta"`    // 生成的文本块
-	IsFinal bool   `json:"is_final"` // 是否是最后一个块
+	ID         string         `json:"id"`                   // 块的唯一标识符或关联请求的ID
+	Index      int            `json:"index"`                // 块所属候选结果的序号，N > 1 时用于区分不同候选
+	Delta      string         `json:"delta"`                // 生成的文本块
+	Reasoning  string         `json:"reasoning,omitempty"`  // 思考过程的增量文本块 (如果平台提供)
+	Logprobs   []TokenLogprob `json:"logprobs,omitempty"`   // 本块中 Token 的对数概率，仅在请求开启 Logprobs 时返回
+	References []Reference    `json:"references,omitempty"` // 本块中新增的引用来源 (如果平台提供)
+
+	// 以下字段仅在最后一个块中设置，与最终响应的 FinishReason (第一个候选结果) 和 TokenUsage 一致
+	FinishReason FinishReason `json:"finish_reason,omitempty"` // 归一化后的完成原因
+	Usage        *Usage       `json:"usage,omitempty"`         // Token 使用情况，平台未返回用量时为 nil
+	IsFinal      bool         `json:"is_final"`                // 是否是最后一个块
+}
+
+// Reference 描述了应用插件 (例如联网搜索、知识库) 返回的一条引用来源。
+type Reference struct {
+	Title       string `json:"title,omitempty"`        // 标题
+	URL         string `json:"url,omitempty"`          // 来源链接
+	SiteName    string `json:"site_name,omitempty"`    // 站点名称
+	Summary     string `json:"summary,omitempty"`      // 摘要
+	PublishTime string `json:"publish_time,omitempty"` // 发布时间，格式由平台决定
+	DocName     string `json:"doc_name,omitempty"`     // 知识库文档名称
+	ChunkID     string `json:"chunk_id,omitempty"`     // 知识库文档切片 ID
+}
+
+// BotUsage 描述了应用一次调用中各模型与插件的用量。
+type BotUsage struct {
+	ModelUsage  []ModelUsage  `json:"model_usage,omitempty"`  // 每个模型的 Token 用量
+	ActionUsage []ActionUsage `json:"action_usage,omitempty"` // 每个插件的调用次数
+}
+
+// ModelUsage 是应用中单个模型的 Token 用量。
+type ModelUsage struct {
+	Name  string `json:"name"` // 模型名称或推理接入点 ID
+	Usage Usage  `json:"usage"`
+}
+
+// ActionUsage 是应用中单个插件的调用次数。
+type ActionUsage struct {
+	Name  string `json:"name"`  // 插件名称，例如 "content_plugin"
+	Count int    `json:"count"` // 调用次数
+}
+
+// TokenLogprob 描述了一个输出 Token 的对数概率。
+type TokenLogprob struct {
+	Token       string       `json:"token"`                  // Token 文本
+	Logprob     float64      `json:"logprob"`                // 对数概率
+	Bytes       []int        `json:"bytes,omitempty"`        // Token 的 UTF-8 字节表示 (如果平台提供)
+	TopLogprobs []TopLogprob `json:"top_logprobs,omitempty"` // 该位置概率最高的候选 Token
+}
+
+// TopLogprob 描述了某个位置上的一个候选 Token 及其对数概率。
+type TopLogprob struct {
+	Token   string  `json:"token"`           // Token 文本
+	Logprob float64 `json:"logprob"`         // 对数概率
+	Bytes   []int   `json:"bytes,omitempty"` // Token 的 UTF-8 字节表示 (如果平台提供)
+}
+
+// Ptr 返回 v 的指针，便于设置 Temperature、Seed 等可空字段，例如 models.Ptr[float32](0)。
+func Ptr[T any](v T) *T {
+	return &v
 }
 
 // ImageGenerationRequest 定义了图片生成请求的结构。
@@ -40,9 +243,24 @@ type ImageGenerationRequest struct {
 	Size                   string                 `json:"size,omitempty"`                     // 图片尺寸，例如 "1024x1024"
 	Quality                string                 `json:"quality,omitempty"`                  // 图片质量，例如 "standard", "hd"
 	Style                  string                 `json:"style,omitempty"`                    // 图片风格，例如 "vivid" (鲜明), "natural" (自然)
+	Images                 []string               `json:"images,omitempty"`                   // 参考图 (公网 URL 或 data URL)，设置后为图生图或图片编辑
+	Mask                   string                 `json:"mask,omitempty"`                     // 局部重绘的蒙版 (公网 URL 或 data URL)，白色区域会被重绘，需要恰好一张参考图；平台不支持时返回 ErrUnsupportedOperation
+	Strength               *float32               `json:"strength,omitempty"`                 // 图生图时对参考图的改变程度，取值 [0, 1]，越大越偏离参考图；平台不支持时返回 ErrUnsupportedOperation
+	GuidanceScale          *float32               `json:"guidance_scale,omitempty"`           // 生成结果与提示的贴合程度，越大越贴合提示
+	Seed                   *int64                 `json:"seed,omitempty"`                     // 随机种子，相同种子与参数下尽量返回相同的图片
+	ResponseFormat         ImageResponseFormat    `json:"response_format,omitempty"`          // 返回图片的方式，零值表示 URL
+	OutputFormat           string                 `json:"output_format,omitempty"`            // 图片文件格式，例如 "png"、"jpeg"，零值表示平台默认
 	PlatformSpecificParams map[string]interface{} `json:"platform_specific_params,omitempty"` // 用于覆盖平台特定参数
 }
 
+// ImageResponseFormat 指定生成的图片以 URL 还是 Base64 数据返回。
+type ImageResponseFormat string
+
+const (
+	ImageResponseURL    ImageResponseFormat = "url"    // 返回图片 URL，通常在一段时间后失效
+	ImageResponseBase64 ImageResponseFormat = "base64" // 返回 Base64 编码的图片数据
+)
+
 // ImageGenerationResponse 定义了图片生成响应的结构。
 type ImageGenerationResponse struct {
 	ID     string  `json:"id"`     // 请求的唯一标识符
@@ -66,12 +284,15 @@ type EmbeddingRequest struct {
 
 // EmbeddingResponse 定义了向量嵌入响应的结构。
 type EmbeddingResponse struct {
-	ID         string      `json:"id"`         // 请求的唯一标识符
-	Embeddings []Embedding `json:"embeddings"` // 生成的向量嵌入列表
-	TokenUsage struct {    // Token 使用情况 (如果平台提供)
-		PromptTokens int `json:"prompt_tokens"` // 输入提示的 Token 数量
-		TotalTokens  int `json:"total_tokens"`  // 总 Token 数量
-	} `json:"token_usage,omitempty"`
+	ID         string      `json:"id"`                    // 请求的唯一标识符
+	Embeddings []Embedding `json:"embeddings"`            // 生成的向量嵌入列表
+	TokenUsage Usage       `json:"token_usage,omitempty"` // Token 使用情况 (如果平台提供)
+}
+
+// EmbeddingLimits 描述了向量嵌入接口的输入限制。
+type EmbeddingLimits struct {
+	MaxInputs      int `json:"max_inputs,omitempty"`       // 单次请求最多的输入条数
+	MaxInputTokens int `json:"max_input_tokens,omitempty"` // 单条输入最多的 Token 数量
 }
 
 // Embedding 定义了单个向量嵌入的数据。
@@ -0,0 +1,46 @@
+// models/moderation.go
+package models
+
+import "sort"
+
+// 常见的审核类别，各平台的原始类别会尽量映射为以下名称，无法映射的保留平台原始名称。
+const (
+	ModerationSexual     = "sexual"
+	ModerationHate       = "hate"
+	ModerationHarassment = "harassment"
+	ModerationSelfHarm   = "self_harm"
+	ModerationViolence   = "violence"
+	ModerationIllegal    = "illegal"
+)
+
+// ModerationRequest 定义了内容审核请求的结构。
+type ModerationRequest struct {
+	Input                  []string               `json:"input"`                              // 需要审核的文本列表
+	Model                  string                 `json:"model,omitempty"`                    // 平台特定的模型 ID 或通用别名
+	PlatformSpecificParams map[string]interface{} `json:"platform_specific_params,omitempty"` // 用于覆盖平台特定参数
+}
+
+// ModerationResponse 定义了内容审核响应的结构。
+type ModerationResponse struct {
+	ID      string             `json:"id"`      // 请求的唯一标识符
+	Results []ModerationResult `json:"results"` // 审核结果，与 Input 一一对应
+}
+
+// ModerationResult 是单条文本的审核结果。
+type ModerationResult struct {
+	Flagged        bool               `json:"flagged"`                   // 是否命中任一类别
+	Categories     map[string]bool    `json:"categories"`                // 各类别是否命中
+	CategoryScores map[string]float64 `json:"category_scores,omitempty"` // 各类别的置信度，取值 [0, 1] (如果平台提供)
+}
+
+// FlaggedCategories 返回命中的类别名称。
+func (r ModerationResult) FlaggedCategories() []string {
+	var flagged []string
+	for category, hit := range r.Categories {
+		if hit {
+			flagged = append(flagged, category)
+		}
+	}
+	sort.Strings(flagged)
+	return flagged
+}
@@ -0,0 +1,26 @@
+// models/rerank.go
+package models
+
+// RerankRequest 定义了排序 (重排) 请求的结构，用于对检索得到的候选文档按与查询的相关性重新排序。
+type RerankRequest struct {
+	Query                  string                 `json:"query"`                              // 查询文本
+	Documents              []string               `json:"documents"`                          // 待排序的候选文档
+	Model                  string                 `json:"model,omitempty"`                    // 平台特定的模型 ID 或通用别名
+	TopN                   int                    `json:"top_n,omitempty"`                    // 只返回相关性最高的 N 个结果，零值表示返回全部
+	ReturnDocuments        bool                   `json:"return_documents,omitempty"`         // 是否在结果中返回文档原文
+	PlatformSpecificParams map[string]interface{} `json:"platform_specific_params,omitempty"` // 用于覆盖平台特定参数
+}
+
+// RerankResponse 定义了排序响应的结构。
+type RerankResponse struct {
+	ID         string         `json:"id"`                    // 请求的唯一标识符
+	Results    []RerankResult `json:"results"`               // 按相关性从高到低排列的结果
+	TokenUsage Usage          `json:"token_usage,omitempty"` // Token 使用情况 (如果平台提供)
+}
+
+// RerankResult 是单个候选文档的排序结果。
+type RerankResult struct {
+	Index          int     `json:"index"`              // 文档在 RerankRequest.Documents 中的位置
+	RelevanceScore float64 `json:"relevance_score"`    // 相关性分数，越大越相关，取值范围由平台决定
+	Document       string  `json:"document,omitempty"` // 文档原文，仅在 ReturnDocuments 为 true 时返回
+}
@@ -0,0 +1,40 @@
+// models/tts.go
+package models
+
+import "io"
+
+// AudioFormat 是音频的编码格式，具体支持的格式取决于平台。
+type AudioFormat string
+
+const (
+	AudioFormatMP3     AudioFormat = "mp3"
+	AudioFormatWAV     AudioFormat = "wav"
+	AudioFormatPCM     AudioFormat = "pcm"
+	AudioFormatOggOpus AudioFormat = "ogg_opus"
+)
+
+// TTSRequest 定义了语音合成请求的结构。
+type TTSRequest struct {
+	Text                   string                 `json:"text"`                               // 待合成的文本
+	Model                  string                 `json:"model,omitempty"`                    // 平台特定的模型 (或资源) ID 或通用别名
+	Voice                  string                 `json:"voice"`                              // 音色 ID
+	Format                 AudioFormat            `json:"format,omitempty"`                   // 输出音频格式，零值表示平台默认
+	SampleRate             int                    `json:"sample_rate,omitempty"`              // 采样率 (Hz)，零值表示平台默认
+	Speed                  float64                `json:"speed,omitempty"`                    // 语速倍率，1.0 为正常语速，零值表示平台默认
+	Emotion                string                 `json:"emotion,omitempty"`                  // 情感，例如 "happy"，仅部分音色支持
+	Stream                 bool                   `json:"stream,omitempty"`                   // 是否以流的形式返回音频
+	PlatformSpecificParams map[string]interface{} `json:"platform_specific_params,omitempty"` // 用于覆盖平台特定参数
+}
+
+// TTSResponse 定义了语音合成响应的结构。
+// 非流式请求的音频在 Audio 中；流式请求的音频通过 AudioStream 边合成边读取。
+type TTSResponse struct {
+	ID         string      `json:"id"`                    // 请求的唯一标识符
+	Format     AudioFormat `json:"format"`                // 音频格式
+	SampleRate int         `json:"sample_rate,omitempty"` // 采样率 (Hz)，平台未返回时为请求中的值
+	Audio      []byte      `json:"audio,omitempty"`       // 完整的音频数据 (非流式)
+
+	// AudioStream 是流式请求的音频数据，读到 io.EOF 表示合成完成，
+	// 合成中途失败时 Read 返回 SDK 错误。调用方负责关闭。
+	AudioStream io.ReadCloser `json:"-"`
+}
@@ -3,12 +3,13 @@ package platform
 
 import (
 	"context"
+	"time"
 
 	"github.com/hewenyu/modelbridge/models" // 假设的 module 路径，请根据实际情况修改
 )
 
 // PlatformHandler 定义了与特定大模型平台交互的通用接口。
-// 每个受支持的平台都需要实现此接口。
+// 每个受支持的平台都需要实现此接口；排序、语音与审核等并非所有平台都提供的能力通过下方的可选接口声明。
 type PlatformHandler interface {
 	// TextGeneration 执行文本生成任务。
 	TextGeneration(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error)
@@ -19,30 +20,118 @@ type PlatformHandler interface {
 	// Embedding 执行向量嵌入任务。
 	Embedding(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error)
 
-	// ... 未来可以根据需要添加更多模型操作的方法，例如：
-	// AudioTranscription(ctx context.Context, req *models.AudioTranscriptionRequest) (*models.AudioTranscriptionResponse, error)
-	// TextToSpeech(ctx context.Context, req *models.TTSRequest) (*models.TTSResponse, error)
+	// ... 未来可以根据需要添加更多模型操作的方法
 
 	// GetPlatformInfo 返回平台相关信息，例如平台名称、支持的模型等。
 	// GetPlatformInfo() PlatformInfo // PlatformInfo 结构体待定义
 }
 
+// RerankHandler 是提供排序 (重排) 模型的平台处理器可选实现的接口。
+// 未实现时 client.Rerank 返回 ErrUnsupportedOperation 错误。
+type RerankHandler interface {
+	// Rerank 按与查询的相关性对候选文档重新排序。
+	Rerank(ctx context.Context, req *models.RerankRequest) (*models.RerankResponse, error)
+}
+
+// SpeechHandler 是提供语音合成的平台处理器可选实现的接口。
+type SpeechHandler interface {
+	// TextToSpeech 执行语音合成任务，req.Stream 为 true 时通过 TTSResponse.AudioStream 返回音频流。
+	TextToSpeech(ctx context.Context, req *models.TTSRequest) (*models.TTSResponse, error)
+}
+
+// TranscriptionHandler 是提供语音识别的平台处理器可选实现的接口。
+type TranscriptionHandler interface {
+	// AudioTranscription 执行语音识别任务，req.AudioStream 不为 nil 时为流式识别。
+	AudioTranscription(ctx context.Context, req *models.AudioTranscriptionRequest) (*models.AudioTranscriptionResponse, error)
+}
+
+// ModerationHandler 是提供内容审核的平台处理器可选实现的接口。
+type ModerationHandler interface {
+	// Moderation 执行内容审核任务。
+	Moderation(ctx context.Context, req *models.ModerationRequest) (*models.ModerationResponse, error)
+}
+
+// ContextCacheHandler 是支持上下文缓存的平台处理器可选实现的接口。
+// 创建缓存后，在 TextGenerationRequest.ContextCacheID 中引用缓存 ID 即可复用缓存内容。
+type ContextCacheHandler interface {
+	// CreateContextCache 根据消息前缀创建上下文缓存。
+	CreateContextCache(ctx context.Context, req *models.ContextCacheRequest) (*models.ContextCache, error)
+}
+
+// BatchHandler 是支持批量推理任务的平台处理器可选实现的接口。
+// 平台不支持任务的某种配置 (例如操作类型) 时，CreateBatchJob 应返回 ErrUnsupportedOperation 错误，
+// 此时客户端会改用本地并发执行。
+type BatchHandler interface {
+	// CreateBatchJob 提交批量任务，任务在后台异步执行。
+	CreateBatchJob(ctx context.Context, req *models.BatchJobRequest) (*models.BatchJob, error)
+	// GetBatchJob 查询批量任务的状态。
+	GetBatchJob(ctx context.Context, id string) (*models.BatchJob, error)
+	// CancelBatchJob 取消尚未结束的批量任务。
+	CancelBatchJob(ctx context.Context, id string) error
+	// BatchResults 返回已结束任务中每个请求的结果。
+	BatchResults(ctx context.Context, id string) ([]models.BatchResult, error)
+}
+
+// EmbeddingLimiter 是平台处理器可选实现的接口，用于声明向量嵌入接口的输入限制，
+// 客户端据此将大量输入切分为多个请求。
+type EmbeddingLimiter interface {
+	// EmbeddingLimits 返回模型的输入限制，字段为 0 表示不限制或未知。
+	EmbeddingLimits(model string) models.EmbeddingLimits
+}
+
 // Provider 是用于标识不同大模型平台的类型。
 type Provider string
 
 const (
 	ProviderVolcengine Provider = "volcengine"
 	ProviderAlibaba    Provider = "alibaba"
+	ProviderRerank     Provider = "rerank" // 兼容 /rerank 接口的排序服务，例如 vLLM、Xinference、Jina、Cohere
 	// 可以根据需要添加更多平台
 )
 
+// KnownProviders 返回 SDK 支持的所有平台提供商，按名称排序。
+func KnownProviders() []Provider {
+	return []Provider{ProviderAlibaba, ProviderRerank, ProviderVolcengine}
+}
+
 // PlatformConfig 用于配置特定平台的客户端。
 // 它在 `GETTING_STARTED.md` 中已有初步定义。
 type PlatformConfig struct {
-	Provider    Provider          `json:"provider"`    // 平台提供商
-	Credentials map[string]string `json:"credentials"` // 平台凭证，例如 API Key, Secret Key 等
-	// 其他平台特定的配置项，例如 Region, Endpoint 等
-	// SpecificConfig map[string]interface{} `json:"specific_config,omitempty"`
+	Provider       Provider          `json:"provider"`                  // 平台提供商
+	Credentials    map[string]string `json:"credentials"`               // 平台凭证，例如 API Key, Secret Key 等
+	Timeout        time.Duration     `json:"timeout,omitempty"`         // 可选，单次 HTTP 请求的超时时间，零值表示使用平台默认值
+	SpecificConfig ProviderSettings  `json:"specific_config,omitempty"` // 可选，平台特定的配置项，例如 Region, Endpoint 等
+}
+
+// ProviderSettings 包含平台特定的可选配置项。零值字段表示使用平台默认值。
+type ProviderSettings struct {
+	// BaseURL 覆盖平台默认的 API 根地址 (不含具体接口路径)，
+	// 例如企业出口代理或测试用的本地服务。设置后 Region 与 APIVersion 不再参与地址拼接。
+	BaseURL    string            `json:"base_url,omitempty"`
+	Region     string            `json:"region,omitempty"`      // 平台区域，例如火山方舟的 "cn-beijing"
+	APIVersion string            `json:"api_version,omitempty"` // API 版本，例如火山方舟的 "v3"
+	Headers    map[string]string `json:"headers,omitempty"`     // 附加到每个请求上的自定义请求头
+	ProxyURL   string            `json:"proxy_url,omitempty"`   // HTTP 代理地址，例如企业出口代理；为空时沿用 HTTP(S)_PROXY 环境变量
+
+	TLS            TLSSettings            `json:"tls,omitempty"`             // TLS 设置，例如企业内部的根证书
+	ConnectionPool ConnectionPoolSettings `json:"connection_pool,omitempty"` // 连接池设置
+}
+
+// TLSSettings 描述了从文件加载的 TLS 配置，零值表示使用系统默认设置。
+type TLSSettings struct {
+	CAFile             string `json:"ca_file,omitempty"`              // PEM 格式的根证书文件，为空时使用系统根证书
+	CertFile           string `json:"cert_file,omitempty"`            // PEM 格式的客户端证书文件，需与 KeyFile 同时设置
+	KeyFile            string `json:"key_file,omitempty"`             // PEM 格式的客户端私钥文件
+	ServerName         string `json:"server_name,omitempty"`          // 覆盖用于校验服务端证书的主机名
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"` // 跳过服务端证书校验，仅用于测试环境
+}
+
+// ConnectionPoolSettings 描述了 HTTP 连接池的配置，零值字段表示沿用默认设置。
+type ConnectionPoolSettings struct {
+	MaxIdleConns        int           `json:"max_idle_conns,omitempty"`          // 所有主机的最大空闲连接数
+	MaxIdleConnsPerHost int           `json:"max_idle_conns_per_host,omitempty"` // 每个主机的最大空闲连接数
+	MaxConnsPerHost     int           `json:"max_conns_per_host,omitempty"`      // 每个主机的最大连接数 (包含活跃连接)
+	IdleConnTimeout     time.Duration `json:"idle_conn_timeout,omitempty"`       // 空闲连接的保持时间
 }
 
 // (可以考虑将 Provider 和 PlatformConfig 移至 client 包或一个更通用的 config 包，
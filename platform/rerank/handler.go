@@ -0,0 +1,191 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+	"github.com/hewenyu/modelbridge/utils"
+)
+
+const (
+	rerankAPIKeyName = "apiKey"  // 可选凭证，非空时以 Bearer 方式认证
+	rerankPath       = "/rerank" // Jina、Cohere、vLLM、Xinference 等使用的排序接口路径
+	DefaultTimeout   = 10 * time.Second
+)
+
+// RerankHandler 调用兼容 /rerank 接口的排序服务，例如自建的 vLLM、Xinference 或 Jina、Cohere 的托管服务。
+// 它只提供 Rerank，其余操作返回 ErrUnsupportedOperation 错误。
+type RerankHandler struct {
+	apiKey     string
+	baseURL    string            // 服务根地址，不含 /rerank 路径
+	headers    map[string]string // 附加到每个请求上的自定义请求头
+	httpClient utils.HTTPClient  // 所有请求都经由此客户端发送
+	api        *utils.APIClient  // 基于 httpClient 的 JSON 请求封装
+
+	// 以下字段仅在 NewHandler 中用于构建 httpClient
+	timeout          time.Duration
+	transport        http.RoundTripper
+	transportConfig  utils.TransportConfig
+	customHTTPClient utils.HTTPClient
+	hooks            utils.Hooks
+}
+
+// NewHandler 创建一个新的 RerankHandler 实例。
+// config.SpecificConfig.BaseURL 为必填项，config.Credentials 中的 apiKey 为可选项。
+func NewHandler(config *platform.PlatformConfig, opts ...Option) (*RerankHandler, error) {
+	if config == nil {
+		return nil, errors.New(errors.ErrCodeConfiguration, "rerank handler: platform config cannot be nil")
+	}
+	baseURL := config.SpecificConfig.BaseURL
+	u, err := url.Parse(baseURL)
+	if baseURL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("rerank handler: a valid base URL is required, got %q", baseURL))
+	}
+
+	timeout := DefaultTimeout
+	if config.Timeout > 0 {
+		timeout = config.Timeout
+	}
+	handler := &RerankHandler{
+		apiKey:  config.Credentials[rerankAPIKeyName],
+		baseURL: strings.TrimRight(baseURL, "/"),
+		headers: make(map[string]string, len(config.SpecificConfig.Headers)),
+		timeout: timeout,
+	}
+
+	if config.SpecificConfig.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.SpecificConfig.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("rerank handler: invalid proxy URL %q", config.SpecificConfig.ProxyURL))
+		}
+		handler.transportConfig.Proxy = http.ProxyURL(proxyURL)
+	}
+	tlsSettings := config.SpecificConfig.TLS
+	tlsConfig, err := utils.TLSFiles{
+		CAFile:             tlsSettings.CAFile,
+		CertFile:           tlsSettings.CertFile,
+		KeyFile:            tlsSettings.KeyFile,
+		ServerName:         tlsSettings.ServerName,
+		InsecureSkipVerify: tlsSettings.InsecureSkipVerify,
+	}.Load()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfiguration, "rerank handler: invalid TLS settings")
+	}
+	handler.transportConfig.TLSConfig = tlsConfig
+	pool := config.SpecificConfig.ConnectionPool
+	handler.transportConfig.MaxIdleConns = pool.MaxIdleConns
+	handler.transportConfig.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+	handler.transportConfig.MaxConnsPerHost = pool.MaxConnsPerHost
+	handler.transportConfig.IdleConnTimeout = pool.IdleConnTimeout
+
+	for k, v := range config.SpecificConfig.Headers {
+		handler.headers[k] = v
+	}
+
+	for _, opt := range opts {
+		opt(handler)
+	}
+
+	if handler.customHTTPClient != nil {
+		handler.httpClient, err = utils.ApplyTransportConfig(handler.customHTTPClient, handler.transportConfig)
+	} else {
+		handler.httpClient, err = utils.NewHTTPClient(handler.timeout, handler.transport, handler.transportConfig)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfiguration, "rerank handler: failed to configure HTTP transport")
+	}
+	handler.api = &utils.APIClient{
+		HTTPClient:  handler.httpClient,
+		Platform:    "rerank",
+		DecodeError: decodeRerankError,
+		Hooks:       handler.hooks,
+	}
+	return handler, nil
+}
+
+// requestHeaders 返回自定义请求头与认证信息。配置了 apiKey 时自定义请求头不会覆盖 Authorization。
+func (h *RerankHandler) requestHeaders() map[string]string {
+	headers := make(map[string]string, len(h.headers)+1)
+	for k, v := range h.headers {
+		headers[k] = v
+	}
+	if h.apiKey != "" {
+		headers["Authorization"] = "Bearer " + h.apiKey
+	}
+	return headers
+}
+
+// decodeRerankError 解析排序服务的错误响应体。不同服务的格式不一致，依次尝试
+// OpenAI 风格的 {"error": {...}}、{"error": "..."} 以及 FastAPI 风格的 {"detail": "..."} 与 {"message": "..."}。
+func decodeRerankError(statusCode int, body []byte) *errors.Error {
+	var errResp struct {
+		Error   json.RawMessage `json:"error"`
+		Detail  json.RawMessage `json:"detail"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(body, &errResp) != nil {
+		return nil
+	}
+	var detail struct {
+		Message string      `json:"message"`
+		Type    string      `json:"type"`
+		Code    interface{} `json:"code"`
+	}
+	var message string
+	switch {
+	case json.Unmarshal(errResp.Error, &detail) == nil && detail.Message != "":
+		message = detail.Message
+	case json.Unmarshal(errResp.Error, &message) == nil && message != "":
+	case json.Unmarshal(errResp.Detail, &message) == nil && message != "":
+	case len(errResp.Detail) > 0 && string(errResp.Detail) != "null":
+		// FastAPI 的参数校验错误以数组形式返回，原样保留。
+		message = string(errResp.Detail)
+	default:
+		message = errResp.Message
+	}
+	if message == "" {
+		return nil
+	}
+	sdkErr := errors.New(utils.HTTPStatusErrorCode(statusCode), fmt.Sprintf("rerank API error: status %d, message: %s", statusCode, message))
+	if detail.Type != "" || detail.Code != nil {
+		sdkErr.PlatformDetails = map[string]interface{}{"code": detail.Code, "type": detail.Type}
+	}
+	return sdkErr
+}
+
+// TextGeneration 排序服务不提供文本生成，返回 ErrUnsupportedOperation 错误。
+func (h *RerankHandler) TextGeneration(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
+	return nil, errors.New(errors.ErrCodeUnsupported, "rerank handler: text generation is not supported")
+}
+
+// ImageGeneration 排序服务不提供图片生成，返回 ErrUnsupportedOperation 错误。
+func (h *RerankHandler) ImageGeneration(ctx context.Context, req *models.ImageGenerationRequest) (*models.ImageGenerationResponse, error) {
+	return nil, errors.New(errors.ErrCodeUnsupported, "rerank handler: image generation is not supported")
+}
+
+// Embedding 排序服务不提供向量化，返回 ErrUnsupportedOperation 错误。
+func (h *RerankHandler) Embedding(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
+	return nil, errors.New(errors.ErrCodeUnsupported, "rerank handler: embedding is not supported")
+}
+
+// compile-time check to ensure RerankHandler implements PlatformHandler
+var (
+	_ platform.PlatformHandler = (*RerankHandler)(nil)
+	_ platform.RerankHandler   = (*RerankHandler)(nil)
+)
+
+// init registers the RerankHandler with the platform registry.
+func init() {
+	constructor := func(config *platform.PlatformConfig) (platform.PlatformHandler, error) {
+		return NewHandler(config)
+	}
+	platform.RegisterHandler(string(platform.ProviderRerank), constructor)
+}
@@ -0,0 +1,64 @@
+package rerank
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/hewenyu/modelbridge/utils"
+)
+
+// Option 是用于配置 RerankHandler 的选项。
+type Option func(*RerankHandler)
+
+// WithTimeout 设置 HTTP 请求的超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(h *RerankHandler) {
+		h.timeout = timeout
+	}
+}
+
+// WithHTTPClient 设置自定义的 HTTPClient，设置后 WithTimeout 与 WithTransport 不再生效；
+// 代理、TLS 与连接池设置只能应用于 Transport 为 *http.Transport 的 *http.Client，否则 NewHandler 返回配置错误。
+func WithHTTPClient(client utils.HTTPClient) Option {
+	return func(h *RerankHandler) {
+		h.customHTTPClient = client
+	}
+}
+
+// WithTransport 设置底层的 http.RoundTripper。同时设置代理、TLS 或连接池时 transport 必须是 *http.Transport。
+func WithTransport(transport http.RoundTripper) Option {
+	return func(h *RerankHandler) {
+		h.transport = transport
+	}
+}
+
+// WithTLSConfig 设置 TLS 配置，例如企业内部的根证书。
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(h *RerankHandler) {
+		h.transportConfig.TLSConfig = tlsConfig
+	}
+}
+
+// WithConnectionPool 调整连接池参数，零值表示沿用默认设置。
+func WithConnectionPool(maxIdleConns, maxIdleConnsPerHost int, idleConnTimeout time.Duration) Option {
+	return func(h *RerankHandler) {
+		h.transportConfig.MaxIdleConns = maxIdleConns
+		h.transportConfig.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		h.transportConfig.IdleConnTimeout = idleConnTimeout
+	}
+}
+
+// WithMaxConnsPerHost 限制每个主机的最大连接数 (包含活跃连接)，零值表示不限制。
+func WithMaxConnsPerHost(n int) Option {
+	return func(h *RerankHandler) {
+		h.transportConfig.MaxConnsPerHost = n
+	}
+}
+
+// WithHooks 设置请求生命周期回调，例如记录每次 HTTP 调用的日志。
+func WithHooks(hooks utils.Hooks) Option {
+	return func(h *RerankHandler) {
+		h.hooks = hooks
+	}
+}
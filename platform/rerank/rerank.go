@@ -0,0 +1,102 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/utils"
+)
+
+// rerankRequest 是 /rerank 接口的请求体结构。
+type rerankRequest struct {
+	Model           string   `json:"model"`
+	Query           string   `json:"query"`
+	Documents       []string `json:"documents"`
+	TopN            int      `json:"top_n,omitempty"`
+	ReturnDocuments bool     `json:"return_documents,omitempty"`
+}
+
+// rerankResponse 是 /rerank 接口的响应体结构。
+type rerankResponse struct {
+	ID      string `json:"id"`
+	Results []struct {
+		Index          int             `json:"index"`
+		RelevanceScore float64         `json:"relevance_score"`
+		Document       json.RawMessage `json:"document,omitempty"` // 字符串或 {"text": "..."}
+	} `json:"results"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Rerank 调用排序服务的 /rerank 接口，返回的结果按相关性从高到低排列。
+func (h *RerankHandler) Rerank(ctx context.Context, req *models.RerankRequest) (*models.RerankResponse, error) {
+	if req == nil || req.Model == "" || req.Query == "" || len(req.Documents) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "rerank handler: rerank request requires a model, a query and at least one document")
+	}
+
+	var apiResp rerankResponse
+	if _, err := h.api.DoJSON(ctx, &utils.Request{
+		Method: http.MethodPost,
+		URL:    h.baseURL + rerankPath,
+		Header: h.requestHeaders(),
+		Body: rerankRequest{
+			Model:           req.Model,
+			Query:           req.Query,
+			Documents:       req.Documents,
+			TopN:            req.TopN,
+			ReturnDocuments: req.ReturnDocuments,
+		},
+	}, &apiResp); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.RerankResult, 0, len(apiResp.Results))
+	for _, r := range apiResp.Results {
+		if r.Index < 0 || r.Index >= len(req.Documents) {
+			return nil, errors.New(errors.ErrCodeInvalidResponse, fmt.Sprintf("rerank handler: result index %d out of range", r.Index))
+		}
+		result := models.RerankResult{Index: r.Index, RelevanceScore: r.RelevanceScore}
+		if req.ReturnDocuments {
+			// 部分服务不回传原文，此时使用请求中的文档。
+			result.Document = documentText(r.Document)
+			if result.Document == "" {
+				result.Document = req.Documents[r.Index]
+			}
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].RelevanceScore > results[j].RelevanceScore })
+	if req.TopN > 0 && len(results) > req.TopN {
+		results = results[:req.TopN]
+	}
+	return &models.RerankResponse{
+		ID:      apiResp.ID,
+		Results: results,
+		TokenUsage: models.Usage{
+			PromptTokens: apiResp.Usage.PromptTokens,
+			TotalTokens:  apiResp.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// documentText 解析字符串或 {"text": "..."} 形式的文档原文。
+func documentText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var doc struct {
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(raw, &doc) == nil {
+		return doc.Text
+	}
+	return ""
+}
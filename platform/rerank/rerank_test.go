@@ -0,0 +1,158 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+// newTestHandler 创建请求发往 httptest 服务的处理器，apiKey 为空时不配置凭证。
+func newTestHandler(t *testing.T, apiKey string, handler http.HandlerFunc) *RerankHandler {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	h, err := NewHandler(&platform.PlatformConfig{
+		Provider:       platform.ProviderRerank,
+		Credentials:    map[string]string{"apiKey": apiKey},
+		SpecificConfig: platform.ProviderSettings{BaseURL: server.URL + "/v1/", Headers: map[string]string{"X-Team": "search"}},
+	})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return h
+}
+
+func TestRerank(t *testing.T) {
+	var got rerankRequest
+	h := newTestHandler(t, "rerank-key", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/rerank" || r.Header.Get("Authorization") != "Bearer rerank-key" || r.Header.Get("X-Team") != "search" {
+			t.Errorf("request = %s %v", r.URL.Path, r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		// 结果未按分数排序，文档原文混用字符串、对象与缺省三种形式。
+		fmt.Fprint(w, `{"id":"r1","results":[
+			{"index":2,"relevance_score":0.1},
+			{"index":0,"relevance_score":0.9,"document":{"text":"a"}},
+			{"index":1,"relevance_score":0.5,"document":"b"}
+		],"usage":{"total_tokens":12}}`)
+	})
+
+	req := &models.RerankRequest{Model: "bge-reranker", Query: "q", Documents: []string{"a", "b", "c"}, TopN: 2, ReturnDocuments: true}
+	resp, err := h.Rerank(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	wantBody := rerankRequest{Model: "bge-reranker", Query: "q", Documents: []string{"a", "b", "c"}, TopN: 2, ReturnDocuments: true}
+	if !reflect.DeepEqual(got, wantBody) {
+		t.Errorf("body = %+v, want %+v", got, wantBody)
+	}
+	want := []models.RerankResult{
+		{Index: 0, RelevanceScore: 0.9, Document: "a"},
+		{Index: 1, RelevanceScore: 0.5, Document: "b"},
+	}
+	if resp.ID != "r1" || !reflect.DeepEqual(resp.Results, want) || resp.TokenUsage.TotalTokens != 12 {
+		t.Errorf("resp = %+v", resp)
+	}
+
+	// 服务不回传原文时使用请求中的文档。
+	req.TopN = 0
+	resp, err = h.Rerank(context.Background(), req)
+	if err != nil || len(resp.Results) != 3 || resp.Results[2].Document != "c" {
+		t.Errorf("resp = %+v, err = %v", resp, err)
+	}
+}
+
+func TestRerankErrors(t *testing.T) {
+	req := &models.RerankRequest{Model: "m", Query: "q", Documents: []string{"a"}}
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"index out of range", http.StatusOK, `{"results":[{"index":1,"relevance_score":1}]}`, errors.ErrCodeInvalidResponse},
+		{"malformed body", http.StatusOK, `{"results":`, errors.ErrCodeInvalidResponse},
+		{"rate limited", http.StatusTooManyRequests, `{"error":{"message":"slow down"}}`, errors.ErrCodeRateLimited},
+		{"unknown model", http.StatusNotFound, `{"detail":"model m not found"}`, errors.ErrCodeNotFound},
+		{"bad request", http.StatusBadRequest, `{"detail":[{"loc":["body","query"],"msg":"field required"}]}`, errors.ErrCodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, "", func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "" {
+					t.Errorf("Authorization = %q, want none without an API key", r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			})
+			if _, err := h.Rerank(context.Background(), req); !errors.IsSDKError(err, tt.want) {
+				t.Errorf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	h := newTestHandler(t, "", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("invalid requests must not be sent")
+	})
+	if _, err := h.Rerank(context.Background(), &models.RerankRequest{Model: "m", Query: "q"}); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("no documents: err = %v, want %s", err, errors.ErrCodeInvalidRequest)
+	}
+	if _, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: "hi"}); !errors.IsSDKError(err, errors.ErrCodeUnsupported) {
+		t.Errorf("text generation: err = %v, want %s", err, errors.ErrCodeUnsupported)
+	}
+}
+
+func TestDecodeRerankError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string // 为空表示无法识别
+	}{
+		{name: "openai style", body: `{"error":{"message":"bad input","type":"invalid_request_error"}}`, want: "bad input"},
+		{name: "error string", body: `{"error":"bad input"}`, want: "bad input"},
+		{name: "detail string", body: `{"detail":"bad input"}`, want: "bad input"},
+		{name: "message", body: `{"message":"bad input"}`, want: "bad input"},
+		{name: "unknown shape", body: `{"status":"failed"}`},
+		{name: "not json", body: `Internal Server Error`},
+	}
+	for _, tt := range tests {
+		sdkErr := decodeRerankError(http.StatusBadRequest, []byte(tt.body))
+		if tt.want == "" {
+			if sdkErr != nil {
+				t.Errorf("%s: err = %v, want nil", tt.name, sdkErr)
+			}
+			continue
+		}
+		if sdkErr == nil || sdkErr.Code != errors.ErrCodeInvalidRequest || !strings.Contains(sdkErr.Message, tt.want) {
+			t.Errorf("%s: err = %v, want %s with %q", tt.name, sdkErr, errors.ErrCodeInvalidRequest, tt.want)
+		}
+	}
+}
+
+func TestNewHandlerInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *platform.PlatformConfig
+	}{
+		{name: "nil config"},
+		{name: "missing base URL", config: &platform.PlatformConfig{Provider: platform.ProviderRerank}},
+		{name: "base URL without scheme", config: &platform.PlatformConfig{Provider: platform.ProviderRerank, SpecificConfig: platform.ProviderSettings{BaseURL: "rerank.internal:8000"}}},
+		{name: "invalid proxy", config: &platform.PlatformConfig{Provider: platform.ProviderRerank, SpecificConfig: platform.ProviderSettings{BaseURL: "http://rerank.internal", ProxyURL: "://proxy"}}},
+	}
+	for _, tt := range tests {
+		if _, err := NewHandler(tt.config); !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+			t.Errorf("%s: err = %v, want %s", tt.name, err, errors.ErrCodeConfiguration)
+		}
+	}
+}
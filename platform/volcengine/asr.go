@@ -0,0 +1,169 @@
+package volcengine
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/utils"
+)
+
+const (
+	volcengineASRFlashPath = "/api/v3/auc/bigmodel/recognize/flash"
+	// DefaultASRResourceID 是录音文件识别 (极速版) 未指定 Model 时使用的资源 ID。
+	DefaultASRResourceID = "volc.bigasr.auc_turbo"
+)
+
+// volcengineASRRequest 是豆包语音大模型识别接口的请求体，流式识别的首个请求也使用此结构。
+type volcengineASRRequest struct {
+	User    volcengineSpeechUser   `json:"user"`
+	Audio   volcengineASRAudio     `json:"audio"`
+	Request map[string]interface{} `json:"request"`
+}
+
+type volcengineASRAudio struct {
+	URL      string `json:"url,omitempty"`
+	Data     string `json:"data,omitempty"` // base64 编码的音频数据
+	Format   string `json:"format,omitempty"`
+	Codec    string `json:"codec,omitempty"`
+	Rate     int    `json:"rate,omitempty"`
+	Bits     int    `json:"bits,omitempty"`
+	Channel  int    `json:"channel,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// volcengineASRResponse 是识别结果，时间单位为毫秒。
+type volcengineASRResponse struct {
+	AudioInfo struct {
+		Duration int64 `json:"duration"`
+	} `json:"audio_info"`
+	Result struct {
+		Text       string                   `json:"text"`
+		Utterances []volcengineASRUtterance `json:"utterances"`
+	} `json:"result"`
+}
+
+type volcengineASRUtterance struct {
+	Text      string `json:"text"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Definite  bool   `json:"definite"`
+	Words     []struct {
+		Text      string `json:"text"`
+		StartTime int64  `json:"start_time"`
+		EndTime   int64  `json:"end_time"`
+	} `json:"words"`
+	Additions struct {
+		Speaker string `json:"speaker"`
+	} `json:"additions"`
+}
+
+// AudioTranscription 调用豆包语音的大模型录音文件识别 (极速版) 接口，音频通过 Audio 或 AudioURL 提供；
+// 设置 AudioStream 时改用流式识别接口。
+func (h *VolcengineHandler) AudioTranscription(ctx context.Context, req *models.AudioTranscriptionRequest) (*models.AudioTranscriptionResponse, error) {
+	if !h.hasSpeechCredentials() {
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("volcengine handler: audio transcription requires %s and %s in credentials", volcengineSpeechAppIDName, volcengineSpeechAccessKeyName))
+	}
+	if req.AudioStream != nil {
+		return h.streamAudioTranscription(ctx, req)
+	}
+	if (len(req.Audio) == 0) == (req.AudioURL == "") {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: audio transcription requires exactly one of audio, audio URL or audio stream")
+	}
+	resourceID := req.Model
+	if resourceID == "" {
+		resourceID = DefaultASRResourceID
+	}
+
+	volcReq := volcengineASRRequest{
+		User: volcengineSpeechUser{UID: "modelbridge"},
+		Audio: volcengineASRAudio{
+			URL:      req.AudioURL,
+			Format:   string(req.Format),
+			Rate:     req.SampleRate,
+			Language: req.Language,
+		},
+		Request: asrRequestOpts(req),
+	}
+	if len(req.Audio) > 0 {
+		volcReq.Audio.Data = base64.StdEncoding.EncodeToString(req.Audio)
+	}
+
+	requestID := newSpeechRequestID()
+	headers := h.speechHeaders("X-Api-App-Key", resourceID, requestID)
+	headers["X-Api-Sequence"] = "-1"
+	var volcResp volcengineASRResponse
+	meta, err := h.speechAPI.DoJSON(ctx, &utils.Request{
+		Method: http.MethodPost,
+		URL:    h.speechURL + volcengineASRFlashPath,
+		Header: headers,
+		Body:   volcReq,
+	}, &volcResp)
+	if err != nil {
+		return nil, err
+	}
+	// 极速版接口通过响应头返回处理状态，HTTP 状态码为 200 时也可能失败。
+	if code, _ := strconv.Atoi(meta.Header.Get("X-Api-Status-Code")); code != 0 {
+		status := volcengineSpeechStatus{Code: code, Message: meta.Header.Get("X-Api-Message")}
+		if err := status.err("audio transcription"); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := volcResp.toAudioTranscriptionResponse(req)
+	resp.ID = requestID
+	return resp, nil
+}
+
+// asrRequestOpts 根据通用请求构建识别参数，默认开启文本规范化与标点。
+// PlatformSpecificParams 中的同名参数会覆盖默认值，例如 {"enable_itn": false}。
+func asrRequestOpts(req *models.AudioTranscriptionRequest) map[string]interface{} {
+	opts := map[string]interface{}{
+		"model_name":      "bigmodel",
+		"enable_itn":      true,
+		"enable_punc":     true,
+		"show_utterances": true,
+	}
+	if req.SpeakerDiarization {
+		opts["enable_speaker_info"] = true
+	}
+	for k, v := range req.PlatformSpecificParams {
+		opts[k] = v
+	}
+	return opts
+}
+
+// toAudioTranscriptionResponse 将识别结果转换为通用响应，逐词时间戳与说话人按请求选项保留。
+func (r *volcengineASRResponse) toAudioTranscriptionResponse(req *models.AudioTranscriptionRequest) *models.AudioTranscriptionResponse {
+	resp := &models.AudioTranscriptionResponse{
+		Text:     r.Result.Text,
+		Duration: time.Duration(r.AudioInfo.Duration) * time.Millisecond,
+	}
+	for _, u := range r.Result.Utterances {
+		seg := models.TranscriptionSegment{
+			Start:    time.Duration(u.StartTime) * time.Millisecond,
+			End:      time.Duration(u.EndTime) * time.Millisecond,
+			Text:     u.Text,
+			Definite: u.Definite,
+		}
+		if req.SpeakerDiarization {
+			seg.Speaker = u.Additions.Speaker
+		}
+		if req.Timestamps {
+			for _, w := range u.Words {
+				seg.Words = append(seg.Words, models.TranscriptWord{
+					Start: time.Duration(w.StartTime) * time.Millisecond,
+					End:   time.Duration(w.EndTime) * time.Millisecond,
+					Text:  w.Text,
+				})
+			}
+		}
+		resp.Segments = append(resp.Segments, seg)
+	}
+	return resp
+}
@@ -0,0 +1,253 @@
+package volcengine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/utils"
+)
+
+const (
+	volcengineASRStreamPath = "/api/v3/sauc/bigmodel"
+	// DefaultStreamingASRResourceID 是流式识别未指定 Model 时使用的资源 ID。
+	DefaultStreamingASRResourceID = "volc.bigasr.sauc.duration"
+	// volcengineASRStreamChunkBytes 是每个音频包的大小，对应 16kHz 16bit 单声道 pcm 的 200ms。
+	volcengineASRStreamChunkBytes = 6400
+)
+
+// 流式识别使用豆包语音的二进制协议：4 字节头部 (版本与头部长度、消息类型与标志、序列化与压缩方式、保留)，
+// 之后是可选的 4 字节序号、4 字节负载长度与负载。
+const (
+	asrProtocolHeader = 0x11 // 协议版本 1，头部长度 1 * 4 字节
+
+	asrMsgFullClientRequest  = 0x1
+	asrMsgAudioOnlyRequest   = 0x2
+	asrMsgFullServerResponse = 0x9
+	asrMsgError              = 0xf
+
+	asrFlagSequence = 0x1 // 负载前带有序号
+	asrFlagLast     = 0x2 // 最后一个包
+
+	asrSerializationNone = 0x0
+	asrSerializationJSON = 0x1
+	asrCompressionGzip   = 0x1
+)
+
+// streamAudioTranscription 通过 WebSocket 调用豆包语音的大模型流式识别接口。
+// 音频按 volcengineASRStreamChunkBytes 切分后边读边发，每收到一次识别结果调用一次 OnStreamChunk。
+// 方法返回后发送音频的 goroutine 不再读取 AudioStream，但无法中断正在进行的 Read，
+// 它会在该次 Read 返回后退出。
+func (h *VolcengineHandler) streamAudioTranscription(ctx context.Context, req *models.AudioTranscriptionRequest) (*models.AudioTranscriptionResponse, error) {
+	if len(req.Audio) > 0 || req.AudioURL != "" {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: audio transcription requires exactly one of audio, audio URL or audio stream")
+	}
+	if req.SpeakerDiarization {
+		return nil, errors.New(errors.ErrCodeUnsupported, "volcengine handler: speaker diarization is not supported for streaming audio transcription")
+	}
+	resourceID := req.Model
+	if resourceID == "" {
+		resourceID = DefaultStreamingASRResourceID
+	}
+	payload, err := json.Marshal(volcengineASRRequest{
+		User:    volcengineSpeechUser{UID: "modelbridge"},
+		Audio:   streamingASRAudio(req),
+		Request: asrRequestOpts(req),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInvalidRequest, "volcengine handler: failed to marshal audio transcription request")
+	}
+
+	requestID := newSpeechRequestID()
+	headers := h.speechHeaders("X-Api-App-Key", resourceID, requestID)
+	headers["X-Api-Connect-Id"] = requestID
+	conn, meta, err := h.speechAPI.DialWebSocket(ctx, &utils.Request{URL: h.speechURL + volcengineASRStreamPath, Header: headers})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := writeASRFrame(conn, asrMsgFullClientRequest, 0, asrSerializationJSON, payload); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodePlatformError, "volcengine audio transcription: failed to send request")
+	}
+	sendErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done) // 先于 conn.Close 执行，发送方在下一次读取前退出
+	go func() {
+		err := sendASRAudio(conn, req.AudioStream, done)
+		sendErr <- err
+		if err != nil {
+			conn.Close() // 使读取结果的循环立即返回
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() == nil {
+				select {
+				case e := <-sendErr:
+					if e != nil {
+						return nil, e
+					}
+				default:
+				}
+			}
+			return nil, h.speechAPI.WrapStreamError(ctx, err, meta)
+		}
+		last, result, err := decodeASRFrame(data)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			continue
+		}
+		resp := result.toAudioTranscriptionResponse(req)
+		resp.ID = requestID
+		if req.OnStreamChunk != nil {
+			if err := req.OnStreamChunk(&models.AudioTranscriptionStreamChunk{Text: resp.Text, Segments: resp.Segments, IsFinal: last}); err != nil {
+				return nil, err
+			}
+		}
+		if last {
+			return resp, nil
+		}
+	}
+}
+
+// streamingASRAudio 返回流式识别的音频参数，未指定格式时按 16kHz 16bit 单声道 pcm 处理。
+func streamingASRAudio(req *models.AudioTranscriptionRequest) volcengineASRAudio {
+	audio := volcengineASRAudio{Rate: req.SampleRate, Bits: 16, Channel: 1, Language: req.Language}
+	if audio.Rate == 0 {
+		audio.Rate = 16000
+	}
+	switch req.Format {
+	case "", models.AudioFormatPCM:
+		audio.Format, audio.Codec = "pcm", "raw"
+	case models.AudioFormatOggOpus:
+		audio.Format, audio.Codec = "ogg", "opus"
+	default:
+		audio.Format = string(req.Format)
+	}
+	return audio
+}
+
+// sendASRAudio 从 r 读取音频并逐包发送，读到 io.EOF 时将最后一包标记为结束。
+// done 关闭后不再读取 r。
+func sendASRAudio(conn *utils.WebSocketConn, r io.Reader, done <-chan struct{}) error {
+	buf := make([]byte, volcengineASRStreamChunkBytes)
+	var pending []byte
+	started := false
+	for {
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if started {
+				if err := writeASRFrame(conn, asrMsgAudioOnlyRequest, 0, asrSerializationNone, pending); err != nil {
+					return errors.Wrap(err, errors.ErrCodePlatformError, "volcengine audio transcription: failed to send audio")
+				}
+			}
+			pending = append(pending[:0], buf[:n]...)
+			started = true
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			if err := writeASRFrame(conn, asrMsgAudioOnlyRequest, asrFlagLast, asrSerializationNone, pending); err != nil {
+				return errors.Wrap(err, errors.ErrCodePlatformError, "volcengine audio transcription: failed to send audio")
+			}
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInvalidRequest, "volcengine audio transcription: failed to read audio stream")
+		}
+	}
+}
+
+// writeASRFrame 以 gzip 压缩负载并按二进制协议发送一个包。
+func writeASRFrame(conn *utils.WebSocketConn, msgType, flags, serialization byte, payload []byte) error {
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write(payload); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	frame := make([]byte, 0, 8+compressed.Len())
+	frame = append(frame, asrProtocolHeader, msgType<<4|flags, serialization<<4|asrCompressionGzip, 0)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(compressed.Len()))
+	frame = append(frame, compressed.Bytes()...)
+	return conn.WriteMessage(utils.WebSocketBinary, frame)
+}
+
+// decodeASRFrame 解析服务端的包，返回是否为最后一个结果以及识别结果；错误包会转换为 SDK 错误。
+func decodeASRFrame(data []byte) (last bool, result *volcengineASRResponse, err error) {
+	if len(data) < 4 || int(data[0]&0x0f)*4 > len(data) {
+		return false, nil, errors.New(errors.ErrCodeInvalidResponse, "volcengine audio transcription: malformed response frame")
+	}
+	msgType, flags := data[1]>>4, data[1]&0x0f
+	compression := data[2] & 0x0f
+	body := data[int(data[0]&0x0f)*4:]
+
+	switch msgType {
+	case asrMsgError:
+		if len(body) < 8 {
+			return false, nil, errors.New(errors.ErrCodeInvalidResponse, "volcengine audio transcription: malformed error frame")
+		}
+		code := int(binary.BigEndian.Uint32(body))
+		message, _ := asrPayload(body[4:], 0)
+		return false, nil, volcengineSpeechStatus{Code: code, Message: string(message)}.err("audio transcription")
+	case asrMsgFullServerResponse:
+	default:
+		return false, nil, nil
+	}
+
+	if flags&asrFlagSequence != 0 {
+		if len(body) < 4 {
+			return false, nil, errors.New(errors.ErrCodeInvalidResponse, "volcengine audio transcription: malformed response frame")
+		}
+		body = body[4:]
+	}
+	payload, err := asrPayload(body, compression)
+	if err != nil {
+		return false, nil, err
+	}
+	result = &volcengineASRResponse{}
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, result); err != nil {
+			sdkErr := errors.Wrap(err, errors.ErrCodeInvalidResponse, "volcengine audio transcription: failed to unmarshal result")
+			sdkErr.PlatformDetails = map[string]interface{}{"payload": string(payload)}
+			return false, nil, sdkErr
+		}
+	}
+	return flags&asrFlagLast != 0, result, nil
+}
+
+// asrPayload 读取带 4 字节长度前缀的负载，按需解压。
+func asrPayload(body []byte, compression byte) ([]byte, error) {
+	if len(body) < 4 || uint64(binary.BigEndian.Uint32(body)) > uint64(len(body)-4) {
+		return nil, errors.New(errors.ErrCodeInvalidResponse, "volcengine audio transcription: malformed payload")
+	}
+	payload := body[4 : 4+binary.BigEndian.Uint32(body)]
+	if compression != asrCompressionGzip || len(payload) == 0 {
+		return payload, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInvalidResponse, "volcengine audio transcription: invalid gzip payload")
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInvalidResponse, "volcengine audio transcription: invalid gzip payload")
+	}
+	return out, nil
+}
@@ -0,0 +1,329 @@
+package volcengine
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// gzipBytes 压缩 data。
+func gzipBytes(data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
+
+// asrServerFrame 按二进制协议构造服务端的识别结果包，seq 不为 0 时带序号。
+func asrServerFrame(flags byte, seq int32, result string) []byte {
+	frame := []byte{asrProtocolHeader, asrMsgFullServerResponse<<4 | flags, asrSerializationJSON<<4 | asrCompressionGzip, 0}
+	if flags&asrFlagSequence != 0 {
+		frame = binary.BigEndian.AppendUint32(frame, uint32(seq))
+	}
+	payload := gzipBytes([]byte(result))
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+// asrErrorFrame 构造服务端的错误包，错误信息不压缩。
+func asrErrorFrame(code uint32, message string) []byte {
+	frame := []byte{asrProtocolHeader, asrMsgError << 4, asrSerializationJSON << 4, 0}
+	frame = binary.BigEndian.AppendUint32(frame, code)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(message)))
+	return append(frame, message...)
+}
+
+func TestDecodeASRFrame(t *testing.T) {
+	result := `{"audio_info":{"duration":1500},"result":{"text":"你好","utterances":[{"text":"你好","start_time":0,"end_time":800,"definite":true}]}}`
+	full := asrServerFrame(asrFlagSequence|asrFlagLast, -3, result)
+
+	last, got, err := decodeASRFrame(full)
+	if err != nil || !last || got.Result.Text != "你好" || got.AudioInfo.Duration != 1500 || len(got.Result.Utterances) != 1 {
+		t.Fatalf("decodeASRFrame = %v, %+v, %v", last, got, err)
+	}
+	last, got, err = decodeASRFrame(asrServerFrame(0, 0, result))
+	if err != nil || last || got.Result.Text != "你好" {
+		t.Errorf("without sequence: %v, %+v, %v", last, got, err)
+	}
+	uncompressed := []byte{asrProtocolHeader, asrMsgFullServerResponse << 4, asrSerializationJSON << 4, 0}
+	uncompressed = binary.BigEndian.AppendUint32(uncompressed, uint32(len(result)))
+	if _, got, err := decodeASRFrame(append(uncompressed, result...)); err != nil || got.Result.Text != "你好" {
+		t.Errorf("uncompressed: %+v, %v", got, err)
+	}
+	if last, got, err := decodeASRFrame([]byte{asrProtocolHeader, 0xb0, 0, 0}); last || got != nil || err != nil {
+		t.Errorf("unknown message type: %v, %+v, %v", last, got, err)
+	}
+
+	_, _, err = decodeASRFrame(asrErrorFrame(45000001, "bad audio"))
+	if !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("error frame: err = %v, want %s", err, errors.ErrCodeInvalidRequest)
+	}
+	if sdkErr, ok := err.(*errors.Error); !ok || sdkErr.PlatformDetails["code"] != 45000001 {
+		t.Errorf("error frame details = %v", err)
+	}
+
+	oversized := append([]byte(nil), full...)
+	binary.BigEndian.PutUint32(oversized[8:], uint32(len(full))) // 负载长度超过剩余字节
+	malformed := map[string][]byte{
+		"empty":                 {},
+		"short header":          {asrProtocolHeader, asrMsgFullServerResponse << 4},
+		"header size too large": {0x1f, asrMsgFullServerResponse << 4, 0, 0},
+		"missing sequence":      {asrProtocolHeader, asrMsgFullServerResponse<<4 | asrFlagSequence, 0, 0, 0, 0},
+		"missing payload size":  {asrProtocolHeader, asrMsgFullServerResponse << 4, 0, 0, 0, 0},
+		"truncated payload":     full[:len(full)-3],
+		"oversized payload":     oversized,
+		"short error frame":     asrErrorFrame(45000001, "")[:10],
+		"invalid gzip":          append([]byte{asrProtocolHeader, asrMsgFullServerResponse << 4, asrCompressionGzip, 0, 0, 0, 0, 3}, "abc"...),
+		"invalid JSON":          asrServerFrame(0, 0, `{"result":`),
+	}
+	for name, frame := range malformed {
+		if _, _, err := decodeASRFrame(frame); !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+			t.Errorf("%s: err = %v, want %s", name, err, errors.ErrCodeInvalidResponse)
+		}
+	}
+}
+
+func TestASRPayload(t *testing.T) {
+	body := binary.BigEndian.AppendUint32(nil, 3)
+	body = append(body, "abcdef"...)
+	if got, err := asrPayload(body, 0); err != nil || string(got) != "abc" {
+		t.Errorf("asrPayload = %q, %v, want the length-prefixed bytes only", got, err)
+	}
+	if got, err := asrPayload([]byte{0, 0, 0, 0}, asrCompressionGzip); err != nil || len(got) != 0 {
+		t.Errorf("empty gzip payload = %q, %v", got, err)
+	}
+	for _, bad := range [][]byte{nil, {0, 0, 0}, {0, 0, 0, 1}, {0xff, 0xff, 0xff, 0xff, 'a'}} {
+		if _, err := asrPayload(bad, 0); !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+			t.Errorf("asrPayload(%v): err = %v", bad, err)
+		}
+	}
+}
+
+// wsPeer 是测试用 WebSocket 服务端连接的最小实现：读取带掩码的客户端帧，发送不带掩码的服务端帧。
+type wsPeer struct {
+	t  *testing.T
+	rw *bufio.ReadWriter
+}
+
+func (p *wsPeer) read() (opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(p.rw, head[:]); err != nil {
+		return 0, nil, err
+	}
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(p.rw, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(p.rw, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if head[1]&0x80 == 0 {
+		p.t.Error("client frame is not masked")
+	}
+	var mask [4]byte
+	io.ReadFull(p.rw, mask[:])
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(p.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return head[0] & 0x0f, payload, nil
+}
+
+// readASR 读取一个客户端请求包，返回消息类型、标志、序列化方式与解压后的负载。
+func (p *wsPeer) readASR() (msgType, flags, serialization byte, payload []byte) {
+	p.t.Helper()
+	opcode, frame, err := p.read()
+	if err != nil || opcode != 2 || len(frame) < 8 || frame[0] != asrProtocolHeader || frame[2]&0x0f != asrCompressionGzip {
+		p.t.Fatalf("client frame: opcode %d, %x, %v", opcode, frame, err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(frame[8:]))
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	payload, err = io.ReadAll(zr)
+	if err != nil || int(binary.BigEndian.Uint32(frame[4:])) != len(frame)-8 {
+		p.t.Fatalf("client payload: %v", err)
+	}
+	return frame[1] >> 4, frame[1] & 0x0f, frame[2] >> 4, payload
+}
+
+func (p *wsPeer) write(data []byte) {
+	frame := []byte{0x80 | 2}
+	switch n := len(data); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	p.rw.Write(append(frame, data...))
+	p.rw.Flush()
+}
+
+// newASRStreamHandler 启动充当豆包语音流式识别接口的 WebSocket 服务，serve 返回后连接被关闭。
+func newASRStreamHandler(t *testing.T, serve func(r *http.Request, p *wsPeer)) *VolcengineHandler {
+	return newSpeechTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != volcengineASRStreamPath || r.Header.Get("Upgrade") != "websocket" {
+			t.Errorf("request = %s %v", r.URL.Path, r.Header)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+		rw.Flush()
+		serve(r, &wsPeer{t: t, rw: rw})
+	})
+}
+
+func TestStreamAudioTranscription(t *testing.T) {
+	audio := make([]byte, 2*volcengineASRStreamChunkBytes+100)
+	for i := range audio {
+		audio[i] = byte(i)
+	}
+	var received []byte
+	var flags []byte
+	h := newASRStreamHandler(t, func(r *http.Request, p *wsPeer) {
+		for header, want := range map[string]string{"X-Api-App-Key": "app-1", "X-Api-Access-Key": "token-1", "X-Api-Resource-Id": DefaultStreamingASRResourceID} {
+			if got := r.Header.Get(header); got != want {
+				t.Errorf("%s = %q, want %q", header, got, want)
+			}
+		}
+		msgType, _, serialization, payload := p.readASR()
+		var req volcengineASRRequest
+		if msgType != asrMsgFullClientRequest || serialization != asrSerializationJSON || json.Unmarshal(payload, &req) != nil {
+			t.Fatalf("first frame: type %d, serialization %d, %s", msgType, serialization, payload)
+		}
+		wantAudio := volcengineASRAudio{Format: "pcm", Codec: "raw", Rate: 16000, Bits: 16, Channel: 1}
+		if !reflect.DeepEqual(req.Audio, wantAudio) || req.Request["enable_punc"] != true {
+			t.Errorf("request = %+v", req)
+		}
+
+		for {
+			msgType, flag, _, chunk := p.readASR()
+			if msgType != asrMsgAudioOnlyRequest {
+				t.Fatalf("audio frame type %d", msgType)
+			}
+			received = append(received, chunk...)
+			flags = append(flags, flag)
+			if len(flags) == 1 {
+				p.write(asrServerFrame(asrFlagSequence, 1, `{"result":{"text":"你","utterances":[{"text":"你","definite":false}]}}`))
+			}
+			if flag&asrFlagLast != 0 {
+				break
+			}
+		}
+		p.write(asrServerFrame(asrFlagSequence|asrFlagLast, -2, `{"audio_info":{"duration":900},"result":{"text":"你好","utterances":[{"text":"你好","end_time":900,"definite":true}]}}`))
+	})
+
+	var chunks []models.AudioTranscriptionStreamChunk
+	resp, err := h.AudioTranscription(context.Background(), &models.AudioTranscriptionRequest{
+		AudioStream: bytes.NewReader(audio),
+		OnStreamChunk: func(chunk *models.AudioTranscriptionStreamChunk) error {
+			chunks = append(chunks, *chunk)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("AudioTranscription: %v", err)
+	}
+	if !bytes.Equal(received, audio) {
+		t.Errorf("server received %d audio bytes, want %d", len(received), len(audio))
+	}
+	if !reflect.DeepEqual(flags, []byte{0, 0, asrFlagLast}) {
+		t.Errorf("audio frame flags = %v, want the last frame marked", flags)
+	}
+	if len(chunks) != 2 || chunks[0].Text != "你" || chunks[0].IsFinal || chunks[1].Text != "你好" || !chunks[1].IsFinal {
+		t.Errorf("chunks = %+v", chunks)
+	}
+	if resp.Text != "你好" || resp.Duration != 900*time.Millisecond || len(resp.Segments) != 1 || !resp.Segments[0].Definite || resp.ID == "" {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+// blockingReader 每次 Read 都等待 release，用于模拟麦克风等会阻塞的音频输入。
+type blockingReader struct {
+	calls   chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	r.calls <- struct{}{}
+	<-r.release
+	return len(p), nil
+}
+
+func TestStreamAudioTranscriptionErrorStopsSender(t *testing.T) {
+	h := newASRStreamHandler(t, func(r *http.Request, p *wsPeer) {
+		p.readASR()
+		p.write(asrErrorFrame(55000031, "server busy"))
+	})
+
+	audio := &blockingReader{calls: make(chan struct{}, 10), release: make(chan struct{})}
+	_, err := h.AudioTranscription(context.Background(), &models.AudioTranscriptionRequest{AudioStream: audio})
+	if !errors.IsSDKError(err, errors.ErrCodePlatformError) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodePlatformError)
+	}
+
+	// 方法返回时发送方阻塞在第一次 Read 中；Read 返回后它应当退出，而不是继续读取音频。
+	<-audio.calls
+	close(audio.release)
+	select {
+	case <-audio.calls:
+		t.Error("AudioStream was read again after the transcription ended")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStreamAudioTranscriptionCancel(t *testing.T) {
+	h := newASRStreamHandler(t, func(r *http.Request, p *wsPeer) {
+		p.readASR()
+		for {
+			if _, _, err := p.read(); err != nil {
+				return
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	audio := &blockingReader{calls: make(chan struct{}, 10), release: make(chan struct{})}
+	go func() {
+		<-audio.calls
+		cancel()
+	}()
+	_, err := h.AudioTranscription(ctx, &models.AudioTranscriptionRequest{AudioStream: audio})
+	if !errors.IsSDKError(err, errors.ErrCodeCancelled) {
+		t.Errorf("err = %v, want %s", err, errors.ErrCodeCancelled)
+	}
+	close(audio.release)
+}
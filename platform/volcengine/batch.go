@@ -0,0 +1,293 @@
+package volcengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/modelbridge/batch"
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// volcengineTosLocation 是 TOS 中的位置。
+type volcengineTosLocation struct {
+	BucketName string `json:"BucketName"`
+	ObjectKey  string `json:"ObjectKey"`
+}
+
+// volcengineModelReference 指定批量任务使用的推理接入点或基础模型。
+type volcengineModelReference struct {
+	EndpointID      string                     `json:"EndpointId,omitempty"`
+	FoundationModel *volcengineFoundationModel `json:"FoundationModel,omitempty"`
+}
+
+type volcengineFoundationModel struct {
+	Name         string `json:"Name"`
+	ModelVersion string `json:"ModelVersion,omitempty"`
+}
+
+// volcengineBatchJob 是批量推理任务的描述。
+type volcengineBatchJob struct {
+	ID             string                   `json:"Id"`
+	Name           string                   `json:"Name"`
+	ModelReference volcengineModelReference `json:"ModelReference"`
+	Status         struct {
+		Phase   string `json:"Phase"`
+		Message string `json:"Message"`
+	} `json:"Status"`
+	RequestCounts struct {
+		Total     int `json:"Total"`
+		Completed int `json:"Completed"`
+		Failed    int `json:"Failed"`
+	} `json:"RequestCounts"`
+	InputFileTosLocation volcengineTosLocation `json:"InputFileTosLocation"`
+	OutputDirTosLocation volcengineTosLocation `json:"OutputDirTosLocation"`
+	CreateTime           string                `json:"CreateTime"`
+	UpdateTime           string                `json:"UpdateTime"`
+}
+
+type volcengineCreateBatchJobRequest struct {
+	Name                 string                   `json:"Name"`
+	ModelReference       volcengineModelReference `json:"ModelReference"`
+	InputFileTosLocation volcengineTosLocation    `json:"InputFileTosLocation"`
+	OutputDirTosLocation volcengineTosLocation    `json:"OutputDirTosLocation"`
+	CompletionWindow     string                   `json:"CompletionWindow,omitempty"`
+}
+
+// CreateBatchJob 将请求写为 JSONL 上传到 TOS，并创建火山方舟批量推理任务，实现 platform.BatchHandler 接口。
+// 仅支持文本生成，且需要配置 AK/SK 与 WithBatchStorage，否则返回 ErrUnsupportedOperation 错误，由客户端在本地执行。
+func (h *VolcengineHandler) CreateBatchJob(ctx context.Context, req *models.BatchJobRequest) (*models.BatchJob, error) {
+	if req == nil {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: batch job request cannot be nil")
+	}
+	if req.Operation != models.BatchOperationTextGeneration {
+		return nil, errors.New(errors.ErrCodeUnsupported, fmt.Sprintf("volcengine handler: batch inference does not support operation %q", req.Operation))
+	}
+	if !h.hasOpenAPICredentials() || h.batchStorage == nil {
+		return nil, errors.New(errors.ErrCodeUnsupported, "volcengine handler: batch inference requires accessKeyId/secretAccessKey credentials and WithBatchStorage")
+	}
+	if req.Model == "" {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: batch job requires a model")
+	}
+	items, err := batch.NormalizeItems(req.Operation, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// 批量推理任务只能使用一个模型，请求体中的参数按文本生成接口的规则转换
+	lines := make([]batch.InputLine, 0, len(items))
+	for _, item := range items {
+		item = batch.WithDefaultModel(item, req.Model)
+		if item.TextGeneration.Model != req.Model {
+			return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: batch request %q uses model %q, but a batch job can only use %q", item.CustomID, item.TextGeneration.Model, req.Model))
+		}
+		if item.TextGeneration.Stream || item.TextGeneration.ContextCacheID != "" {
+			return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: batch request %q cannot use streaming or context cache", item.CustomID))
+		}
+		body, err := buildChatRequest(item.TextGeneration)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, batch.InputLine{CustomID: item.CustomID, Body: body})
+	}
+
+	var input bytes.Buffer
+	if err := batch.WriteInput(&input, lines); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("modelbridge-%d", time.Now().UnixNano())
+	inputLoc := batch.Location{Bucket: h.batchLocation.Bucket, Key: path.Join(h.batchLocation.Key, name, "input.jsonl")}
+	outputLoc := batch.Location{Bucket: h.batchLocation.Bucket, Key: path.Join(h.batchLocation.Key, name, "output") + "/"}
+	if err := h.batchStorage.Put(ctx, inputLoc, &input); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodePlatformError, "volcengine handler: failed to upload batch input")
+	}
+
+	createReq := &volcengineCreateBatchJobRequest{
+		Name:                 name,
+		ModelReference:       modelReference(req.Model, req.PlatformSpecificParams),
+		InputFileTosLocation: volcengineTosLocation{BucketName: inputLoc.Bucket, ObjectKey: inputLoc.Key},
+		OutputDirTosLocation: volcengineTosLocation{BucketName: outputLoc.Bucket, ObjectKey: outputLoc.Key},
+	}
+	if req.CompletionWindow > 0 {
+		createReq.CompletionWindow = fmt.Sprintf("%dd", int(math.Ceil(req.CompletionWindow.Hours()/24)))
+	}
+
+	var created struct {
+		ID string `json:"Id"`
+	}
+	if err := h.callOpenAPI(ctx, "CreateBatchInferenceJob", createReq, &created); err != nil {
+		return nil, err
+	}
+	if created.ID == "" {
+		return nil, errors.New(errors.ErrCodeInvalidResponse, "volcengine handler: no batch job id found in response")
+	}
+	return &models.BatchJob{
+		ID:            created.ID,
+		Model:         req.Model,
+		Operation:     req.Operation,
+		Status:        models.BatchJobQueued,
+		RequestCounts: models.BatchRequestCount{Total: len(items)},
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// GetBatchJob 查询批量推理任务的状态。
+func (h *VolcengineHandler) GetBatchJob(ctx context.Context, id string) (*models.BatchJob, error) {
+	job, err := h.findBatchJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return job.toBatchJob(), nil
+}
+
+// CancelBatchJob 取消批量推理任务。
+func (h *VolcengineHandler) CancelBatchJob(ctx context.Context, id string) error {
+	return h.callOpenAPI(ctx, "CancelBatchInferenceJob", map[string]string{"Id": id}, nil)
+}
+
+// BatchResults 从 TOS 下载已结束任务的输出文件，并将每行转换为 models.BatchResult。
+// 结果按输出文件中的顺序返回，需要通过 CustomID 与请求对应。
+func (h *VolcengineHandler) BatchResults(ctx context.Context, id string) ([]models.BatchResult, error) {
+	if h.batchStorage == nil {
+		return nil, errors.New(errors.ErrCodeConfiguration, "volcengine handler: reading batch results requires WithBatchStorage")
+	}
+	job, err := h.findBatchJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status := job.status(); !status.Terminal() {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: batch job %s has not finished (status %s)", id, status))
+	}
+
+	objects, err := h.batchStorage.List(ctx, batch.Location{Bucket: job.OutputDirTosLocation.BucketName, Key: job.OutputDirTosLocation.ObjectKey})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodePlatformError, "volcengine handler: failed to list batch output")
+	}
+	var results []models.BatchResult
+	for _, obj := range objects {
+		if !strings.HasSuffix(obj.Key, ".jsonl") {
+			continue
+		}
+		if err := h.readBatchOutput(ctx, obj, &results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// readBatchOutput 读取一个输出文件并将结果追加到 results。
+func (h *VolcengineHandler) readBatchOutput(ctx context.Context, loc batch.Location, results *[]models.BatchResult) error {
+	rc, err := h.batchStorage.Get(ctx, loc)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodePlatformError, fmt.Sprintf("volcengine handler: failed to download batch output %s", loc.Key))
+	}
+	defer rc.Close()
+
+	return batch.ReadOutput(rc, func(line *batch.OutputLine) error {
+		result := models.BatchResult{CustomID: line.CustomID}
+		switch {
+		case line.Error != nil:
+			result.Error = &models.BatchError{Code: line.Error.Code, Message: line.Error.Message}
+		case line.Response == nil:
+			result.Error = &models.BatchError{Code: errors.ErrCodeInvalidResponse, Message: "batch output line has neither response nor error"}
+		default:
+			var volcResp volcengineChatResponse
+			if err := json.Unmarshal(line.Response.Body, &volcResp); err != nil {
+				result.Error = &models.BatchError{Code: errors.ErrCodeInvalidResponse, Message: fmt.Sprintf("failed to unmarshal response body: %v", err)}
+				break
+			}
+			resp, err := toTextGenerationResponse(&volcResp)
+			if err != nil {
+				// 响应体中的平台错误保留 ErrCodePlatformError，其他格式问题为 ErrCodeInvalidResponse。
+				code := errors.ErrCodeInvalidResponse
+				var sdkErr *errors.Error
+				if errors.As(err, &sdkErr) {
+					code = sdkErr.Code
+				}
+				result.Error = &models.BatchError{Code: code, Message: err.Error()}
+				break
+			}
+			result.TextGeneration = resp
+		}
+		*results = append(*results, result)
+		return nil
+	})
+}
+
+// findBatchJob 按 ID 查询批量推理任务。
+func (h *VolcengineHandler) findBatchJob(ctx context.Context, id string) (*volcengineBatchJob, error) {
+	var listed struct {
+		Items []volcengineBatchJob `json:"Items"`
+	}
+	filter := map[string]interface{}{"Filter": map[string][]string{"Ids": {id}}}
+	if err := h.callOpenAPI(ctx, "ListBatchInferenceJobs", filter, &listed); err != nil {
+		return nil, err
+	}
+	for i := range listed.Items {
+		if listed.Items[i].ID == id {
+			return &listed.Items[i], nil
+		}
+	}
+	return nil, errors.New(errors.ErrCodeNotFound, fmt.Sprintf("volcengine handler: batch job %s not found", id))
+}
+
+// modelReference 根据模型 ID 构造 ModelReference：推理接入点 (ep- 前缀) 使用 EndpointId，否则视为基础模型名称。
+func modelReference(model string, params map[string]interface{}) volcengineModelReference {
+	if strings.HasPrefix(model, "ep-") {
+		return volcengineModelReference{EndpointID: model}
+	}
+	fm := &volcengineFoundationModel{Name: model}
+	if version, ok := params["volc_model_version"].(string); ok {
+		fm.ModelVersion = version
+	}
+	return volcengineModelReference{FoundationModel: fm}
+}
+
+// status 将任务阶段映射为 models.BatchJobStatus。
+func (j *volcengineBatchJob) status() models.BatchJobStatus {
+	switch strings.ToLower(j.Status.Phase) {
+	case "queued", "pending":
+		return models.BatchJobQueued
+	case "completed", "succeeded":
+		return models.BatchJobCompleted
+	case "failed":
+		return models.BatchJobFailed
+	case "terminating", "cancelling":
+		return models.BatchJobCancelling
+	case "terminated", "cancelled":
+		return models.BatchJobCancelled
+	case "expired":
+		return models.BatchJobExpired
+	default:
+		return models.BatchJobRunning
+	}
+}
+
+func (j *volcengineBatchJob) toBatchJob() *models.BatchJob {
+	job := &models.BatchJob{
+		ID:        j.ID,
+		Model:     j.ModelReference.EndpointID,
+		Operation: models.BatchOperationTextGeneration,
+		Status:    j.status(),
+		Message:   j.Status.Message,
+		RequestCounts: models.BatchRequestCount{
+			Total:     j.RequestCounts.Total,
+			Completed: j.RequestCounts.Completed,
+			Failed:    j.RequestCounts.Failed,
+		},
+	}
+	if job.Model == "" && j.ModelReference.FoundationModel != nil {
+		job.Model = j.ModelReference.FoundationModel.Name
+	}
+	job.CreatedAt, _ = time.Parse(time.RFC3339, j.CreateTime)
+	if job.Status.Terminal() {
+		job.CompletedAt, _ = time.Parse(time.RFC3339, j.UpdateTime)
+	}
+	return job
+}
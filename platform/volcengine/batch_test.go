@@ -0,0 +1,63 @@
+package volcengine
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/batch"
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+func TestReadBatchOutputErrors(t *testing.T) {
+	output := `{"custom_id":"ok","response":{"status_code":200,"body":{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}}}
+{"custom_id":"failed","error":{"code":"RateLimitExceeded","message":"slow down"}}
+{"custom_id":"empty"}
+{"custom_id":"no-choices","response":{"status_code":200,"body":{"id":"c2","choices":[]}}}
+{"custom_id":"body-error","response":{"status_code":200,"body":{"error":{"code":"InternalServiceError","message":"oops"}}}}
+{"custom_id":"bad-body","response":{"status_code":200,"body":"not an object"}}
+`
+	storage := newMemoryStorage()
+	loc := batch.Location{Bucket: "b", Key: "out/results.jsonl"}
+	if err := storage.Put(context.Background(), loc, bytes.NewBufferString(output)); err != nil {
+		t.Fatal(err)
+	}
+	h := &VolcengineHandler{batchStorage: storage}
+
+	var results []models.BatchResult
+	if err := h.readBatchOutput(context.Background(), loc, &results); err != nil {
+		t.Fatalf("readBatchOutput: %v", err)
+	}
+	want := map[string]string{
+		"ok":         "",
+		"failed":     "RateLimitExceeded",
+		"empty":      errors.ErrCodeInvalidResponse,
+		"no-choices": errors.ErrCodeInvalidResponse,
+		"body-error": errors.ErrCodePlatformError,
+		"bad-body":   errors.ErrCodeInvalidResponse,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for _, r := range results {
+		code := ""
+		if r.Error != nil {
+			code = r.Error.Code
+		}
+		if code != want[r.CustomID] {
+			t.Errorf("%s: error code = %q, want %q", r.CustomID, code, want[r.CustomID])
+		}
+	}
+	if results[0].TextGeneration == nil || results[0].TextGeneration.GeneratedText != "hi" {
+		t.Errorf("ok result = %+v", results[0].TextGeneration)
+	}
+
+	// 无法解析的输出行是格式错误，而不是可重试的平台错误。
+	if err := storage.Put(context.Background(), loc, bytes.NewBufferString("{not json\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.readBatchOutput(context.Background(), loc, &results); !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+		t.Errorf("malformed line: err = %v, want %s", err, errors.ErrCodeInvalidResponse)
+	}
+}
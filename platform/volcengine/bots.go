@@ -0,0 +1,82 @@
+package volcengine
+
+import (
+	"strings"
+
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// volcengineBotIDPrefix 是火山方舟应用 (Bot) ID 的前缀，以此区分应用与推理接入点。
+const volcengineBotIDPrefix = "bot-"
+
+// volcengineReference 是应用插件返回的引用来源。
+type volcengineReference struct {
+	Title       string `json:"title,omitempty"`
+	URL         string `json:"url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+	PublishTime string `json:"publish_time,omitempty"`
+	DocName     string `json:"doc_name,omitempty"`
+	ChunkID     string `json:"chunk_id,omitempty"`
+}
+
+// volcengineBotUsage 是应用调用中各模型与插件的用量明细。
+type volcengineBotUsage struct {
+	ModelUsage []struct {
+		Name string `json:"name"`
+		volcengineTokenUsage
+	} `json:"model_usage,omitempty"`
+	ActionUsage []struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	} `json:"action_usage,omitempty"`
+}
+
+// isBotID 判断模型 ID 是否为应用 ID，应用需要通过 bots/chat/completions 接口调用。
+func isBotID(model string) bool {
+	return strings.HasPrefix(model, volcengineBotIDPrefix)
+}
+
+// botsChatURL 返回应用对话接口的完整地址。
+func (h *VolcengineHandler) botsChatURL() string {
+	return h.baseURL + volcengineBotsChatPath
+}
+
+// toReferences 将平台的引用来源转换为通用结构，没有引用时返回 nil。
+func toReferences(refs []volcengineReference) []models.Reference {
+	if len(refs) == 0 {
+		return nil
+	}
+	out := make([]models.Reference, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, models.Reference(ref))
+	}
+	return out
+}
+
+// toBotUsage 将应用用量明细转换为通用结构。
+func (u *volcengineBotUsage) toBotUsage() *models.BotUsage {
+	if u == nil {
+		return nil
+	}
+	usage := &models.BotUsage{}
+	for _, m := range u.ModelUsage {
+		usage.ModelUsage = append(usage.ModelUsage, models.ModelUsage{Name: m.Name, Usage: m.toUsage()})
+	}
+	for _, a := range u.ActionUsage {
+		usage.ActionUsage = append(usage.ActionUsage, models.ActionUsage{Name: a.Name, Count: a.Count})
+	}
+	return usage
+}
+
+// totalModelUsage 汇总应用中所有模型的 Token 用量，用于应用响应未返回 usage 字段的情况。
+func (u *volcengineBotUsage) totalModelUsage() models.Usage {
+	var total models.Usage
+	if u == nil {
+		return total
+	}
+	for _, m := range u.ModelUsage {
+		total.Add(m.toUsage())
+	}
+	return total
+}
@@ -0,0 +1,90 @@
+package volcengine
+
+import (
+	"fmt"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// 视频理解模型支持的抽帧频率范围。
+const (
+	volcengineMinVideoFPS = 0.2
+	volcengineMaxVideoFPS = 5.0
+)
+
+// volcengineRequestMessage 是请求中的消息，Content 为字符串或 []volcengineContentPart。
+type volcengineRequestMessage struct {
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"`
+}
+
+// volcengineContentPart 是多模态消息内容的一个部分，结构与 OpenAI 兼容。
+type volcengineContentPart struct {
+	Type     string              `json:"type"` // text, image_url, video_url
+	Text     string              `json:"text,omitempty"`
+	ImageURL *volcengineMediaURL `json:"image_url,omitempty"`
+	VideoURL *volcengineMediaURL `json:"video_url,omitempty"`
+}
+
+type volcengineMediaURL struct {
+	URL string  `json:"url"`
+	FPS float64 `json:"fps,omitempty"` // 仅视频，每秒抽取的帧数
+}
+
+// toRequestMessage 将通用的消息内容转换为请求消息。没有多模态内容时 Content 为纯文本，
+// 否则为按顺序排列的各部分，text 非空时作为最后一个文本部分。
+func toRequestMessage(role, text string, parts []models.ContentPart) (volcengineRequestMessage, error) {
+	msg := volcengineRequestMessage{Role: role, Content: text}
+	if len(parts) == 0 {
+		return msg, nil
+	}
+	content := make([]volcengineContentPart, 0, len(parts)+1)
+	for i, part := range parts {
+		converted, err := toContentParts(part)
+		if err != nil {
+			return msg, errors.Wrap(err, errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: invalid content part %d", i))
+		}
+		content = append(content, converted...)
+	}
+	if text != "" {
+		content = append(content, volcengineContentPart{Type: "text", Text: text})
+	}
+	msg.Content = content
+	return msg, nil
+}
+
+// toContentParts 转换单个 ContentPart，返回的错误由调用方包装为 SDK 错误。视频帧序列会展开为按顺序排列的多张图片，
+// 火山方舟的图片输入不携带时间信息，因此帧序列的 FPS 不会传递给模型。
+func toContentParts(part models.ContentPart) ([]volcengineContentPart, error) {
+	switch part.Type {
+	case models.ContentPartText:
+		return []volcengineContentPart{{Type: "text", Text: part.Text}}, nil
+	case models.ContentPartImage:
+		if part.ImageURL == "" {
+			return nil, fmt.Errorf("image part requires an image URL")
+		}
+		return []volcengineContentPart{{Type: "image_url", ImageURL: &volcengineMediaURL{URL: part.ImageURL}}}, nil
+	case models.ContentPartVideo:
+		v := part.Video
+		if v == nil || (v.URL == "") == (len(v.Frames) == 0) {
+			return nil, fmt.Errorf("video part requires exactly one of URL or frames")
+		}
+		if v.URL != "" {
+			if v.FPS != 0 && (v.FPS < volcengineMinVideoFPS || v.FPS > volcengineMaxVideoFPS) {
+				return nil, fmt.Errorf("video fps must be in [%g, %g], got %g", volcengineMinVideoFPS, volcengineMaxVideoFPS, v.FPS)
+			}
+			return []volcengineContentPart{{Type: "video_url", VideoURL: &volcengineMediaURL{URL: v.URL, FPS: v.FPS}}}, nil
+		}
+		frames := make([]volcengineContentPart, len(v.Frames))
+		for i, frame := range v.Frames {
+			if frame == "" {
+				return nil, fmt.Errorf("video frame %d is empty", i)
+			}
+			frames[i] = volcengineContentPart{Type: "image_url", ImageURL: &volcengineMediaURL{URL: frame}}
+		}
+		return frames, nil
+	default:
+		return nil, fmt.Errorf("unsupported content part type %q", part.Type)
+	}
+}
@@ -0,0 +1,108 @@
+package volcengine
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+func TestToRequestMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		parts []models.ContentPart
+		want  interface{}
+	}{
+		{name: "text only", text: "hi", want: "hi"},
+		{
+			name:  "image then prompt",
+			text:  "describe",
+			parts: []models.ContentPart{models.ImagePart("https://example.com/a.png")},
+			want: []volcengineContentPart{
+				{Type: "image_url", ImageURL: &volcengineMediaURL{URL: "https://example.com/a.png"}},
+				{Type: "text", Text: "describe"},
+			},
+		},
+		{
+			name:  "parts without prompt",
+			parts: []models.ContentPart{models.TextPart("compare"), models.ImagePart("data:image/png;base64,AAAA")},
+			want: []volcengineContentPart{
+				{Type: "text", Text: "compare"},
+				{Type: "image_url", ImageURL: &volcengineMediaURL{URL: "data:image/png;base64,AAAA"}},
+			},
+		},
+		{
+			name:  "video URL with fps",
+			parts: []models.ContentPart{models.VideoURLPart("https://example.com/v.mp4", 2)},
+			want:  []volcengineContentPart{{Type: "video_url", VideoURL: &volcengineMediaURL{URL: "https://example.com/v.mp4", FPS: 2}}},
+		},
+		{
+			name:  "video URL with default fps",
+			parts: []models.ContentPart{models.VideoURLPart("https://example.com/v.mp4", 0)},
+			want:  []volcengineContentPart{{Type: "video_url", VideoURL: &volcengineMediaURL{URL: "https://example.com/v.mp4"}}},
+		},
+		{
+			// 帧序列展开为多张图片，FPS 不会传递给模型。
+			name:  "video frames",
+			parts: []models.ContentPart{models.VideoFramesPart([]string{"f1.jpg", "f2.jpg"}, 1)},
+			want: []volcengineContentPart{
+				{Type: "image_url", ImageURL: &volcengineMediaURL{URL: "f1.jpg"}},
+				{Type: "image_url", ImageURL: &volcengineMediaURL{URL: "f2.jpg"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toRequestMessage("user", tt.text, tt.parts)
+			if err != nil {
+				t.Fatalf("toRequestMessage: %v", err)
+			}
+			if got.Role != "user" || !reflect.DeepEqual(got.Content, tt.want) {
+				t.Errorf("message = %+v, want content %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToRequestMessageErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		parts   []models.ContentPart
+		wantMsg string
+	}{
+		{name: "image without URL", parts: []models.ContentPart{models.ImagePart("")}, wantMsg: "requires an image URL"},
+		{name: "video without input", parts: []models.ContentPart{{Type: models.ContentPartVideo}}, wantMsg: "exactly one of URL or frames"},
+		{name: "video with URL and frames", parts: []models.ContentPart{{Type: models.ContentPartVideo, Video: &models.VideoInput{URL: "v.mp4", Frames: []string{"f.jpg"}}}}, wantMsg: "exactly one of URL or frames"},
+		{name: "empty video", parts: []models.ContentPart{models.VideoFramesPart(nil, 0)}, wantMsg: "exactly one of URL or frames"},
+		{name: "fps too low", parts: []models.ContentPart{models.VideoURLPart("v.mp4", 0.1)}, wantMsg: "fps must be in [0.2, 5]"},
+		{name: "fps too high", parts: []models.ContentPart{models.VideoURLPart("v.mp4", 10)}, wantMsg: "fps must be in [0.2, 5]"},
+		{name: "empty frame", parts: []models.ContentPart{models.VideoFramesPart([]string{"f1.jpg", ""}, 1)}, wantMsg: "video frame 1 is empty"},
+		{name: "unsupported type", parts: []models.ContentPart{{Type: "audio"}}, wantMsg: `unsupported content part type "audio"`},
+		{name: "index of the bad part", parts: []models.ContentPart{models.TextPart("ok"), models.ImagePart("")}, wantMsg: "invalid content part 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := toRequestMessage("user", "hi", tt.parts)
+			if !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("err = %v, want %s containing %q", err, errors.ErrCodeInvalidRequest, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestTextGenerationInvalidContentPart(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a request with an invalid content part must not be sent")
+	})
+	_, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{
+		Model: "m", Prompt: "hi", Parts: []models.ContentPart{models.VideoURLPart("v.mp4", 30)},
+	})
+	if !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("err = %v, want %s", err, errors.ErrCodeInvalidRequest)
+	}
+}
@@ -2,13 +2,144 @@ package volcengine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 
+	"github.com/hewenyu/modelbridge/errors"
 	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/utils"
+	"github.com/hewenyu/modelbridge/vector"
 )
 
-// Embedding 占位符实现
+const (
+	// volcengineEmbeddingMaxInputs 是火山方舟向量嵌入接口单次请求的最大输入条数。
+	volcengineEmbeddingMaxInputs = 256
+	// volcengineEmbeddingMaxInputTokens 是文本向量模型单条输入的最大 Token 数量。
+	volcengineEmbeddingMaxInputTokens = 4096
+)
+
+// volcengineEmbeddingRequest 是火山方舟向量嵌入 API 的请求体结构。
+type volcengineEmbeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	EncodingFormat string   `json:"encoding_format,omitempty"` // float 或 base64
+	Dimensions     int      `json:"dimensions,omitempty"`      // 输出向量的维度，仅部分模型支持
+}
+
+// volcengineEmbeddingResponse 是火山方舟向量嵌入 API 的响应体结构。
+type volcengineEmbeddingResponse struct {
+	ID    string                    `json:"id"`
+	Model string                    `json:"model"`
+	Data  []volcengineEmbeddingData `json:"data"`
+	Usage volcengineTokenUsage      `json:"usage"`
+}
+
+// volcengineEmbeddingData 是单条输入的向量，encoding_format 为 base64 时 Embedding 是字符串。
+type volcengineEmbeddingData struct {
+	Index     int             `json:"index"`
+	Embedding json.RawMessage `json:"embedding"`
+}
+
+// EmbeddingLimits 返回火山方舟向量嵌入接口的输入限制，实现 platform.EmbeddingLimiter 接口。
+func (h *VolcengineHandler) EmbeddingLimits(model string) models.EmbeddingLimits {
+	return models.EmbeddingLimits{MaxInputs: volcengineEmbeddingMaxInputs, MaxInputTokens: volcengineEmbeddingMaxInputTokens}
+}
+
+// Embedding 调用火山方舟的向量嵌入接口。EncodingFormat 为 base64 时平台返回的向量会被解码为 []float32，
+// PlatformSpecificParams 中的 "dimensions" 用于指定输出向量的维度。返回的 Embeddings 按 Index 排列。
 func (h *VolcengineHandler) Embedding(ctx context.Context, req *models.EmbeddingRequest) (*models.EmbeddingResponse, error) {
-	// h.logger.Println("Volcengine Embedding not yet implemented.")
-	return nil, fmt.Errorf("volcengine handler: Embedding not yet implemented") // errors.New(errors.ErrCodeUnsupported, "Embedding not yet implemented for Volcengine")
+	if req == nil {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: embedding request cannot be nil")
+	}
+	if req.Model == "" || len(req.Input) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: embedding request requires a model and at least one input")
+	}
+	if len(req.Input) > volcengineEmbeddingMaxInputs {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: embedding request has %d inputs, at most %d are allowed", len(req.Input), volcengineEmbeddingMaxInputs))
+	}
+	switch req.EncodingFormat {
+	case "", "float", "base64":
+	default:
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: unsupported embedding encoding format %q", req.EncodingFormat))
+	}
+
+	body := volcengineEmbeddingRequest{
+		Model:          req.Model,
+		Input:          req.Input,
+		EncodingFormat: req.EncodingFormat,
+	}
+	if dims, ok := req.PlatformSpecificParams["dimensions"]; ok {
+		n, ok := toInt(dims)
+		if !ok || n <= 0 {
+			return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: dimensions must be a positive integer, got %v", dims))
+		}
+		body.Dimensions = n
+	}
+
+	var apiResp volcengineEmbeddingResponse
+	if _, err := h.api.DoJSON(ctx, &utils.Request{
+		Method: http.MethodPost,
+		URL:    h.baseURL + volcengineEmbeddingsPath,
+		Header: h.requestHeaders(),
+		Body:   body,
+	}, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if len(apiResp.Data) != len(req.Input) {
+		return nil, errors.New(errors.ErrCodeInvalidResponse, fmt.Sprintf("volcengine handler: expected %d embeddings, got %d", len(req.Input), len(apiResp.Data)))
+	}
+	embeddings := make([]models.Embedding, len(apiResp.Data))
+	seen := make([]bool, len(apiResp.Data))
+	for _, d := range apiResp.Data {
+		if d.Index < 0 || d.Index >= len(embeddings) || seen[d.Index] {
+			return nil, errors.New(errors.ErrCodeInvalidResponse, fmt.Sprintf("volcengine handler: unexpected embedding index %d", d.Index))
+		}
+		values, err := decodeEmbedding(d.Embedding)
+		if err != nil {
+			return nil, err
+		}
+		seen[d.Index] = true
+		embeddings[d.Index] = models.Embedding{Index: d.Index, Embedding: values}
+	}
+	return &models.EmbeddingResponse{
+		ID:         apiResp.ID,
+		Embeddings: embeddings,
+		TokenUsage: apiResp.Usage.toUsage(),
+	}, nil
+}
+
+// decodeEmbedding 解码 float 数组或 base64 字符串形式的向量。
+func decodeEmbedding(raw json.RawMessage) ([]float32, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInvalidResponse, "volcengine handler: invalid base64 embedding")
+		}
+		return vector.DecodeBase64(encoded)
+	}
+	var values []float32
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInvalidResponse, "volcengine handler: invalid embedding")
+	}
+	return values, nil
+}
+
+// toInt 将 PlatformSpecificParams 中的整数转换为 int，兼容从 JSON 解码得到的 float64。
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), true
+		}
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
 }
@@ -0,0 +1,106 @@
+package volcengine
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// encodeFloats 按平台 base64 格式 (小端 float32) 编码向量。
+func encodeFloats(values ...float32) string {
+	buf := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+func TestEmbedding(t *testing.T) {
+	var got volcengineEmbeddingRequest
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("request = %s %v", r.URL.Path, r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		// 乱序返回，并混用 float 数组与 base64 字符串。
+		fmt.Fprintf(w, `{"id":"e1","model":"m","data":[{"index":1,"embedding":"%s"},{"index":0,"embedding":[0.5,-1]}],"usage":{"prompt_tokens":4,"total_tokens":4}}`,
+			encodeFloats(0.25, 2))
+	})
+
+	resp, err := h.Embedding(context.Background(), &models.EmbeddingRequest{
+		Model:                  "m",
+		Input:                  []string{"a", "b"},
+		EncodingFormat:         "base64",
+		PlatformSpecificParams: map[string]interface{}{"dimensions": float64(2)},
+	})
+	if err != nil {
+		t.Fatalf("Embedding: %v", err)
+	}
+	want := volcengineEmbeddingRequest{Model: "m", Input: []string{"a", "b"}, EncodingFormat: "base64", Dimensions: 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %+v, want %+v", got, want)
+	}
+	wantEmbeddings := []models.Embedding{
+		{Index: 0, Embedding: []float32{0.5, -1}},
+		{Index: 1, Embedding: []float32{0.25, 2}},
+	}
+	if resp.ID != "e1" || !reflect.DeepEqual(resp.Embeddings, wantEmbeddings) {
+		t.Errorf("resp = %+v", resp)
+	}
+	if resp.TokenUsage.PromptTokens != 4 || resp.TokenUsage.TotalTokens != 4 {
+		t.Errorf("usage = %+v", resp.TokenUsage)
+	}
+}
+
+func TestEmbeddingInvalidResponse(t *testing.T) {
+	tests := map[string]string{
+		"count mismatch":     `{"data":[{"index":0,"embedding":[1]}]}`,
+		"duplicate index":    `{"data":[{"index":0,"embedding":[1]},{"index":0,"embedding":[2]}]}`,
+		"index out of range": `{"data":[{"index":0,"embedding":[1]},{"index":2,"embedding":[2]}]}`,
+		"bad embedding":      `{"data":[{"index":0,"embedding":[1]},{"index":1,"embedding":{}}]}`,
+		"bad base64":         `{"data":[{"index":0,"embedding":[1]},{"index":1,"embedding":"!!"}]}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, body)
+			})
+			_, err := h.Embedding(context.Background(), &models.EmbeddingRequest{Model: "m", Input: []string{"a", "b"}})
+			if !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+				t.Errorf("err = %v, want %s", err, errors.ErrCodeInvalidResponse)
+			}
+		})
+	}
+}
+
+func TestEmbeddingInvalidRequest(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("invalid requests must not reach the platform")
+	})
+	tests := map[string]*models.EmbeddingRequest{
+		"nil":             nil,
+		"no model":        {Input: []string{"a"}},
+		"no input":        {Model: "m"},
+		"too many inputs": {Model: "m", Input: make([]string, volcengineEmbeddingMaxInputs+1)},
+		"encoding format": {Model: "m", Input: []string{"a"}, EncodingFormat: "int8"},
+		"dimensions":      {Model: "m", Input: []string{"a"}, PlatformSpecificParams: map[string]interface{}{"dimensions": 1.5}},
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := h.Embedding(context.Background(), req); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+				t.Errorf("err = %v, want %s", err, errors.ErrCodeInvalidRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package volcengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/batch"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// assertGolden 将 got 与 testdata/golden/name 比较，-update 时重写 golden 文件。
+// JSON 内容会先格式化，使 golden 文件便于审阅。
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	if strings.HasSuffix(name, ".json") {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, got, "", "  "); err != nil {
+			t.Fatalf("invalid JSON for %s: %v\n%s", name, err, got)
+		}
+		buf.WriteByte('\n')
+		got = buf.Bytes()
+	}
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch (run with -update to accept)\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// capturedRequest 是测试服务收到的一个请求。
+type capturedRequest struct {
+	Path string
+	Body []byte
+}
+
+// chatServer 记录收到的请求，并返回一个固定的对话补全响应。
+type chatServer struct {
+	mu       sync.Mutex
+	requests []capturedRequest
+}
+
+func (s *chatServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, capturedRequest{Path: r.URL.Path, Body: body})
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, `{"id":"chat-1","model":"m","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+}
+
+func (s *chatServer) last(t *testing.T) capturedRequest {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		t.Fatal("no request received")
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+func float32Ptr(v float32) *float32 { return &v }
+
+func TestChatRequestGolden(t *testing.T) {
+	seed := int64(42)
+	tests := []struct {
+		name   string
+		path   string
+		golden string
+		req    *models.TextGenerationRequest
+	}{
+		{
+			name:   "chat completions",
+			path:   "/chat/completions",
+			golden: "chat_completions.json",
+			req: &models.TextGenerationRequest{
+				Model:            "doubao-1.5-pro-32k-250115",
+				Prompt:           "你好",
+				MaxTokens:        256,
+				Temperature:      float32Ptr(0),
+				TopP:             float32Ptr(0.5),
+				FrequencyPenalty: float32Ptr(0.5),
+				Seed:             &seed,
+				LogitBias:        map[string]int{"1234": -100},
+				N:                2,
+				Logprobs:         true,
+				TopLogprobs:      3,
+				StopSequences:    []string{"\n\n"},
+				Thinking:         models.ThinkingDisabled,
+				ResponseFormat:   &models.ResponseFormat{Type: models.ResponseFormatJSONObject},
+			},
+		},
+		{
+			name:   "multimodal",
+			path:   "/chat/completions",
+			golden: "chat_completions_multimodal.json",
+			req: &models.TextGenerationRequest{
+				Model:  "doubao-1.5-vision-pro-32k-250115",
+				Prompt: "描述这张图片",
+				Parts:  []models.ContentPart{models.ImagePart("https://example.com/cat.png")},
+			},
+		},
+		{
+			name:   "bots chat completions",
+			path:   "/bots/chat/completions",
+			golden: "bots_chat_completions.json",
+			req:    &models.TextGenerationRequest{Model: "bot-20250101000000-abcde", Prompt: "今天的新闻"},
+		},
+		{
+			name:   "context chat completions",
+			path:   "/context/chat/completions",
+			golden: "context_chat_completions.json",
+			req:    &models.TextGenerationRequest{Model: "ep-20250101000000-abcde", Prompt: "继续", ContextCacheID: "ctx-123"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &chatServer{}
+			h := newTestHandler(t, server.handle)
+			if _, err := h.TextGeneration(context.Background(), tt.req); err != nil {
+				t.Fatalf("TextGeneration: %v", err)
+			}
+			got := server.last(t)
+			if got.Path != tt.path {
+				t.Errorf("path = %q, want %q", got.Path, tt.path)
+			}
+			assertGolden(t, tt.golden, got.Body)
+		})
+	}
+}
+
+// memoryStorage 是保存在内存中的 batch.Storage。
+type memoryStorage struct {
+	mu      sync.Mutex
+	objects map[batch.Location][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{objects: make(map[batch.Location][]byte)}
+}
+
+func (s *memoryStorage) Put(ctx context.Context, loc batch.Location, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[loc] = data
+	return nil
+}
+
+func (s *memoryStorage) Get(ctx context.Context, loc batch.Location) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[loc]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryStorage) List(ctx context.Context, prefix batch.Location) ([]batch.Location, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var locs []batch.Location
+	for loc := range s.objects {
+		if loc.Bucket == prefix.Bucket && strings.HasPrefix(loc.Key, prefix.Key) {
+			locs = append(locs, loc)
+		}
+	}
+	sort.Slice(locs, func(i, j int) bool { return locs[i].Key < locs[j].Key })
+	return locs, nil
+}
+
+func TestBatchInputGolden(t *testing.T) {
+	var createBody []byte
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Action") != "CreateBatchInferenceJob" {
+			t.Errorf("unexpected action %q", r.URL.Query().Get("Action"))
+		}
+		createBody, _ = io.ReadAll(r.Body)
+		io.WriteString(w, `{"ResponseMetadata":{"RequestId":"r1"},"Result":{"Id":"batch-1"}}`)
+	})
+	storage := newMemoryStorage()
+	cfg := testConfig(platform.ProviderSettings{})
+	cfg.Credentials["accessKeyId"] = "ak"
+	cfg.Credentials["secretAccessKey"] = "sk"
+	h, err := NewHandler(cfg, WithOpenAPIEndpoint(server), WithBatchStorage(storage, "bucket", "jobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := h.CreateBatchJob(context.Background(), &models.BatchJobRequest{
+		Model:     "ep-20250101000000-abcde",
+		Operation: models.BatchOperationTextGeneration,
+		Items: []models.BatchRequestItem{
+			{CustomID: "a", TextGeneration: &models.TextGenerationRequest{Prompt: "第一题", MaxTokens: 64}},
+			{CustomID: "b", TextGeneration: &models.TextGenerationRequest{Prompt: "第二题", Temperature: float32Ptr(0.7)}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBatchJob: %v", err)
+	}
+	if job.ID != "batch-1" {
+		t.Errorf("job ID = %q", job.ID)
+	}
+
+	var create struct {
+		ModelReference       map[string]string
+		InputFileTosLocation struct{ BucketName, ObjectKey string }
+	}
+	if err := json.Unmarshal(createBody, &create); err != nil {
+		t.Fatalf("create request: %v\n%s", err, createBody)
+	}
+	if create.ModelReference["EndpointId"] != "ep-20250101000000-abcde" {
+		t.Errorf("ModelReference = %v", create.ModelReference)
+	}
+	input, err := storage.Get(context.Background(), batch.Location{Bucket: create.InputFileTosLocation.BucketName, Key: create.InputFileTosLocation.ObjectKey})
+	if err != nil {
+		t.Fatalf("input file %+v not uploaded: %v", create.InputFileTosLocation, err)
+	}
+	data, _ := io.ReadAll(input)
+	assertGolden(t, "batch_input.jsonl", data)
+}
@@ -1,28 +1,62 @@
 package volcengine
 
 import (
+	"encoding/json"
 	"fmt"
-	"net/http" // Added for strings.TrimPrefix
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/hewenyu/modelbridge/batch"
+	"github.com/hewenyu/modelbridge/errors"
 	"github.com/hewenyu/modelbridge/platform"
-	// "github.com/hewenyu/modelbridge/errors" // SDK自定义错误，后续会用到
+	"github.com/hewenyu/modelbridge/utils"
 	// "github.com/hewenyu/modelbridge/client" // client.Logger，后续会用到
 )
 
 const (
-	volcengineAPIKeyName         = "apiKey" // 与 GETTING_STARTED.md 中定义的凭证 key 一致
-	volcengineChatCompletionsURL = "https://ark.cn-beijing.volces.com/api/v3/chat/completions"
-	sseDataPrefix                = "data: "
-	sseDataPrefixNoSpace         = "data:"
-	sseDoneMessage               = "[DONE]"
-	DefaultTimeout               = 10 * time.Second
+	volcengineAPIKeyName          = "apiKey"                           // 与 GETTING_STARTED.md 中定义的凭证 key 一致
+	volcengineBaseURLTemplate     = "https://ark.%s.volces.com/api/%s" // 按区域与 API 版本拼接的默认根地址
+	DefaultRegion                 = "cn-beijing"
+	DefaultAPIVersion             = "v3"
+	volcengineChatCompletionsPath = "/chat/completions"
+	volcengineContextCreatePath   = "/context/create"
+	volcengineContextChatPath     = "/context/chat/completions"
+	volcengineBotsChatPath        = "/bots/chat/completions"
+	volcengineEmbeddingsPath      = "/embeddings"
+	DefaultTimeout                = 10 * time.Second
 )
 
 // VolcengineHandler 实现了 PlatformHandler 接口，用于与火山方舟平台交互。
 type VolcengineHandler struct {
 	apiKey     string
-	httpClient *http.Client
+	baseURL    string            // API 根地址，不含具体接口路径
+	headers    map[string]string // 附加到每个请求上的自定义请求头
+	httpClient utils.HTTPClient  // 所有请求都经由此客户端发送
+	api        *utils.APIClient  // 基于 httpClient 的 JSON/SSE 请求封装
+
+	// 以下字段用于调用 OpenAPI (管控面)，例如批量推理任务
+	accessKey     string
+	secretKey     string
+	region        string
+	openAPIURL    string
+	openAPI       *utils.APIClient
+	batchStorage  batch.Storage
+	batchLocation batch.Location // 批量任务输入输出文件的存放位置 (桶与前缀)
+
+	// 以下字段用于调用豆包语音接口，例如语音合成
+	speechAppID     string
+	speechAccessKey string
+	speechURL       string
+	speechAPI       *utils.APIClient
+
+	// 以下字段仅在 NewHandler 中用于构建 httpClient
+	timeout          time.Duration
+	transport        http.RoundTripper
+	transportConfig  utils.TransportConfig
+	customHTTPClient utils.HTTPClient
+	hooks            utils.Hooks
 	// logger     client.Logger // 后续添加
 }
 
@@ -31,29 +65,176 @@ type VolcengineHandler struct {
 // logger 参数用于日志记录。
 func NewHandler(config *platform.PlatformConfig, opts ...Option) (*VolcengineHandler, error) {
 	if config == nil {
-		return nil, fmt.Errorf("volcengine handler: platform config cannot be nil") //  errors.New(errors.ErrCodeConfiguration, "volcengine handler: platform config cannot be nil")
+		return nil, errors.New(errors.ErrCodeConfiguration, "volcengine handler: platform config cannot be nil")
 	}
 
 	apiKey, ok := config.Credentials[volcengineAPIKeyName]
 	if !ok || apiKey == "" {
-		return nil, fmt.Errorf("volcengine handler: API key not found or empty in credentials") // errors.New(errors.ErrCodeConfiguration, "volcengine handler: API key not found or empty in credentials")
+		return nil, errors.New(errors.ErrCodeConfiguration, "volcengine handler: API key not found or empty in credentials")
+	}
+
+	baseURL, err := resolveBaseURL(config.SpecificConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := DefaultTimeout
+	if config.Timeout > 0 {
+		timeout = config.Timeout
+	}
+
+	region := config.SpecificConfig.Region
+	if region == "" {
+		region = DefaultRegion
 	}
 
 	handler := &VolcengineHandler{
 		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: DefaultTimeout}, // 使用默认的 http.Client，后续可以配置超时等
+		baseURL:    baseURL,
+		headers:    make(map[string]string, len(config.SpecificConfig.Headers)),
+		timeout:    timeout,
+		accessKey:  config.Credentials[volcengineAccessKeyName],
+		secretKey:  config.Credentials[volcengineSecretKeyName],
+		region:     region,
+		openAPIURL: fmt.Sprintf(volcengineOpenAPIURLTemplate, region),
+
+		speechAppID:     config.Credentials[volcengineSpeechAppIDName],
+		speechAccessKey: config.Credentials[volcengineSpeechAccessKeyName],
+		speechURL:       DefaultSpeechEndpoint,
 		// logger:     logger,
 	}
 
+	if config.SpecificConfig.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.SpecificConfig.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("volcengine handler: invalid proxy URL %q", config.SpecificConfig.ProxyURL))
+		}
+		handler.transportConfig.Proxy = http.ProxyURL(proxyURL)
+	}
+	tlsSettings := config.SpecificConfig.TLS
+	tlsConfig, err := utils.TLSFiles{
+		CAFile:             tlsSettings.CAFile,
+		CertFile:           tlsSettings.CertFile,
+		KeyFile:            tlsSettings.KeyFile,
+		ServerName:         tlsSettings.ServerName,
+		InsecureSkipVerify: tlsSettings.InsecureSkipVerify,
+	}.Load()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfiguration, "volcengine handler: invalid TLS settings")
+	}
+	handler.transportConfig.TLSConfig = tlsConfig
+	pool := config.SpecificConfig.ConnectionPool
+	handler.transportConfig.MaxIdleConns = pool.MaxIdleConns
+	handler.transportConfig.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+	handler.transportConfig.MaxConnsPerHost = pool.MaxConnsPerHost
+	handler.transportConfig.IdleConnTimeout = pool.IdleConnTimeout
+
+	for k, v := range config.SpecificConfig.Headers {
+		handler.headers[k] = v
+	}
+
 	for _, opt := range opts {
 		opt(handler)
 	}
 
+	if handler.customHTTPClient != nil {
+		handler.httpClient, err = utils.ApplyTransportConfig(handler.customHTTPClient, handler.transportConfig)
+	} else {
+		handler.httpClient, err = utils.NewHTTPClient(handler.timeout, handler.transport, handler.transportConfig)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeConfiguration, "volcengine handler: failed to configure HTTP transport")
+	}
+	handler.api = &utils.APIClient{
+		HTTPClient:  handler.httpClient,
+		Platform:    "volcengine",
+		DecodeError: decodeVolcengineError,
+		Hooks:       handler.hooks,
+	}
+	handler.openAPI = &utils.APIClient{
+		HTTPClient:  handler.httpClient,
+		Platform:    "volcengine",
+		DecodeError: decodeVolcengineOpenAPIError,
+		Hooks:       handler.hooks,
+	}
+	handler.speechAPI = &utils.APIClient{
+		HTTPClient:  handler.httpClient,
+		Platform:    "volcengine",
+		DecodeError: decodeVolcengineSpeechError,
+		Hooks:       handler.hooks,
+	}
+
 	return handler, nil
 }
 
+// resolveBaseURL 根据平台特定配置确定 API 根地址。
+// 显式的 BaseURL 优先，否则按 Region 与 APIVersion 拼接火山方舟的默认地址。
+func resolveBaseURL(settings platform.ProviderSettings) (string, error) {
+	if settings.BaseURL != "" {
+		u, err := url.Parse(settings.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("volcengine handler: invalid base URL %q", settings.BaseURL))
+		}
+		return strings.TrimRight(settings.BaseURL, "/"), nil
+	}
+
+	region := settings.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+	apiVersion := settings.APIVersion
+	if apiVersion == "" {
+		apiVersion = DefaultAPIVersion
+	}
+	if strings.ContainsAny(region, "/.:") || strings.ContainsAny(apiVersion, "/.:") {
+		return "", errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("volcengine handler: invalid region %q or API version %q", region, apiVersion))
+	}
+	return fmt.Sprintf(volcengineBaseURLTemplate, region, apiVersion), nil
+}
+
+// requestHeaders 返回认证信息与自定义请求头。自定义请求头不会覆盖 Authorization。
+func (h *VolcengineHandler) requestHeaders() map[string]string {
+	headers := make(map[string]string, len(h.headers)+1)
+	for k, v := range h.headers {
+		headers[k] = v
+	}
+	headers["Authorization"] = "Bearer " + h.apiKey
+	return headers
+}
+
+// decodeVolcengineError 解析火山方舟的错误响应体。
+func decodeVolcengineError(statusCode int, body []byte) *errors.Error {
+	var errResp struct {
+		Error *volcengineError `json:"error"`
+	}
+	if json.Unmarshal(body, &errResp) != nil || errResp.Error == nil {
+		return nil
+	}
+	sdkErr := errors.New(utils.HTTPStatusErrorCode(statusCode), fmt.Sprintf("volcengine API error: status %d, code %s, message: %s", statusCode, errResp.Error.Code, errResp.Error.Message))
+	sdkErr.Underlying = errResp.Error
+	sdkErr.PlatformDetails = map[string]interface{}{"code": errResp.Error.Code, "type": errResp.Error.Type}
+	return sdkErr
+}
+
+// chatCompletionsURL 返回对话补全接口的完整地址。
+func (h *VolcengineHandler) chatCompletionsURL() string {
+	return h.baseURL + volcengineChatCompletionsPath
+}
+
+// contextChatURL 返回基于上下文缓存的对话补全接口的完整地址。
+func (h *VolcengineHandler) contextChatURL() string {
+	return h.baseURL + volcengineContextChatPath
+}
+
 // compile-time check to ensure VolcengineHandler implements PlatformHandler
-var _ platform.PlatformHandler = (*VolcengineHandler)(nil)
+var (
+	_ platform.PlatformHandler      = (*VolcengineHandler)(nil)
+	_ platform.RerankHandler        = (*VolcengineHandler)(nil)
+	_ platform.SpeechHandler        = (*VolcengineHandler)(nil)
+	_ platform.TranscriptionHandler = (*VolcengineHandler)(nil)
+	_ platform.ContextCacheHandler  = (*VolcengineHandler)(nil)
+	_ platform.BatchHandler         = (*VolcengineHandler)(nil)
+)
 
 func (e *volcengineError) Error() string {
 	return fmt.Sprintf("volcengine API error: code=%s, message=%s, type=%s", e.Code, e.Message, e.Type)
@@ -0,0 +1,147 @@
+package volcengine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func testConfig(settings platform.ProviderSettings) *platform.PlatformConfig {
+	return &platform.PlatformConfig{
+		Provider:       platform.ProviderVolcengine,
+		Credentials:    map[string]string{"apiKey": "test-key"},
+		SpecificConfig: settings,
+	}
+}
+
+func TestNewHandlerAppliesTransportSettings(t *testing.T) {
+	h, err := NewHandler(testConfig(platform.ProviderSettings{
+		ProxyURL:       "http://proxy.internal:3128",
+		ConnectionPool: platform.ConnectionPoolSettings{MaxIdleConnsPerHost: 8, MaxConnsPerHost: 16, IdleConnTimeout: time.Minute},
+	}))
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	tr, ok := h.httpClient.(*http.Client).Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport = %T, want *http.Transport", h.httpClient.(*http.Client).Transport)
+	}
+	if tr.MaxIdleConnsPerHost != 8 || tr.MaxConnsPerHost != 16 || tr.IdleConnTimeout != time.Minute {
+		t.Errorf("pool settings not applied: idle/host=%d conns/host=%d idle timeout=%v", tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost, tr.IdleConnTimeout)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://ark.cn-beijing.volces.com", nil)
+	proxy, _ := tr.Proxy(req)
+	if proxy == nil || proxy.Host != "proxy.internal:3128" {
+		t.Errorf("proxy = %v", proxy)
+	}
+}
+
+func TestNewHandlerRejectsUnconfigurableTransport(t *testing.T) {
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+	settings := platform.ProviderSettings{ProxyURL: "http://proxy.internal:3128"}
+
+	_, err := NewHandler(testConfig(settings), WithTransport(custom))
+	if !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("custom transport: err = %v, want %s", err, errors.ErrCodeConfiguration)
+	}
+	_, err = NewHandler(testConfig(settings), WithHTTPClient(&http.Client{Transport: custom}))
+	if !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("custom client: err = %v, want %s", err, errors.ErrCodeConfiguration)
+	}
+
+	// 没有代理、TLS 与连接池设置时可以注入任意 Transport。
+	if _, err := NewHandler(testConfig(platform.ProviderSettings{}), WithTransport(custom)); err != nil {
+		t.Errorf("custom transport without settings: %v", err)
+	}
+}
+
+func TestNewHandlerInvalidTLSSettings(t *testing.T) {
+	_, err := NewHandler(testConfig(platform.ProviderSettings{TLS: platform.TLSSettings{CAFile: "/nonexistent/ca.pem"}}))
+	if !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("err = %v, want %s", err, errors.ErrCodeConfiguration)
+	}
+}
+
+func TestResolveBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings platform.ProviderSettings
+		want     string
+		wantErr  bool
+	}{
+		{name: "default", want: "https://ark.cn-beijing.volces.com/api/v3"},
+		{name: "region and version", settings: platform.ProviderSettings{Region: "cn-shanghai", APIVersion: "v4"}, want: "https://ark.cn-shanghai.volces.com/api/v4"},
+		{name: "base URL wins", settings: platform.ProviderSettings{BaseURL: "http://127.0.0.1:8080/api/v3/", Region: "cn-shanghai"}, want: "http://127.0.0.1:8080/api/v3"},
+		{name: "base URL without scheme", settings: platform.ProviderSettings{BaseURL: "ark.example.com/api/v3"}, wantErr: true},
+		{name: "base URL with unsupported scheme", settings: platform.ProviderSettings{BaseURL: "ftp://ark.example.com"}, wantErr: true},
+		{name: "base URL without host", settings: platform.ProviderSettings{BaseURL: "https:///api/v3"}, wantErr: true},
+		{name: "region injecting a host", settings: platform.ProviderSettings{Region: "evil.com/x"}, wantErr: true},
+		{name: "api version with a port", settings: platform.ProviderSettings{APIVersion: "v3:443"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveBaseURL(tt.settings)
+			if tt.wantErr {
+				if !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+					t.Errorf("err = %v, want %s", err, errors.ErrCodeConfiguration)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveBaseURL = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHandlerInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *platform.PlatformConfig
+	}{
+		{name: "nil config"},
+		{name: "missing API key", config: &platform.PlatformConfig{Provider: platform.ProviderVolcengine, Credentials: map[string]string{"accessKeyId": "ak"}}},
+		{name: "empty API key", config: &platform.PlatformConfig{Provider: platform.ProviderVolcengine, Credentials: map[string]string{"apiKey": ""}}},
+		{name: "invalid base URL", config: testConfig(platform.ProviderSettings{BaseURL: "not a url"})},
+		{name: "invalid proxy", config: testConfig(platform.ProviderSettings{ProxyURL: "://proxy"})},
+	}
+	for _, tt := range tests {
+		if _, err := NewHandler(tt.config); !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+			t.Errorf("%s: err = %v, want %s", tt.name, err, errors.ErrCodeConfiguration)
+		}
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	var got http.Header
+	h, err := NewHandler(testConfig(platform.ProviderSettings{
+		BaseURL: newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Clone()
+			fmt.Fprint(w, `{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`)
+		}),
+		Headers: map[string]string{"X-Team": "search", "Authorization": "Bearer overridden"},
+	}))
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	if _, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: "hi"}); err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	// 自定义请求头附加到请求上，但不能覆盖认证信息。
+	if got.Get("X-Team") != "search" {
+		t.Errorf("X-Team = %q", got.Get("X-Team"))
+	}
+	if got.Get("Authorization") != "Bearer test-key" {
+		t.Errorf("Authorization = %q, want the configured API key", got.Get("Authorization"))
+	}
+}
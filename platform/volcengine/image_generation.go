@@ -3,12 +3,107 @@ package volcengine
 import (
 	"context"
 	"fmt"
+	"net/http"
 
+	"github.com/hewenyu/modelbridge/errors"
 	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/utils"
 )
 
-// ImageGeneration 占位符实现
+const (
+	volcengineImageGenerationsPath = "/images/generations"
+	// volcengineImageOutputFormat 是火山方舟生成图片的文件格式，接口不支持指定其他格式。
+	volcengineImageOutputFormat = "jpeg"
+)
+
+// volcengineImageResponse 是图片生成接口的响应结构。
+type volcengineImageResponse struct {
+	Model   string `json:"model"`
+	Created int64  `json:"created"`
+	Data    []struct {
+		URL     string `json:"url,omitempty"`
+		B64JSON string `json:"b64_json,omitempty"`
+	} `json:"data"`
+}
+
+// ImageGeneration 调用火山方舟的图片生成接口。未设置 Images 时为文生图 (例如 Seedream)，
+// 设置 Images 时为图生图或按指令编辑图片 (例如 SeedEdit)，多张参考图需要模型支持多图输入。
+// 接口每次只返回一张图片，且不支持蒙版、Strength 与指定文件格式，Quality 与 Style 会被忽略。
 func (h *VolcengineHandler) ImageGeneration(ctx context.Context, req *models.ImageGenerationRequest) (*models.ImageGenerationResponse, error) {
-	// h.logger.Println("Volcengine ImageGeneration not yet implemented.")
-	return nil, fmt.Errorf("volcengine handler: ImageGeneration not yet implemented") // errors.New(errors.ErrCodeUnsupported, "ImageGeneration not yet implemented for Volcengine")
+	body, err := buildImageRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var volcResp volcengineImageResponse
+	meta, err := h.api.DoJSON(ctx, &utils.Request{
+		Method: http.MethodPost,
+		URL:    h.baseURL + volcengineImageGenerationsPath,
+		Header: h.requestHeaders(),
+		Body:   body,
+	}, &volcResp)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.ImageGenerationResponse{ID: meta.RequestID, Images: make([]models.Image, 0, len(volcResp.Data))}
+	for _, d := range volcResp.Data {
+		resp.Images = append(resp.Images, models.Image{URL: d.URL, Base64: d.B64JSON})
+	}
+	return resp, nil
+}
+
+// buildImageRequest 将 models.ImageGenerationRequest 转换为请求体，PlatformSpecificParams 会覆盖同名参数 (例如 watermark)。
+func buildImageRequest(req *models.ImageGenerationRequest) (map[string]interface{}, error) {
+	unsupported := func(feature string) error {
+		return errors.New(errors.ErrCodeUnsupported, fmt.Sprintf("volcengine handler: image generation does not support %s", feature))
+	}
+	switch {
+	case req.Prompt == "":
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: image generation requires a prompt")
+	case req.N > 1:
+		return nil, unsupported("generating more than one image per request")
+	case req.Mask != "":
+		return nil, unsupported("mask-based inpainting, describe the edit in the prompt instead")
+	case req.Strength != nil:
+		return nil, unsupported("strength")
+	case req.OutputFormat != "" && req.OutputFormat != volcengineImageOutputFormat && req.OutputFormat != "jpg":
+		return nil, unsupported(fmt.Sprintf("output format %q", req.OutputFormat))
+	}
+	if g := req.GuidanceScale; g != nil && (*g < 1 || *g > 10) {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: guidance scale must be in [1, 10], got %v", *g))
+	}
+
+	body := map[string]interface{}{
+		"model":  req.Model,
+		"prompt": req.Prompt,
+	}
+	switch len(req.Images) {
+	case 0:
+	case 1:
+		body["image"] = req.Images[0]
+	default:
+		body["image"] = req.Images
+	}
+	if req.Size != "" {
+		body["size"] = req.Size
+	}
+	if req.GuidanceScale != nil {
+		body["guidance_scale"] = *req.GuidanceScale
+	}
+	if req.Seed != nil {
+		body["seed"] = *req.Seed
+	}
+	switch req.ResponseFormat {
+	case "", models.ImageResponseURL:
+		body["response_format"] = "url"
+	case models.ImageResponseBase64:
+		body["response_format"] = "b64_json"
+	default:
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: unsupported image response format %q", req.ResponseFormat))
+	}
+	for k, v := range req.PlatformSpecificParams {
+		body[k] = v
+	}
+	return body, nil
 }
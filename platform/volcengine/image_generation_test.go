@@ -0,0 +1,105 @@
+package volcengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+func TestBuildImageRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.ImageGenerationRequest
+		want map[string]interface{}
+	}{
+		{
+			name: "text to image",
+			req:  models.ImageGenerationRequest{Model: "seedream", Prompt: "a cat", Size: "1024x1024", Seed: models.Ptr[int64](7)},
+			want: map[string]interface{}{"model": "seedream", "prompt": "a cat", "size": "1024x1024", "seed": int64(7), "response_format": "url"},
+		},
+		{
+			name: "single reference image",
+			req:  models.ImageGenerationRequest{Model: "seededit", Prompt: "make it blue", Images: []string{"https://x/a.png"}, GuidanceScale: models.Ptr[float32](5.5)},
+			want: map[string]interface{}{"model": "seededit", "prompt": "make it blue", "image": "https://x/a.png", "guidance_scale": float32(5.5), "response_format": "url"},
+		},
+		{
+			name: "multiple reference images",
+			req:  models.ImageGenerationRequest{Model: "m", Prompt: "merge", Images: []string{"a", "b"}},
+			want: map[string]interface{}{"model": "m", "prompt": "merge", "image": []string{"a", "b"}, "response_format": "url"},
+		},
+		{
+			name: "base64 response and platform params",
+			req: models.ImageGenerationRequest{
+				Model: "m", Prompt: "p", ResponseFormat: models.ImageResponseBase64, OutputFormat: "jpg",
+				PlatformSpecificParams: map[string]interface{}{"watermark": false, "size": "2K"},
+			},
+			want: map[string]interface{}{"model": "m", "prompt": "p", "response_format": "b64_json", "watermark": false, "size": "2K"},
+		},
+		{
+			name: "explicit url response and guidance bounds",
+			req:  models.ImageGenerationRequest{Model: "m", Prompt: "p", ResponseFormat: models.ImageResponseURL, GuidanceScale: models.Ptr[float32](10)},
+			want: map[string]interface{}{"model": "m", "prompt": "p", "guidance_scale": float32(10), "response_format": "url"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildImageRequest(&tt.req)
+			if err != nil {
+				t.Fatalf("buildImageRequest: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildImageRequestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  models.ImageGenerationRequest
+		want string
+	}{
+		{"missing prompt", models.ImageGenerationRequest{}, errors.ErrCodeInvalidRequest},
+		{"guidance too low", models.ImageGenerationRequest{Prompt: "p", GuidanceScale: models.Ptr[float32](0.5)}, errors.ErrCodeInvalidRequest},
+		{"guidance too high", models.ImageGenerationRequest{Prompt: "p", GuidanceScale: models.Ptr[float32](10.5)}, errors.ErrCodeInvalidRequest},
+		{"response format", models.ImageGenerationRequest{Prompt: "p", ResponseFormat: "binary"}, errors.ErrCodeInvalidRequest},
+		{"multiple images", models.ImageGenerationRequest{Prompt: "p", N: 2}, errors.ErrCodeUnsupported},
+		{"mask", models.ImageGenerationRequest{Prompt: "p", Images: []string{"a"}, Mask: "m"}, errors.ErrCodeUnsupported},
+		{"strength", models.ImageGenerationRequest{Prompt: "p", Images: []string{"a"}, Strength: models.Ptr[float32](0.3)}, errors.ErrCodeUnsupported},
+		{"output format", models.ImageGenerationRequest{Prompt: "p", OutputFormat: "png"}, errors.ErrCodeUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildImageRequest(&tt.req); !errors.IsSDKError(err, tt.want) {
+				t.Errorf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageGeneration(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if r.URL.Path != volcengineImageGenerationsPath || json.NewDecoder(r.Body).Decode(&body) != nil || body["image"] != "https://x/a.png" {
+			t.Errorf("request = %s %v", r.URL.Path, body)
+		}
+		w.Header().Set("X-Client-Request-Id", "img-1")
+		fmt.Fprint(w, `{"model":"seededit","created":1,"data":[{"b64_json":"aGk="}]}`)
+	})
+	resp, err := h.ImageGeneration(context.Background(), &models.ImageGenerationRequest{
+		Model: "seededit", Prompt: "p", Images: []string{"https://x/a.png"}, ResponseFormat: models.ImageResponseBase64,
+	})
+	if err != nil {
+		t.Fatalf("ImageGeneration: %v", err)
+	}
+	if resp.ID != "img-1" || !reflect.DeepEqual(resp.Images, []models.Image{{Base64: "aGk="}}) {
+		t.Errorf("resp = %+v", resp)
+	}
+}
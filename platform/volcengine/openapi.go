@@ -0,0 +1,154 @@
+package volcengine
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/utils"
+)
+
+const (
+	volcengineAccessKeyName      = "accessKeyId"     // 火山引擎 OpenAPI (管控面) 使用的 Access Key ID
+	volcengineSecretKeyName      = "secretAccessKey" // 火山引擎 OpenAPI (管控面) 使用的 Secret Access Key
+	volcengineOpenAPIURLTemplate = "https://ark.%s.volcengineapi.com"
+	volcengineOpenAPIVersion     = "2024-01-01"
+	volcengineOpenAPIService     = "ark"
+)
+
+// volcengineOpenAPIResponse 是火山引擎 OpenAPI 的通用响应结构。
+type volcengineOpenAPIResponse struct {
+	ResponseMetadata struct {
+		RequestID string                  `json:"RequestId"`
+		Error     *volcengineOpenAPIError `json:"Error,omitempty"`
+	} `json:"ResponseMetadata"`
+	Result json.RawMessage `json:"Result"`
+}
+
+type volcengineOpenAPIError struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+func (e *volcengineOpenAPIError) Error() string {
+	return fmt.Sprintf("volcengine OpenAPI error: code=%s, message=%s", e.Code, e.Message)
+}
+
+// hasOpenAPICredentials 报告是否配置了调用 OpenAPI 所需的 AK/SK。
+func (h *VolcengineHandler) hasOpenAPICredentials() bool {
+	return h.accessKey != "" && h.secretKey != ""
+}
+
+// callOpenAPI 调用火山引擎 OpenAPI 的 action，将 Result 解码到 out。请求使用 AK/SK 签名。
+func (h *VolcengineHandler) callOpenAPI(ctx context.Context, action string, body interface{}, out interface{}) error {
+	if !h.hasOpenAPICredentials() {
+		return errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("volcengine handler: %s requires %s and %s in credentials", action, volcengineAccessKeyName, volcengineSecretKeyName))
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, fmt.Sprintf("volcengine handler: failed to marshal %s request", action))
+	}
+
+	query := url.Values{"Action": {action}, "Version": {volcengineOpenAPIVersion}}
+	endpoint := h.openAPIURL + "/?" + query.Encode()
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeConfiguration, fmt.Sprintf("volcengine handler: invalid OpenAPI endpoint %q", h.openAPIURL))
+	}
+
+	var resp volcengineOpenAPIResponse
+	if _, err := h.openAPI.DoJSON(ctx, &utils.Request{
+		Method: http.MethodPost,
+		URL:    endpoint,
+		Header: h.signOpenAPIRequest(http.MethodPost, u, payload, time.Now()),
+		Body:   json.RawMessage(payload),
+	}, &resp); err != nil {
+		return err
+	}
+	if e := resp.ResponseMetadata.Error; e != nil {
+		sdkErr := errors.Wrap(e, errors.ErrCodePlatformError, fmt.Sprintf("volcengine %s failed: code %s, message: %s", action, e.Code, e.Message))
+		sdkErr.PlatformDetails = map[string]interface{}{"code": e.Code, "request_id": resp.ResponseMetadata.RequestID}
+		return sdkErr
+	}
+	if out != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, out); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInvalidResponse, fmt.Sprintf("volcengine handler: failed to unmarshal %s result", action))
+		}
+	}
+	return nil
+}
+
+// signOpenAPIRequest 按火山引擎 HMAC-SHA256 签名算法生成认证请求头。
+// 签名覆盖 content-type、host、x-content-sha256 与 x-date 四个请求头。
+func (h *VolcengineHandler) signOpenAPIRequest(method string, u *url.URL, payload []byte, now time.Time) map[string]string {
+	xDate := now.UTC().Format("20060102T150405Z")
+	shortDate := xDate[:8]
+	payloadHash := sha256Hex(payload)
+
+	const signedHeaders = "content-type;host;x-content-sha256;x-date"
+	canonicalHeaders := "content-type:application/json\n" +
+		"host:" + u.Host + "\n" +
+		"x-content-sha256:" + payloadHash + "\n" +
+		"x-date:" + xDate + "\n"
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		strings.ReplaceAll(u.Query().Encode(), "+", "%20"),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{shortDate, h.region, volcengineOpenAPIService, "request"}, "/")
+	stringToSign := strings.Join([]string{"HMAC-SHA256", xDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte(h.secretKey), shortDate)
+	key = hmacSHA256(key, h.region)
+	key = hmacSHA256(key, volcengineOpenAPIService)
+	key = hmacSHA256(key, "request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return map[string]string{
+		"X-Date":           xDate,
+		"X-Content-Sha256": payloadHash,
+		"Authorization":    fmt.Sprintf("HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", h.accessKey, scope, signedHeaders, signature),
+	}
+}
+
+// decodeVolcengineOpenAPIError 解析 OpenAPI 非 2xx 响应中的错误信息。
+func decodeVolcengineOpenAPIError(statusCode int, body []byte) *errors.Error {
+	var resp volcengineOpenAPIResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.ResponseMetadata.Error == nil {
+		return nil
+	}
+	e := resp.ResponseMetadata.Error
+	sdkErr := errors.Wrap(e, utils.HTTPStatusErrorCode(statusCode), fmt.Sprintf("volcengine OpenAPI error: status code %d, code %s, message: %s", statusCode, e.Code, e.Message))
+	sdkErr.PlatformDetails = map[string]interface{}{"code": e.Code}
+	if resp.ResponseMetadata.RequestID != "" {
+		sdkErr.PlatformDetails["request_id"] = resp.ResponseMetadata.RequestID
+	}
+	return sdkErr
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
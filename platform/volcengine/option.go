@@ -1,6 +1,15 @@
 package volcengine
 
-import "time"
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hewenyu/modelbridge/batch"
+	"github.com/hewenyu/modelbridge/utils"
+)
 
 // Option 是用于配置 VolcengineHandler 的选项。
 type Option func(*VolcengineHandler)
@@ -8,6 +17,85 @@ type Option func(*VolcengineHandler)
 // WithTimeout 设置 HTTP 请求的超时时间
 func WithTimeout(timeout time.Duration) Option {
 	return func(h *VolcengineHandler) {
-		h.httpClient.Timeout = timeout
+		h.timeout = timeout
+	}
+}
+
+// WithHTTPClient 设置自定义的 HTTPClient，例如测试中录制/回放请求的客户端。
+// 设置后 WithTimeout 与 WithTransport 不再生效；代理、TLS 与连接池设置只能应用于
+// Transport 为 *http.Transport 的 *http.Client，其他客户端同时设置这些选项时 NewHandler 返回配置错误。
+func WithHTTPClient(client utils.HTTPClient) Option {
+	return func(h *VolcengineHandler) {
+		h.customHTTPClient = client
+	}
+}
+
+// WithTransport 设置底层的 http.RoundTripper。
+// 代理、TLS 与连接池选项会在其副本上生效，因此同时设置这些选项时 transport 必须是 *http.Transport，
+// 否则 NewHandler 返回配置错误。
+func WithTransport(transport http.RoundTripper) Option {
+	return func(h *VolcengineHandler) {
+		h.transport = transport
+	}
+}
+
+// WithProxy 设置代理选择函数，例如 http.ProxyURL(u)。
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(h *VolcengineHandler) {
+		h.transportConfig.Proxy = proxy
+	}
+}
+
+// WithTLSConfig 设置 TLS 配置，例如企业内部的根证书。
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(h *VolcengineHandler) {
+		h.transportConfig.TLSConfig = tlsConfig
+	}
+}
+
+// WithConnectionPool 调整连接池参数，零值表示沿用默认设置。
+func WithConnectionPool(maxIdleConns, maxIdleConnsPerHost int, idleConnTimeout time.Duration) Option {
+	return func(h *VolcengineHandler) {
+		h.transportConfig.MaxIdleConns = maxIdleConns
+		h.transportConfig.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		h.transportConfig.IdleConnTimeout = idleConnTimeout
+	}
+}
+
+// WithMaxConnsPerHost 限制每个主机的最大连接数 (包含活跃连接)，零值表示不限制。
+func WithMaxConnsPerHost(n int) Option {
+	return func(h *VolcengineHandler) {
+		h.transportConfig.MaxConnsPerHost = n
+	}
+}
+
+// WithHooks 设置请求生命周期回调，例如记录每次 HTTP 调用的日志。
+func WithHooks(hooks utils.Hooks) Option {
+	return func(h *VolcengineHandler) {
+		h.hooks = hooks
+	}
+}
+
+// WithOpenAPIEndpoint 覆盖 OpenAPI (管控面) 的地址，默认为 https://ark.{region}.volcengineapi.com。
+func WithOpenAPIEndpoint(endpoint string) Option {
+	return func(h *VolcengineHandler) {
+		h.openAPIURL = strings.TrimRight(endpoint, "/")
+	}
+}
+
+// WithBatchStorage 设置批量推理任务读写文件所用的 TOS 存储，文件存放在 bucket 的 prefix 目录下。
+// 同时需要在凭证中配置 accessKeyId 与 secretAccessKey，否则批量任务会由客户端在本地执行。
+func WithBatchStorage(storage batch.Storage, bucket, prefix string) Option {
+	return func(h *VolcengineHandler) {
+		h.batchStorage = storage
+		h.batchLocation = batch.Location{Bucket: bucket, Key: strings.Trim(prefix, "/")}
+	}
+}
+
+// WithSpeechEndpoint 覆盖豆包语音接口的地址，默认为 DefaultSpeechEndpoint。
+// 同时需要在凭证中配置 speechAppId 与 speechAccessToken。
+func WithSpeechEndpoint(endpoint string) Option {
+	return func(h *VolcengineHandler) {
+		h.speechURL = strings.TrimRight(endpoint, "/")
 	}
 }
@@ -0,0 +1,14 @@
+package volcengine
+
+import (
+	"context"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+// Rerank 火山方舟模型推理接口不提供排序模型，始终返回 ErrUnsupportedOperation 错误。
+// 需要排序时请使用 rerank 平台 (platform/rerank) 对接兼容 /rerank 接口的服务。
+func (h *VolcengineHandler) Rerank(ctx context.Context, req *models.RerankRequest) (*models.RerankResponse, error) {
+	return nil, errors.New(errors.ErrCodeUnsupported, "volcengine handler: rerank is not supported by Volcengine Ark, use the rerank provider instead")
+}
@@ -0,0 +1,20 @@
+package volcengine
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+)
+
+func TestRerankUnsupported(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+	req := &models.RerankRequest{Model: "m", Query: "q", Documents: []string{"a"}}
+	if _, err := h.Rerank(context.Background(), req); !errors.IsSDKError(err, errors.ErrCodeUnsupported) {
+		t.Errorf("err = %v, want %s", err, errors.ErrCodeUnsupported)
+	}
+}
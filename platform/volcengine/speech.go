@@ -0,0 +1,91 @@
+package volcengine
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/utils"
+)
+
+// 豆包语音 (openspeech) 使用与火山方舟不同的鉴权方式，需要在控制台单独开通应用。
+const (
+	volcengineSpeechAppIDName     = "speechAppId"       // 豆包语音应用的 APP ID
+	volcengineSpeechAccessKeyName = "speechAccessToken" // 豆包语音应用的 Access Token
+	DefaultSpeechEndpoint         = "https://openspeech.bytedance.com"
+
+	// volcengineSpeechSuccessCode 是豆包语音 V3 接口表示请求成功结束的状态码。
+	volcengineSpeechSuccessCode = 20000000
+	// volcengineSpeechSilenceCode 表示语音识别的音频中没有有效语音，识别结果为空。
+	volcengineSpeechSilenceCode = 20000003
+)
+
+// hasSpeechCredentials 报告是否配置了调用豆包语音所需的 APP ID 与 Access Token。
+func (h *VolcengineHandler) hasSpeechCredentials() bool {
+	return h.speechAppID != "" && h.speechAccessKey != ""
+}
+
+// speechHeaders 返回豆包语音 V3 接口的鉴权请求头。自定义请求头不会覆盖鉴权信息。
+// 不同接口携带 APP ID 的请求头名称不同，由 appIDHeader 指定。
+func (h *VolcengineHandler) speechHeaders(appIDHeader, resourceID, requestID string) map[string]string {
+	headers := make(map[string]string, len(h.headers)+4)
+	for k, v := range h.headers {
+		headers[k] = v
+	}
+	headers[appIDHeader] = h.speechAppID
+	headers["X-Api-Access-Key"] = h.speechAccessKey
+	headers["X-Api-Resource-Id"] = resourceID
+	headers["X-Api-Request-Id"] = requestID
+	return headers
+}
+
+// volcengineSpeechStatus 是豆包语音 V3 接口响应中的状态信息。
+type volcengineSpeechStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// err 将非成功的状态码转换为 SDK 错误，code 为 0 (处理中) 或成功结束时返回 nil。
+func (s volcengineSpeechStatus) err(operation string) error {
+	if s.Code == 0 || s.Code == volcengineSpeechSuccessCode || s.Code == volcengineSpeechSilenceCode {
+		return nil
+	}
+	sdkErr := errors.New(speechStatusErrorCode(s.Code), fmt.Sprintf("volcengine %s failed: code %d, message: %s", operation, s.Code, s.Message))
+	sdkErr.PlatformDetails = map[string]interface{}{"code": s.Code}
+	return sdkErr
+}
+
+// speechStatusErrorCode 将豆包语音的状态码映射为 SDK 错误代码。
+// 状态码的前三位与 HTTP 状态码含义一致，例如 45000001 表示请求参数错误。
+func speechStatusErrorCode(code int) string {
+	s := strconv.Itoa(code)
+	if len(s) < 3 {
+		return errors.ErrCodePlatformError
+	}
+	status, _ := strconv.Atoi(s[:3])
+	return utils.HTTPStatusErrorCode(status)
+}
+
+// decodeVolcengineSpeechError 解析豆包语音的错误响应体。
+func decodeVolcengineSpeechError(statusCode int, body []byte) *errors.Error {
+	var status volcengineSpeechStatus
+	if json.Unmarshal(body, &status) != nil || status.Code == 0 {
+		return nil
+	}
+	sdkErr := errors.New(utils.HTTPStatusErrorCode(statusCode), fmt.Sprintf("volcengine speech API error: status %d, code %d, message: %s", statusCode, status.Code, status.Message))
+	sdkErr.PlatformDetails = map[string]interface{}{"code": status.Code}
+	return sdkErr
+}
+
+// newSpeechRequestID 生成豆包语音接口要求的请求 ID。
+func newSpeechRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("modelbridge-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
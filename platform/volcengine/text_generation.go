@@ -1,40 +1,50 @@
 package volcengine
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 
+	"github.com/hewenyu/modelbridge/errors"
 	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/utils"
 )
 
 // volcengineChatRequest 是火山方舟对话 API 的请求体结构。
 type volcengineChatRequest struct {
-	Model         volcengineModel          `json:"model"`
-	Messages      []volcengineChatMessage  `json:"messages"`
-	Stream        bool                     `json:"stream,omitempty"`
-	StreamOptions *volcengineStreamOptions `json:"stream_options,omitempty"`
-	User          string                   `json:"user,omitempty"`
-	MaxTokens     int                      `json:"max_tokens,omitempty"`
-	Temperature   float32                  `json:"temperature,omitempty"`
-	TopP          float32                  `json:"top_p,omitempty"`
-	Stop          []string                 `json:"stop,omitempty"`
+	Model            string                     `json:"model"`                // 模型 ID、推理接入点 ID 或应用 ID
+	ContextID        string                     `json:"context_id,omitempty"` // 仅用于上下文缓存对话接口
+	Messages         []volcengineRequestMessage `json:"messages"`
+	Stream           bool                       `json:"stream,omitempty"`
+	StreamOptions    *volcengineStreamOptions   `json:"stream_options,omitempty"`
+	User             string                     `json:"user,omitempty"`
+	MaxTokens        int                        `json:"max_tokens,omitempty"`
+	Temperature      *float32                   `json:"temperature,omitempty"`
+	TopP             *float32                   `json:"top_p,omitempty"`
+	FrequencyPenalty *float32                   `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float32                   `json:"presence_penalty,omitempty"`
+	Seed             *int64                     `json:"seed,omitempty"`
+	LogitBias        map[string]int             `json:"logit_bias,omitempty"`
+	N                int                        `json:"n,omitempty"`
+	Logprobs         bool                       `json:"logprobs,omitempty"`
+	TopLogprobs      int                        `json:"top_logprobs,omitempty"`
+	Stop             []string                   `json:"stop,omitempty"`
+	Thinking         *volcengineThinking        `json:"thinking,omitempty"`
+	ResponseFormat   *models.ResponseFormat     `json:"response_format,omitempty"` // 与火山方舟的 response_format 结构一致
 	// Tools          []volcengineTool          `json:"tools,omitempty"` // 暂时不支持
 }
 
-type volcengineModel struct {
-	ModelID string `json:"model_id"`
-	Version string `json:"version,omitempty"`
+type volcengineChatMessage struct {
+	Role             string `json:"role"` // user, assistant, system
+	Content          string `json:"content"`
+	ReasoningContent string `json:"reasoning_content,omitempty"` // 推理模型返回的思考过程，仅出现在响应中
 }
 
-type volcengineChatMessage struct {
-	Role    string `json:"role"` // user, assistant, system
-	Content string `json:"content"`
+// volcengineThinking 控制深度思考模型是否输出思考过程。
+type volcengineThinking struct {
+	Type string `json:"type"` // enabled, disabled, auto
 }
 
 type volcengineStreamOptions struct {
@@ -43,43 +53,87 @@ type volcengineStreamOptions struct {
 
 // volcengineChatResponse 是火山方舟对话 API 的响应体结构 (非流式)。
 type volcengineChatResponse struct {
-	ID      string               `json:"id"`
-	Object  string               `json:"object"`
-	Created int64                `json:"created"`
-	Model   string               `json:"model"`
-	Choices []volcengineChoice   `json:"choices"`
-	Usage   volcengineTokenUsage `json:"usage"`
-	Error   *volcengineError     `json:"error,omitempty"` // 火山方舟特定的错误结构
+	ID         string                `json:"id"`
+	Object     string                `json:"object"`
+	Created    int64                 `json:"created"`
+	Model      string                `json:"model"`
+	Choices    []volcengineChoice    `json:"choices"`
+	Usage      volcengineTokenUsage  `json:"usage"`
+	References []volcengineReference `json:"references,omitempty"` // 仅应用接口返回
+	BotUsage   *volcengineBotUsage   `json:"bot_usage,omitempty"`  // 仅应用接口返回
+	Error      *volcengineError      `json:"error,omitempty"`      // 火山方舟特定的错误结构
 }
 
 // volcengineStreamChatCompletionChunk 是火山方舟对话 API 流式响应中每个 chunk 的结构。
 type volcengineStreamChatCompletionChunk struct {
-	ID      string                   `json:"id"`
-	Object  string                   `json:"object"` // e.g., "chat.completion.chunk"
-	Created int64                    `json:"created"`
-	Model   string                   `json:"model"`
-	Choices []volcengineStreamChoice `json:"choices"`
-	Usage   *volcengineTokenUsage    `json:"usage,omitempty"` // Typically null until the last chunk if include_usage is true
-	Error   *volcengineError         `json:"error,omitempty"`
+	ID         string                   `json:"id"`
+	Object     string                   `json:"object"` // e.g., "chat.completion.chunk"
+	Created    int64                    `json:"created"`
+	Model      string                   `json:"model"`
+	Choices    []volcengineStreamChoice `json:"choices"`
+	Usage      *volcengineTokenUsage    `json:"usage,omitempty"` // Typically null until the last chunk if include_usage is true
+	References []volcengineReference    `json:"references,omitempty"`
+	BotUsage   *volcengineBotUsage      `json:"bot_usage,omitempty"` // 应用接口在最后一个数据块中返回
+	Error      *volcengineError         `json:"error,omitempty"`
 }
 
 type volcengineStreamChoice struct {
 	Index        int                   `json:"index"`
 	Delta        volcengineChatMessage `json:"delta"`                   // Contains the incremental content
 	FinishReason *string               `json:"finish_reason,omitempty"` // Null until the last chunk for a choice
-	// Logprobs     interface{} `json:"logprobs,omitempty"` // Not handled yet
+	Logprobs     *volcengineLogprobs   `json:"logprobs,omitempty"`
 }
 
 type volcengineChoice struct {
 	Index        int                   `json:"index"`
 	Message      volcengineChatMessage `json:"message"`
 	FinishReason string                `json:"finish_reason"`
+	Logprobs     *volcengineLogprobs   `json:"logprobs,omitempty"`
+}
+
+// volcengineLogprobs 是开启 logprobs 后每个 choice 附带的对数概率信息。
+type volcengineLogprobs struct {
+	Content []models.TokenLogprob `json:"content"` // 结构与 OpenAI 兼容，可直接复用通用类型
+}
+
+// tokens 返回对数概率列表，未开启 logprobs 时为 nil。
+func (l *volcengineLogprobs) tokens() []models.TokenLogprob {
+	if l == nil {
+		return nil
+	}
+	return l.Content
 }
 
 type volcengineTokenUsage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens            int                                `json:"prompt_tokens"`
+	CompletionTokens        int                                `json:"completion_tokens"`
+	TotalTokens             int                                `json:"total_tokens"`
+	PromptTokensDetails     *volcenginePromptTokensDetails     `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails *volcengineCompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+type volcenginePromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+type volcengineCompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// toUsage 将平台的用量信息转换为 models.Usage，未提供的明细为 0。
+func (u volcengineTokenUsage) toUsage() models.Usage {
+	usage := models.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if u.PromptTokensDetails != nil {
+		usage.CachedTokens = u.PromptTokensDetails.CachedTokens
+	}
+	if u.CompletionTokensDetails != nil {
+		usage.ReasoningTokens = u.CompletionTokensDetails.ReasoningTokens
+	}
+	return usage
 }
 
 // volcengineError 定义了火山方舟 API 返回的错误信息结构。
@@ -92,217 +146,282 @@ type volcengineError struct {
 // TextGeneration 实现文本生成逻辑。
 func (h *VolcengineHandler) TextGeneration(ctx context.Context, req *models.TextGenerationRequest) (*models.TextGenerationResponse, error) {
 	if req == nil {
-		return nil, fmt.Errorf("volcengine handler: text generation request cannot be nil") // errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: text generation request cannot be nil")
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: text generation request cannot be nil")
 	}
 
-	// 1. 将 models.TextGenerationRequest 转换为 volcengineChatRequest
-	volcReq := volcengineChatRequest{
-		Model: volcengineModel{
-			ModelID: req.Model, // 假设用户直接提供火山方舟的模型 ID
-			// Version: "...", //  如果需要，可以从 req.PlatformSpecificParams 获取
-		},
-		Messages: []volcengineChatMessage{
-			{
-				Role:    "user", // 默认将 prompt 作为 user message
-				Content: req.Prompt,
-			},
-		},
-		Stream:      req.Stream,
-		MaxTokens:   req.MaxTokens,
-		Temperature: req.Temperature,
-		TopP:        req.TopP,
-		Stop:        req.StopSequences,
-		// User: 从 req.PlatformSpecificParams 获取,
-	}
-
-	// 如果是流式请求且需要在最后包含用量信息
-	if req.Stream {
-		// 示例：从 PlatformSpecificParams 获取 stream_options.include_usage
-		if includeUsage, ok := req.PlatformSpecificParams["volc_stream_options_include_usage"].(bool); ok && includeUsage {
-			volcReq.StreamOptions = &volcengineStreamOptions{IncludeUsage: true}
-		}
-	}
-
-	// TODO: 处理 req.PlatformSpecificParams 中更复杂的 messages 结构 (system, assistant roles)
-	// TODO: 处理 Tools (如果未来支持)
-
-	// 2. 序列化请求体
-	reqBodyBytes, err := json.Marshal(volcReq)
+	volcReq, err := buildChatRequest(req)
 	if err != nil {
-		// h.logger.Printf("Error marshalling volcengine request: %v", err)
-		return nil, fmt.Errorf("volcengine handler: failed to marshal request body: %w", err) // errors.Wrap(err, errors.ErrCodeInternal, "volcengine handler: failed to marshal request body")
+		return nil, err
 	}
 
-	// 3. 创建 HTTP 请求
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, volcengineChatCompletionsURL, bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		// h.logger.Printf("Error creating volcengine HTTP request: %v", err)
-		return nil, fmt.Errorf("volcengine handler: failed to create HTTP request: %w", err) // errors.Wrap(err, errors.ErrCodeInternal, "volcengine handler: failed to create HTTP request")
+	apiReq := &utils.Request{
+		Method: http.MethodPost,
+		URL:    h.chatCompletionsURL(),
+		Header: h.requestHeaders(),
+		Body:   volcReq,
 	}
-
-	// 4. 设置请求头
-	httpReq.Header.Set("Authorization", "Bearer "+h.apiKey)
-	httpReq.Header.Set("Content-Type", "application/json")
-	if req.Stream {
-		httpReq.Header.Set("Accept", "text/event-stream")
-	} else {
-		httpReq.Header.Set("Accept", "application/json")
-	}
-
-	// 5. 发送请求
-	// h.logger.Printf("Sending TextGeneration request to Volcengine for model: %s", req.Model)
-	httpResp, err := h.httpClient.Do(httpReq)
-	if err != nil {
-		// h.logger.Printf("Error sending request to Volcengine: %v", err)
-		return nil, fmt.Errorf("volcengine handler: failed to send HTTP request: %w", err) // errors.Wrap(err, errors.ErrCodePlatformError, "volcengine handler: failed to send HTTP request")
-	}
-	defer httpResp.Body.Close()
-
-	// 6. 处理响应
-	if httpResp.StatusCode != http.StatusOK {
-		respBodyBytes, _ := io.ReadAll(httpResp.Body) // Try to read body for error details
-		// h.logger.Printf("Volcengine API error: status code %d, body: %s", httpResp.StatusCode, string(respBodyBytes))
-		var volcErrResp volcengineChatResponse // Try to parse as non-stream error first
-		if json.Unmarshal(respBodyBytes, &volcErrResp) == nil && volcErrResp.Error != nil {
-			return nil, fmt.Errorf("volcengine API error: status %d, code %s, message: %s", httpResp.StatusCode, volcErrResp.Error.Code, volcErrResp.Error.Message)
-		}
-		var volcStreamErrResp volcengineStreamChatCompletionChunk // Try to parse as stream error
-		if json.Unmarshal(respBodyBytes, &volcStreamErrResp) == nil && volcStreamErrResp.Error != nil {
-			return nil, fmt.Errorf("volcengine API stream error: status %d, code %s, message: %s", httpResp.StatusCode, volcStreamErrResp.Error.Code, volcStreamErrResp.Error.Message)
-		}
-		return nil, fmt.Errorf("volcengine API error: status code %d, response: %s", httpResp.StatusCode, string(respBodyBytes))
-		// errors.New(errors.ErrCodePlatformError, fmt.Sprintf("volcengine API error: status code %d, response: %s\", httpResp.StatusCode, string(respBodyBytes)))
+	switch {
+	case volcReq.ContextID != "" && isBotID(req.Model):
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: context cache cannot be used with a bot")
+	case volcReq.ContextID != "":
+		apiReq.URL = h.contextChatURL()
+	case isBotID(req.Model):
+		apiReq.URL = h.botsChatURL()
 	}
 
 	if req.Stream {
-		// 处理流式响应
-		var fullTextBuilder strings.Builder
+		// 处理流式响应，按 choice 的 index 分别累积内容
+		choices := make(map[int]*streamChoiceAccumulator)
 		var finalResponseID string
-		var finalFinishReason string
-		var finalTokenUsage struct {
-			PromptTokens     int `json:"prompt_tokens"`
-			CompletionTokens int `json:"completion_tokens"`
-			TotalTokens      int `json:"total_tokens"`
-		}
-
-		scanner := bufio.NewScanner(httpResp.Body)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if line == "" { // Skip empty lines
-				continue
-			}
-
-			var dataStr string
-			if strings.HasPrefix(line, sseDataPrefix) {
-				dataStr = strings.TrimPrefix(line, sseDataPrefix)
-			} else if strings.HasPrefix(line, sseDataPrefixNoSpace) { // Handle case without space after "data:"
-				dataStr = strings.TrimPrefix(line, sseDataPrefixNoSpace)
-			} else {
-				// h.logger.Printf("Volcengine stream: received non-SSE line: %s", line)
-				continue // Ignore lines not starting with "data: " or "data:"
-			}
-
-			if dataStr == sseDoneMessage {
-				// h.logger.Println("Volcengine stream: received [DONE] message.")
-				break // End of stream
-			}
+		var finalTokenUsage volcengineTokenUsage
+		var references []models.Reference
+		var botUsage *volcengineBotUsage
 
+		_, err := h.api.DoSSE(ctx, apiReq, func(event *utils.SSEEvent) error {
 			var chunk volcengineStreamChatCompletionChunk
-			if err := json.Unmarshal([]byte(dataStr), &chunk); err != nil {
-				// h.logger.Printf("Error unmarshalling Volcengine stream chunk: %v. Data: %s", err, dataStr)
-				return nil, fmt.Errorf("volcengine handler: failed to unmarshal stream chunk: %w. Data: %s", err, dataStr)
+			if err := json.Unmarshal([]byte(event.Data), &chunk); err != nil {
+				return errors.Wrap(err, errors.ErrCodeInvalidResponse, fmt.Sprintf("volcengine handler: failed to unmarshal stream chunk. Data: %s", event.Data))
 			}
 
 			if chunk.Error != nil {
-				// h.logger.Printf("Volcengine stream error in chunk: code %s, message %s", chunk.Error.Code, chunk.Error.Message)
-				return nil, fmt.Errorf("volcengine stream error: code %s, message: %s", chunk.Error.Code, chunk.Error.Message)
+				return errors.Wrap(chunk.Error, errors.ErrCodePlatformError, fmt.Sprintf("volcengine stream error: code %s, message: %s", chunk.Error.Code, chunk.Error.Message))
 			}
 
 			if finalResponseID == "" {
 				finalResponseID = chunk.ID
 			}
 
-			if len(chunk.Choices) > 0 {
-				choice := chunk.Choices[0]
-				fullTextBuilder.WriteString(choice.Delta.Content)
-				if choice.FinishReason != nil {
-					finalFinishReason = *choice.FinishReason
+			for _, choice := range chunk.Choices {
+				acc, ok := choices[choice.Index]
+				if !ok {
+					acc = &streamChoiceAccumulator{}
+					choices[choice.Index] = acc
+				}
+				acc.add(choice)
+				if req.OnStreamChunk != nil && (choice.Delta.Content != "" || choice.Delta.ReasoningContent != "") {
+					if err := req.OnStreamChunk(&models.TextGenerationStreamChunk{
+						ID:        chunk.ID,
+						Index:     choice.Index,
+						Delta:     choice.Delta.Content,
+						Reasoning: choice.Delta.ReasoningContent,
+						Logprobs:  choice.Logprobs.tokens(),
+					}); err != nil {
+						return err
+					}
 				}
 			}
 
+			// 开启 stream_options.include_usage 时，用量信息出现在 [DONE] 之前的最后一个数据块中。
 			if chunk.Usage != nil {
-				finalTokenUsage.PromptTokens = chunk.Usage.PromptTokens
-				finalTokenUsage.CompletionTokens = chunk.Usage.CompletionTokens
-				finalTokenUsage.TotalTokens = chunk.Usage.TotalTokens
+				finalTokenUsage = *chunk.Usage
+			}
+			if chunk.BotUsage != nil {
+				botUsage = chunk.BotUsage
 			}
+			if refs := toReferences(chunk.References); refs != nil {
+				references = append(references, refs...)
+				if req.OnStreamChunk != nil {
+					if err := req.OnStreamChunk(&models.TextGenerationStreamChunk{ID: chunk.ID, References: refs}); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 
-		if err := scanner.Err(); err != nil {
-			// h.logger.Printf("Error reading Volcengine stream: %v", err)
-			return nil, fmt.Errorf("volcengine handler: error reading stream: %w", err)
+		sdkChoices := make([]models.Choice, 0, len(choices))
+		for index, acc := range choices {
+			sdkChoices = append(sdkChoices, acc.choice(index))
 		}
 
-		// If usage was specifically requested via stream_options but not found in a chunk,
-		// and the [DONE] message might have it. This part is tricky as [DONE] itself is not JSON.
-		// The Volcengine Go SDK example suggests usage comes in the last data event *before* [DONE]
-		// or sometimes within a chunk that also has delta content. The current loop structure should catch it if `include_usage` is true.
-
-		return &models.TextGenerationResponse{
-			ID:            finalResponseID,
-			GeneratedText: fullTextBuilder.String(),
-			FinishReason:  finalFinishReason,
-			TokenUsage: struct {
-				PromptTokens     int `json:"prompt_tokens"`
-				CompletionTokens int `json:"completion_tokens"`
-				TotalTokens      int `json:"total_tokens"`
-			}{
-				PromptTokens:     finalTokenUsage.PromptTokens,
-				CompletionTokens: finalTokenUsage.CompletionTokens,
-				TotalTokens:      finalTokenUsage.TotalTokens,
-			},
-		}, nil
-
-	} else {
-		// 处理非流式响应
-		respBodyBytes, err := io.ReadAll(httpResp.Body)
-		if err != nil {
-			// h.logger.Printf("Error reading Volcengine response body: %v", err)
-			return nil, fmt.Errorf("volcengine handler: failed to read response body: %w", err) // errors.Wrap(err, errors.ErrCodePlatformError, "volcengine handler: failed to read response body")
+		sdkResp := &models.TextGenerationResponse{ID: finalResponseID}
+		sdkResp.SetChoices(sdkChoices)
+		sdkResp.TokenUsage = finalTokenUsage.toUsage()
+		sdkResp.References = references
+		sdkResp.BotUsage = botUsage.toBotUsage()
+		if sdkResp.TokenUsage == (models.Usage{}) {
+			sdkResp.TokenUsage = botUsage.totalModelUsage()
 		}
-
-		var volcResp volcengineChatResponse
-		if err := json.Unmarshal(respBodyBytes, &volcResp); err != nil {
-			// h.logger.Printf("Error unmarshalling Volcengine response: %v. Body: %s", err, string(respBodyBytes))
-			return nil, fmt.Errorf("volcengine handler: failed to unmarshal response body: %w. Body: %s", err, string(respBodyBytes))
-			// errors.Wrap(err, errors.ErrCodePlatformError, fmt.Sprintf("volcengine handler: failed to unmarshal response body. Body: %s\", string(respBodyBytes)))
+		if req.OnStreamChunk != nil {
+			final := &models.TextGenerationStreamChunk{ID: finalResponseID, FinishReason: sdkResp.FinishReason, IsFinal: true}
+			if sdkResp.TokenUsage != (models.Usage{}) {
+				usage := sdkResp.TokenUsage
+				final.Usage = &usage
+			}
+			if err := req.OnStreamChunk(final); err != nil {
+				return nil, err
+			}
 		}
+		return sdkResp, nil
+	}
+
+	// 处理非流式响应
+	var volcResp volcengineChatResponse
+	if _, err := h.api.DoJSON(ctx, apiReq, &volcResp); err != nil {
+		return nil, err
+	}
+
+	return toTextGenerationResponse(&volcResp)
+}
+
+// toTextGenerationResponse 将非流式响应转换为 models.TextGenerationResponse，响应体中的错误会被转换为 SDK 错误。
+func toTextGenerationResponse(volcResp *volcengineChatResponse) (*models.TextGenerationResponse, error) {
+	if volcResp.Error != nil { // Check for API error in the non-stream response body
+		return nil, errors.Wrap(volcResp.Error, errors.ErrCodePlatformError, fmt.Sprintf("volcengine API error: code %s, message: %s", volcResp.Error.Code, volcResp.Error.Message))
+	}
+
+	if len(volcResp.Choices) == 0 {
+		return nil, errors.New(errors.ErrCodeInvalidResponse, "volcengine handler: no choices found in response")
+	}
+
+	sdkChoices := make([]models.Choice, 0, len(volcResp.Choices))
+	for _, choice := range volcResp.Choices {
+		sdkChoices = append(sdkChoices, models.Choice{
+			Index:           choice.Index,
+			Text:            choice.Message.Content,
+			Reasoning:       choice.Message.ReasoningContent,
+			FinishReason:    models.NormalizeFinishReason(choice.FinishReason),
+			RawFinishReason: choice.FinishReason,
+			Logprobs:        choice.Logprobs.tokens(),
+		})
+	}
+
+	sdkResp := &models.TextGenerationResponse{ID: volcResp.ID}
+	sdkResp.SetChoices(sdkChoices)
+	sdkResp.TokenUsage = volcResp.Usage.toUsage()
+	sdkResp.References = toReferences(volcResp.References)
+	sdkResp.BotUsage = volcResp.BotUsage.toBotUsage()
+	if sdkResp.TokenUsage == (models.Usage{}) {
+		sdkResp.TokenUsage = volcResp.BotUsage.totalModelUsage()
+	}
+	return sdkResp, nil
+}
+
+// streamChoiceAccumulator 累积流式响应中单个 choice 的增量内容。
+type streamChoiceAccumulator struct {
+	text         strings.Builder
+	reasoning    strings.Builder
+	logprobs     []models.TokenLogprob
+	finishReason string
+}
+
+func (a *streamChoiceAccumulator) add(choice volcengineStreamChoice) {
+	a.text.WriteString(choice.Delta.Content)
+	a.reasoning.WriteString(choice.Delta.ReasoningContent)
+	a.logprobs = append(a.logprobs, choice.Logprobs.tokens()...)
+	if choice.FinishReason != nil {
+		a.finishReason = *choice.FinishReason
+	}
+}
+
+func (a *streamChoiceAccumulator) choice(index int) models.Choice {
+	return models.Choice{
+		Index:           index,
+		Text:            a.text.String(),
+		Reasoning:       a.reasoning.String(),
+		FinishReason:    models.NormalizeFinishReason(a.finishReason),
+		RawFinishReason: a.finishReason,
+		Logprobs:        a.logprobs,
+	}
+}
+
+// buildChatRequest 将 models.TextGenerationRequest 转换为 volcengineChatRequest，并校验采样参数的取值范围。
+func buildChatRequest(req *models.TextGenerationRequest) (*volcengineChatRequest, error) {
+	if err := validateSamplingParams(req); err != nil {
+		return nil, err
+	}
 
-		if volcResp.Error != nil { // Check for API error in the non-stream response body
-			return nil, fmt.Errorf("volcengine API error: code %s, message: %s", volcResp.Error.Code, volcResp.Error.Message)
+	// 默认将 prompt 与多模态内容作为一条 user message
+	userMessage, err := toRequestMessage("user", req.Prompt, req.Parts)
+	if err != nil {
+		return nil, err
+	}
+
+	volcReq := &volcengineChatRequest{
+		Model:            req.Model,
+		Messages:         []volcengineRequestMessage{userMessage},
+		Stream:           req.Stream,
+		MaxTokens:        req.MaxTokens,
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		FrequencyPenalty: req.FrequencyPenalty,
+		PresencePenalty:  req.PresencePenalty,
+		Seed:             req.Seed,
+		LogitBias:        req.LogitBias,
+		N:                req.N,
+		Logprobs:         req.Logprobs,
+		TopLogprobs:      req.TopLogprobs,
+		Stop:             req.StopSequences,
+		ContextID:        req.ContextCacheID,
+		// User: 从 req.PlatformSpecificParams 获取,
+	}
+	switch req.Thinking {
+	case "":
+	case models.ThinkingEnabled, models.ThinkingDisabled, models.ThinkingAuto:
+		volcReq.Thinking = &volcengineThinking{Type: string(req.Thinking)}
+	default:
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: unsupported thinking mode %q", req.Thinking))
+	}
+	if rf := req.ResponseFormat; rf != nil {
+		switch rf.Type {
+		case models.ResponseFormatText, models.ResponseFormatJSONObject:
+		case models.ResponseFormatJSONSchema:
+			if rf.JSONSchema == nil || rf.JSONSchema.Name == "" || rf.JSONSchema.Schema == nil {
+				return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: json_schema response format requires a name and a schema")
+			}
+		default:
+			return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: unsupported response format %q", rf.Type))
 		}
+		volcReq.ResponseFormat = rf
+	}
 
-		if len(volcResp.Choices) == 0 {
-			// h.logger.Println("Volcengine response contained no choices.")
-			return nil, fmt.Errorf("volcengine handler: no choices found in response") // errors.New(errors.ErrCodePlatformError, "volcengine handler: no choices found in response")
+	// 流式请求默认要求平台在最后一个数据块中返回用量，以便填充 TokenUsage；
+	// PlatformSpecificParams 中的 volc_stream_options_include_usage 为 false 时不请求用量。
+	if req.Stream {
+		if includeUsage, ok := req.PlatformSpecificParams["volc_stream_options_include_usage"].(bool); !ok || includeUsage {
+			volcReq.StreamOptions = &volcengineStreamOptions{IncludeUsage: true}
 		}
+	}
 
-		choice := volcResp.Choices[0]
-
-		sdkResp := &models.TextGenerationResponse{
-			ID:            volcResp.ID,
-			GeneratedText: choice.Message.Content,
-			FinishReason:  choice.FinishReason,
-			TokenUsage: struct {
-				PromptTokens     int `json:"prompt_tokens"`
-				CompletionTokens int `json:"completion_tokens"`
-				TotalTokens      int `json:"total_tokens"`
-			}{
-				PromptTokens:     volcResp.Usage.PromptTokens,
-				CompletionTokens: volcResp.Usage.CompletionTokens,
-				TotalTokens:      volcResp.Usage.TotalTokens,
-			},
+	// TODO: 处理 req.PlatformSpecificParams 中更复杂的 messages 结构 (system, assistant roles)
+	// TODO: 处理 Tools (如果未来支持)
+
+	return volcReq, nil
+}
+
+// validateSamplingParams 按火山方舟文档校验采样参数的取值范围。
+func validateSamplingParams(req *models.TextGenerationRequest) error {
+	invalid := func(format string, args ...interface{}) error {
+		return errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: "+fmt.Sprintf(format, args...))
+	}
+	if t := req.Temperature; t != nil && (*t < 0 || *t > 2) {
+		return invalid("temperature must be in [0, 2], got %v", *t)
+	}
+	if p := req.TopP; p != nil && (*p < 0 || *p > 1) {
+		return invalid("top_p must be in [0, 1], got %v", *p)
+	}
+	if p := req.FrequencyPenalty; p != nil && (*p < -2 || *p > 2) {
+		return invalid("frequency_penalty must be in [-2, 2], got %v", *p)
+	}
+	if p := req.PresencePenalty; p != nil && (*p < -2 || *p > 2) {
+		return invalid("presence_penalty must be in [-2, 2], got %v", *p)
+	}
+	for token, bias := range req.LogitBias {
+		if bias < -100 || bias > 100 {
+			return invalid("logit_bias for token %s must be in [-100, 100], got %d", token, bias)
 		}
-		// h.logger.Printf("Successfully received and parsed TextGeneration response from Volcengine for ID: %s\", sdkResp.ID)
-		return sdkResp, nil
 	}
+	if req.N < 0 {
+		return invalid("n cannot be negative")
+	}
+	if req.TopLogprobs < 0 || req.TopLogprobs > 20 {
+		return invalid("top_logprobs must be in [0, 20], got %d", req.TopLogprobs)
+	}
+	if req.TopLogprobs > 0 && !req.Logprobs {
+		return invalid("top_logprobs requires logprobs to be enabled")
+	}
+	return nil
 }
@@ -0,0 +1,408 @@
+package volcengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+// newTestServer 启动 httptest 服务并返回其地址，服务在测试结束时关闭。
+func newTestServer(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// newTestHandler 创建请求发往 httptest 服务的处理器。
+func newTestHandler(t *testing.T, handler http.HandlerFunc, opts ...Option) *VolcengineHandler {
+	t.Helper()
+	h, err := NewHandler(testConfig(platform.ProviderSettings{BaseURL: newTestServer(t, handler)}), opts...)
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return h
+}
+
+func TestTextGenerationStreamMalformedChunk(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"id":"c1","choices":[{"index":0,"delta":{"content":"Hi"}}]}`+"\n\n")
+		fmt.Fprint(w, "data: {\"id\":\n\n")
+	})
+	_, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: "hi", Stream: true})
+	if !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodeInvalidResponse)
+	}
+}
+
+func TestTextGenerationStreamErrorEvent(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `data: {"error":{"code":"InternalServiceError","message":"oops"}}`+"\n\n")
+	})
+	_, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: "hi", Stream: true})
+	if !errors.IsSDKError(err, errors.ErrCodePlatformError) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodePlatformError)
+	}
+}
+
+func TestTextGenerationSendsModelAsString(t *testing.T) {
+	var body map[string]json.RawMessage
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`)
+	})
+	if _, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "ep-20240101-abc", Prompt: "hi"}); err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	// 方舟要求 model 为模型 ID 字符串，而不是 {"model_id": ...} 对象。
+	if got := string(body["model"]); got != `"ep-20240101-abc"` {
+		t.Errorf("model = %s, want a JSON string", got)
+	}
+}
+
+func TestTextGenerationResponseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"no choices", `{"id":"c1","choices":[]}`, errors.ErrCodeInvalidResponse},
+		{"malformed body", `{"id":`, errors.ErrCodeInvalidResponse},
+		{"error in body", `{"error":{"code":"InternalServiceError","message":"oops"}}`, errors.ErrCodePlatformError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.body)
+			})
+			_, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: "hi"})
+			if !errors.IsSDKError(err, tt.want) {
+				t.Errorf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildChatRequestThinking(t *testing.T) {
+	tests := []struct {
+		mode    models.ThinkingMode
+		want    string
+		wantErr bool
+	}{
+		{mode: "", want: ""},
+		{mode: models.ThinkingEnabled, want: "enabled"},
+		{mode: models.ThinkingDisabled, want: "disabled"},
+		{mode: models.ThinkingAuto, want: "auto"},
+		{mode: "on", wantErr: true},
+	}
+	for _, tt := range tests {
+		volcReq, err := buildChatRequest(&models.TextGenerationRequest{Model: "m", Prompt: "hi", Thinking: tt.mode})
+		if tt.wantErr {
+			if !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+				t.Errorf("thinking %q: err = %v, want %s", tt.mode, err, errors.ErrCodeInvalidRequest)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("thinking %q: %v", tt.mode, err)
+			continue
+		}
+		got := ""
+		if volcReq.Thinking != nil {
+			got = volcReq.Thinking.Type
+		}
+		if got != tt.want {
+			t.Errorf("thinking %q: type = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestTextGenerationReasoning(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"42","reasoning_content":"6 times 7"},"finish_reason":"stop"}],`+
+			`"usage":{"prompt_tokens":3,"completion_tokens":10,"total_tokens":13,"completion_tokens_details":{"reasoning_tokens":8}}}`)
+	})
+	resp, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: "6*7?", Thinking: models.ThinkingEnabled})
+	if err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	// 思考过程不包含在 GeneratedText 中，思考 Token 计入 CompletionTokens。
+	if resp.GeneratedText != "42" || resp.Reasoning != "6 times 7" {
+		t.Errorf("text = %q, reasoning = %q", resp.GeneratedText, resp.Reasoning)
+	}
+	if resp.TokenUsage.ReasoningTokens != 8 || resp.TokenUsage.CompletionTokens != 10 {
+		t.Errorf("usage = %+v", resp.TokenUsage)
+	}
+}
+
+func TestTextGenerationStreamReasoning(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		for _, data := range []string{
+			`{"id":"c1","choices":[{"index":0,"delta":{"reasoning_content":"6 times"}}]}`,
+			`{"id":"c1","choices":[{"index":0,"delta":{"reasoning_content":" 7"}}]}`,
+			`{"id":"c1","choices":[{"index":0,"delta":{"content":"42"},"finish_reason":"stop"}]}`,
+			`[DONE]`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+	})
+	var reasoning, text string
+	resp, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{
+		Model: "m", Prompt: "6*7?", Stream: true,
+		OnStreamChunk: func(chunk *models.TextGenerationStreamChunk) error {
+			reasoning += chunk.Reasoning
+			text += chunk.Delta
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	if reasoning != "6 times 7" || text != "42" {
+		t.Errorf("streamed reasoning = %q, text = %q", reasoning, text)
+	}
+	if resp.Reasoning != "6 times 7" || resp.GeneratedText != "42" {
+		t.Errorf("response reasoning = %q, text = %q", resp.Reasoning, resp.GeneratedText)
+	}
+}
+
+func TestValidateSamplingParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.TextGenerationRequest
+		wantErr string
+	}{
+		{name: "zero values are sent", req: models.TextGenerationRequest{Temperature: models.Ptr[float32](0), TopP: models.Ptr[float32](0), Seed: models.Ptr[int64](0)}},
+		{name: "upper bounds", req: models.TextGenerationRequest{Temperature: models.Ptr[float32](2), TopP: models.Ptr[float32](1), FrequencyPenalty: models.Ptr[float32](2), PresencePenalty: models.Ptr[float32](-2)}},
+		{name: "logprobs", req: models.TextGenerationRequest{Logprobs: true, TopLogprobs: 20, LogitBias: map[string]int{"1": -100, "2": 100}}},
+		{name: "temperature too high", req: models.TextGenerationRequest{Temperature: models.Ptr[float32](2.1)}, wantErr: "temperature"},
+		{name: "negative temperature", req: models.TextGenerationRequest{Temperature: models.Ptr[float32](-0.1)}, wantErr: "temperature"},
+		{name: "top_p too high", req: models.TextGenerationRequest{TopP: models.Ptr[float32](1.5)}, wantErr: "top_p"},
+		{name: "frequency penalty", req: models.TextGenerationRequest{FrequencyPenalty: models.Ptr[float32](-3)}, wantErr: "frequency_penalty"},
+		{name: "presence penalty", req: models.TextGenerationRequest{PresencePenalty: models.Ptr[float32](2.5)}, wantErr: "presence_penalty"},
+		{name: "logit bias", req: models.TextGenerationRequest{LogitBias: map[string]int{"1": 101}}, wantErr: "logit_bias"},
+		{name: "negative n", req: models.TextGenerationRequest{N: -1}, wantErr: "n cannot be negative"},
+		{name: "too many top logprobs", req: models.TextGenerationRequest{Logprobs: true, TopLogprobs: 21}, wantErr: "top_logprobs"},
+		{name: "top logprobs without logprobs", req: models.TextGenerationRequest{TopLogprobs: 2}, wantErr: "requires logprobs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.Model, req.Prompt = "m", "hi"
+			volcReq, err := buildChatRequest(&req)
+			if tt.wantErr != "" {
+				if !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %s mentioning %q", err, errors.ErrCodeInvalidRequest, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildChatRequest: %v", err)
+			}
+			// 可空参数设置为零值时也要发送，未设置时省略。
+			body, _ := json.Marshal(volcReq)
+			for field, set := range map[string]bool{
+				`"temperature":`: req.Temperature != nil,
+				`"top_p":`:       req.TopP != nil,
+				`"seed":`:        req.Seed != nil,
+			} {
+				if strings.Contains(string(body), field) != set {
+					t.Errorf("body %s: %s present = %v, want %v", body, field, !set, set)
+				}
+			}
+		})
+	}
+}
+
+func TestTextGenerationLogprobs(t *testing.T) {
+	const logprobs = `{"content":[{"token":"Hi","logprob":-0.1,"bytes":[72,105],"top_logprobs":[{"token":"Hi","logprob":-0.1},{"token":"Hey","logprob":-2.5}]}]}`
+	tests := []struct {
+		name    string
+		stream  bool
+		body    string
+		wantErr string
+	}{
+		{name: "response", body: `{"id":"c1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop","logprobs":` + logprobs + `}]}`},
+		{name: "stream", stream: true, body: `data: {"id":"c1","choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop","logprobs":` + logprobs + `}]}` + "\n\ndata: [DONE]\n\n"},
+		{name: "malformed logprobs", body: `{"id":"c1","choices":[{"index":0,"message":{"content":"Hi"},"logprobs":{"content":"oops"}}]}`, wantErr: errors.ErrCodeInvalidResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.body)
+			})
+			var streamed []models.TokenLogprob
+			req := &models.TextGenerationRequest{Model: "m", Prompt: "hi", Stream: tt.stream, Logprobs: true, TopLogprobs: 2}
+			if tt.stream {
+				req.OnStreamChunk = func(chunk *models.TextGenerationStreamChunk) error {
+					streamed = append(streamed, chunk.Logprobs...)
+					return nil
+				}
+			}
+			resp, err := h.TextGeneration(context.Background(), req)
+			if tt.wantErr != "" {
+				if !errors.IsSDKError(err, tt.wantErr) {
+					t.Errorf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TextGeneration: %v", err)
+			}
+			if len(resp.Logprobs) != 1 || resp.Logprobs[0].Token != "Hi" || resp.Logprobs[0].Logprob != -0.1 || len(resp.Logprobs[0].TopLogprobs) != 2 {
+				t.Errorf("Logprobs = %+v", resp.Logprobs)
+			}
+			if tt.stream && len(streamed) != 1 {
+				t.Errorf("streamed logprobs = %+v", streamed)
+			}
+		})
+	}
+}
+
+func TestTextGenerationMultipleChoices(t *testing.T) {
+	var body map[string]json.RawMessage
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		// 平台不保证 choices 的顺序。
+		fmt.Fprint(w, `{"id":"c1","choices":[`+
+			`{"index":1,"message":{"role":"assistant","content":"B"},"finish_reason":"length"},`+
+			`{"index":0,"message":{"role":"assistant","content":"A"},"finish_reason":"stop"}]}`)
+	})
+	resp, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{Model: "m", Prompt: "hi", N: 2})
+	if err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	if string(body["n"]) != "2" {
+		t.Errorf("n = %s, want 2", body["n"])
+	}
+	if len(resp.Choices) != 2 || resp.Choices[0].Text != "A" || resp.Choices[1].Text != "B" || resp.Choices[1].FinishReason != models.FinishReasonLength {
+		t.Errorf("Choices = %+v", resp.Choices)
+	}
+	if resp.GeneratedText != "A" || resp.FinishReason != models.FinishReasonStop {
+		t.Errorf("first choice not synced: text = %q, finish = %q", resp.GeneratedText, resp.FinishReason)
+	}
+}
+
+func TestTextGenerationStreamMultipleChoices(t *testing.T) {
+	h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		for _, data := range []string{
+			`{"id":"c1","choices":[{"index":1,"delta":{"content":"B1"}}]}`,
+			`{"id":"c1","choices":[{"index":0,"delta":{"content":"A1"}},{"index":1,"delta":{"content":"B2"},"finish_reason":"length"}]}`,
+			`{"id":"c1","choices":[{"index":0,"delta":{"content":"A2"},"finish_reason":"stop"}]}`,
+			`[DONE]`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+	})
+	streamed := make(map[int]string)
+	finals := 0
+	resp, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{
+		Model: "m", Prompt: "hi", N: 2, Stream: true,
+		OnStreamChunk: func(chunk *models.TextGenerationStreamChunk) error {
+			if chunk.IsFinal {
+				finals++
+				return nil
+			}
+			streamed[chunk.Index] += chunk.Delta
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("TextGeneration: %v", err)
+	}
+	if streamed[0] != "A1A2" || streamed[1] != "B1B2" || finals != 1 {
+		t.Errorf("streamed = %v, final chunks = %d", streamed, finals)
+	}
+	want := []models.Choice{
+		{Index: 0, Text: "A1A2", FinishReason: models.FinishReasonStop, RawFinishReason: "stop"},
+		{Index: 1, Text: "B1B2", FinishReason: models.FinishReasonLength, RawFinishReason: "length"},
+	}
+	if !reflect.DeepEqual(resp.Choices, want) {
+		t.Errorf("Choices = %+v, want %+v", resp.Choices, want)
+	}
+
+	// 回调返回错误时中止流式请求。
+	stop := errors.New(errors.ErrCodeInvalidRequest, "stop")
+	_, err = h.TextGeneration(context.Background(), &models.TextGenerationRequest{
+		Model: "m", Prompt: "hi", N: 2, Stream: true,
+		OnStreamChunk: func(*models.TextGenerationStreamChunk) error { return stop },
+	})
+	if err != stop {
+		t.Errorf("err = %v, want the callback error", err)
+	}
+}
+
+func TestTextGenerationStreamUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    map[string]interface{}
+		wantUsage bool
+	}{
+		{name: "default", wantUsage: true},
+		{name: "opt out", params: map[string]interface{}{"volc_stream_options_include_usage": false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					StreamOptions *volcengineStreamOptions `json:"stream_options"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				includeUsage := body.StreamOptions != nil && body.StreamOptions.IncludeUsage
+				if includeUsage != tt.wantUsage {
+					t.Errorf("stream_options.include_usage = %v, want %v", includeUsage, tt.wantUsage)
+				}
+				fmt.Fprint(w, `data: {"id":"c1","choices":[{"index":0,"delta":{"content":"Hi"}}]}`+"\n\n")
+				fmt.Fprint(w, `data: {"id":"c1","choices":[{"index":0,"delta":{},"finish_reason":"max_tokens"}]}`+"\n\n")
+				if includeUsage {
+					// 用量出现在 choices 为空的最后一个数据块中。
+					fmt.Fprint(w, `data: {"id":"c1","choices":[],"usage":{"prompt_tokens":4,"completion_tokens":1,"total_tokens":5,"prompt_tokens_details":{"cached_tokens":2}}}`+"\n\n")
+				}
+				fmt.Fprint(w, "data: [DONE]\n\n")
+			})
+			var final *models.TextGenerationStreamChunk
+			resp, err := h.TextGeneration(context.Background(), &models.TextGenerationRequest{
+				Model: "m", Prompt: "hi", Stream: true, PlatformSpecificParams: tt.params,
+				OnStreamChunk: func(chunk *models.TextGenerationStreamChunk) error {
+					if chunk.IsFinal {
+						final = chunk
+					} else if chunk.Usage != nil || chunk.FinishReason != "" {
+						t.Errorf("non-final chunk carries usage or finish reason: %+v", chunk)
+					}
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("TextGeneration: %v", err)
+			}
+			if final == nil {
+				t.Fatal("no final chunk")
+			}
+			if final.FinishReason != models.FinishReasonLength || resp.FinishReason != models.FinishReasonLength || resp.RawFinishReason != "max_tokens" {
+				t.Errorf("finish reason: chunk %q, response %q (raw %q)", final.FinishReason, resp.FinishReason, resp.RawFinishReason)
+			}
+			wantUsage := models.Usage{PromptTokens: 4, CompletionTokens: 1, TotalTokens: 5, CachedTokens: 2}
+			if !tt.wantUsage {
+				if final.Usage != nil || resp.TokenUsage != (models.Usage{}) {
+					t.Errorf("usage = %+v / %+v, want none", final.Usage, resp.TokenUsage)
+				}
+				return
+			}
+			if final.Usage == nil || *final.Usage != wantUsage || resp.TokenUsage != wantUsage {
+				t.Errorf("usage: chunk %+v, response %+v, want %+v", final.Usage, resp.TokenUsage, wantUsage)
+			}
+		})
+	}
+}
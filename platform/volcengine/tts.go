@@ -0,0 +1,195 @@
+package volcengine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/utils"
+)
+
+const (
+	volcengineTTSPath = "/api/v3/tts/unidirectional"
+	// DefaultTTSResourceID 是请求未指定 Model 时使用的语音合成资源 ID。
+	DefaultTTSResourceID = "seed-tts-1.0"
+	// DefaultTTSFormat 是请求未指定 Format 时的音频格式。
+	DefaultTTSFormat = models.AudioFormatMP3
+)
+
+// volcengineTTSRequest 是豆包语音 V3 单向流式语音合成接口的请求体。
+type volcengineTTSRequest struct {
+	User      volcengineSpeechUser `json:"user"`
+	ReqParams volcengineTTSParams  `json:"req_params"`
+}
+
+type volcengineSpeechUser struct {
+	UID string `json:"uid"`
+}
+
+type volcengineTTSParams struct {
+	Text        string                   `json:"text"`
+	Speaker     string                   `json:"speaker"`
+	AudioParams volcengineTTSAudioParams `json:"audio_params"`
+	Additions   string                   `json:"additions,omitempty"` // JSON 字符串形式的扩展参数
+}
+
+type volcengineTTSAudioParams struct {
+	Format     string `json:"format"`
+	SampleRate int    `json:"sample_rate,omitempty"`
+	SpeechRate int    `json:"speech_rate,omitempty"` // 语速，取值 [-50, 100]，0 为正常语速，100 为 2 倍速
+	Emotion    string `json:"emotion,omitempty"`
+}
+
+// volcengineTTSChunk 是语音合成响应中每一行 JSON 的结构，Data 为 base64 编码的音频片段。
+type volcengineTTSChunk struct {
+	volcengineSpeechStatus
+	Data *string `json:"data"`
+}
+
+// TextToSpeech 调用豆包语音的单向流式语音合成接口。接口总是分块返回音频，
+// 非流式请求会读取完整音频后再返回。
+func (h *VolcengineHandler) TextToSpeech(ctx context.Context, req *models.TTSRequest) (*models.TTSResponse, error) {
+	if !h.hasSpeechCredentials() {
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("volcengine handler: text to speech requires %s and %s in credentials", volcengineSpeechAppIDName, volcengineSpeechAccessKeyName))
+	}
+	if req.Text == "" || req.Voice == "" {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, "volcengine handler: text to speech requires text and voice")
+	}
+	speechRate, err := ttsSpeechRate(req.Speed)
+	if err != nil {
+		return nil, err
+	}
+	format := req.Format
+	if format == "" {
+		format = DefaultTTSFormat
+	}
+	resourceID := req.Model
+	if resourceID == "" {
+		resourceID = DefaultTTSResourceID
+	}
+
+	volcReq := volcengineTTSRequest{
+		User: volcengineSpeechUser{UID: "modelbridge"},
+		ReqParams: volcengineTTSParams{
+			Text:    req.Text,
+			Speaker: req.Voice,
+			AudioParams: volcengineTTSAudioParams{
+				Format:     string(format),
+				SampleRate: req.SampleRate,
+				SpeechRate: speechRate,
+				Emotion:    req.Emotion,
+			},
+		},
+	}
+	if len(req.PlatformSpecificParams) > 0 {
+		additions, err := json.Marshal(req.PlatformSpecificParams)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInvalidRequest, "volcengine handler: invalid platform specific params for text to speech")
+		}
+		volcReq.ReqParams.Additions = string(additions)
+	}
+
+	requestID := newSpeechRequestID()
+	body, meta, err := h.speechAPI.DoStream(ctx, &utils.Request{
+		Method: http.MethodPost,
+		URL:    h.speechURL + volcengineTTSPath,
+		Header: h.speechHeaders("X-Api-App-Id", resourceID, requestID),
+		Body:   volcReq,
+	}, "application/json")
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &ttsStreamReader{ctx: ctx, body: body, lines: bufio.NewReader(body), api: h.speechAPI, meta: meta}
+	resp := &models.TTSResponse{ID: requestID, Format: format, SampleRate: req.SampleRate}
+	if req.Stream {
+		resp.AudioStream = stream
+		return resp, nil
+	}
+	defer stream.Close()
+	if resp.Audio, err = io.ReadAll(stream); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ttsSpeechRate 将语速倍率转换为豆包语音的 speech_rate，支持 0.5 到 2 倍速。
+func ttsSpeechRate(speed float64) (int, error) {
+	if speed == 0 {
+		return 0, nil
+	}
+	if speed < 0.5 || speed > 2 {
+		return 0, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("volcengine handler: speed must be between 0.5 and 2, got %g", speed))
+	}
+	return int(math.Round((speed - 1) * 100)), nil
+}
+
+// ttsStreamReader 逐行解码语音合成响应，将其中的音频片段拼接为连续的音频流。
+type ttsStreamReader struct {
+	ctx   context.Context
+	body  io.ReadCloser
+	lines *bufio.Reader
+	api   *utils.APIClient
+	meta  *utils.ResponseMeta
+	buf   []byte // 当前音频片段中尚未读取的部分
+	err   error  // 后续 Read 返回的错误，合成完成时为 io.EOF
+}
+
+func (r *ttsStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.buf, r.err = r.next()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *ttsStreamReader) Close() error {
+	return r.body.Close()
+}
+
+// next 读取下一行响应并返回其中的音频片段，收到成功结束的状态码时返回 io.EOF。
+func (r *ttsStreamReader) next() ([]byte, error) {
+	line, err := r.lines.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, r.api.WrapStreamError(r.ctx, err, r.meta)
+	}
+	if err == io.EOF && len(bytes.TrimSpace(line)) == 0 {
+		return nil, errors.New(errors.ErrCodePlatformError, "volcengine text to speech: stream ended before synthesis finished")
+	}
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, nil
+	}
+
+	var chunk volcengineTTSChunk
+	if err := json.Unmarshal(line, &chunk); err != nil {
+		sdkErr := errors.Wrap(err, errors.ErrCodeInvalidResponse, "volcengine text to speech: failed to unmarshal stream chunk")
+		sdkErr.PlatformDetails = map[string]interface{}{"chunk": string(line)}
+		return nil, sdkErr
+	}
+	if err := chunk.err("text to speech"); err != nil {
+		return nil, err
+	}
+	var audio []byte
+	if chunk.Data != nil && *chunk.Data != "" {
+		if audio, err = base64.StdEncoding.DecodeString(*chunk.Data); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInvalidResponse, "volcengine text to speech: invalid base64 audio chunk")
+		}
+	}
+	if chunk.Code == volcengineSpeechSuccessCode {
+		return audio, io.EOF
+	}
+	return audio, nil
+}
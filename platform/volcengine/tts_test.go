@@ -0,0 +1,237 @@
+package volcengine
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hewenyu/modelbridge/errors"
+	"github.com/hewenyu/modelbridge/models"
+	"github.com/hewenyu/modelbridge/platform"
+)
+
+// newSpeechTestHandler 创建配置了豆包语音凭证、语音请求发往 httptest 服务的处理器。
+func newSpeechTestHandler(t *testing.T, handler http.HandlerFunc) *VolcengineHandler {
+	t.Helper()
+	config := testConfig(platform.ProviderSettings{})
+	config.Credentials[volcengineSpeechAppIDName] = "app-1"
+	config.Credentials[volcengineSpeechAccessKeyName] = "token-1"
+	h, err := NewHandler(config, WithSpeechEndpoint(newTestServer(t, handler)))
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	return h
+}
+
+// writeTTSLine 写入一行合成响应并立即发送。
+func writeTTSLine(w http.ResponseWriter, code int, audio string) {
+	line := map[string]interface{}{"code": code, "message": "msg"}
+	if audio != "" {
+		line["data"] = base64.StdEncoding.EncodeToString([]byte(audio))
+	}
+	data, _ := json.Marshal(line)
+	w.Write(append(data, '\n'))
+	w.(http.Flusher).Flush()
+}
+
+func TestTextToSpeechBuffered(t *testing.T) {
+	var body volcengineTTSRequest
+	h := newSpeechTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != volcengineTTSPath {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		for header, want := range map[string]string{"X-Api-App-Id": "app-1", "X-Api-Access-Key": "token-1", "X-Api-Resource-Id": DefaultTTSResourceID} {
+			if got := r.Header.Get(header); got != want {
+				t.Errorf("%s = %q, want %q", header, got, want)
+			}
+		}
+		if r.Header.Get("X-Api-Request-Id") == "" {
+			t.Error("missing X-Api-Request-Id")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		writeTTSLine(w, 0, "ab")
+		w.Write([]byte("\n")) // 空行被忽略
+		writeTTSLine(w, 0, "cd")
+		writeTTSLine(w, volcengineSpeechSuccessCode, "")
+		writeTTSLine(w, 0, "ignored") // 成功结束后的内容不再读取
+	})
+
+	resp, err := h.TextToSpeech(context.Background(), &models.TTSRequest{
+		Text:                   "你好",
+		Voice:                  "zh_female",
+		Speed:                  1.5,
+		PlatformSpecificParams: map[string]interface{}{"disable_markdown_filter": true},
+	})
+	if err != nil {
+		t.Fatalf("TextToSpeech: %v", err)
+	}
+	if string(resp.Audio) != "abcd" || resp.Format != DefaultTTSFormat || resp.AudioStream != nil {
+		t.Errorf("resp = %+v", resp)
+	}
+	params := body.ReqParams
+	if params.Text != "你好" || params.Speaker != "zh_female" || params.AudioParams.Format != string(DefaultTTSFormat) || params.AudioParams.SpeechRate != 50 {
+		t.Errorf("req_params = %+v", params)
+	}
+	if params.Additions != `{"disable_markdown_filter":true}` {
+		t.Errorf("additions = %q", params.Additions)
+	}
+}
+
+func TestTextToSpeechStream(t *testing.T) {
+	firstRead := make(chan struct{})
+	h := newSpeechTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		writeTTSLine(w, 0, "ab")
+		// 客户端读到第一个片段之后才发送其余内容，验证音频是边收边读的。
+		<-firstRead
+		writeTTSLine(w, 0, "cd")
+		writeTTSLine(w, volcengineSpeechSuccessCode, "ef")
+	})
+
+	resp, err := h.TextToSpeech(context.Background(), &models.TTSRequest{Text: "hi", Voice: "v", Stream: true, Format: models.AudioFormatPCM})
+	if err != nil {
+		t.Fatalf("TextToSpeech: %v", err)
+	}
+	defer resp.AudioStream.Close()
+	buf := make([]byte, 2)
+	if _, err := io.ReadFull(resp.AudioStream, buf); err != nil || string(buf) != "ab" {
+		t.Fatalf("first read = %q, %v", buf, err)
+	}
+	close(firstRead)
+	rest, err := io.ReadAll(iotest.OneByteReader(resp.AudioStream))
+	if err != nil || string(rest) != "cdef" {
+		t.Errorf("rest = %q, %v", rest, err)
+	}
+}
+
+func TestTextToSpeechStreamErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines func(w http.ResponseWriter)
+		want  string
+	}{
+		{
+			name: "error code mid-stream",
+			lines: func(w http.ResponseWriter) {
+				writeTTSLine(w, 0, "ab")
+				writeTTSLine(w, 45000001, "")
+			},
+			want: errors.ErrCodeInvalidRequest,
+		},
+		{
+			name: "server error code",
+			lines: func(w http.ResponseWriter) {
+				writeTTSLine(w, 0, "ab")
+				writeTTSLine(w, 55000000, "")
+			},
+			want: errors.ErrCodePlatformError,
+		},
+		{
+			name: "EOF before synthesis finished",
+			lines: func(w http.ResponseWriter) {
+				writeTTSLine(w, 0, "ab")
+			},
+			want: errors.ErrCodePlatformError,
+		},
+		{
+			name: "malformed line",
+			lines: func(w http.ResponseWriter) {
+				writeTTSLine(w, 0, "ab")
+				w.Write([]byte("{\"code\":\n"))
+			},
+			want: errors.ErrCodeInvalidResponse,
+		},
+		{
+			name: "invalid base64 audio",
+			lines: func(w http.ResponseWriter) {
+				writeTTSLine(w, 0, "ab")
+				w.Write([]byte(`{"code":0,"data":"!!"}` + "\n"))
+			},
+			want: errors.ErrCodeInvalidResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newSpeechTestHandler(t, func(w http.ResponseWriter, r *http.Request) { tt.lines(w) })
+
+			_, err := h.TextToSpeech(context.Background(), &models.TTSRequest{Text: "hi", Voice: "v"})
+			if !errors.IsSDKError(err, tt.want) {
+				t.Errorf("buffered: err = %v, want %s", err, tt.want)
+			}
+
+			// 流式读取时，出错之前的音频仍然可以读到。
+			resp, err := h.TextToSpeech(context.Background(), &models.TTSRequest{Text: "hi", Voice: "v", Stream: true})
+			if err != nil {
+				t.Fatalf("stream: %v", err)
+			}
+			defer resp.AudioStream.Close()
+			audio, err := io.ReadAll(resp.AudioStream)
+			if string(audio) != "ab" || !errors.IsSDKError(err, tt.want) {
+				t.Errorf("stream: audio = %q, err = %v, want %s", audio, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextToSpeechHTTPError(t *testing.T) {
+	h := newSpeechTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"code":45000010,"message":"invalid token"}`)
+	})
+	_, err := h.TextToSpeech(context.Background(), &models.TTSRequest{Text: "hi", Voice: "v", Stream: true})
+	if !errors.IsSDKError(err, errors.ErrCodeAuthentication) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodeAuthentication)
+	}
+	if code := err.(*errors.Error).PlatformDetails["code"]; code != 45000010 {
+		t.Errorf("code = %v", code)
+	}
+}
+
+func TestTextToSpeechRequestValidation(t *testing.T) {
+	h, err := NewHandler(testConfig(platform.ProviderSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.TextToSpeech(context.Background(), &models.TTSRequest{Text: "hi", Voice: "v"}); !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("without speech credentials: err = %v, want %s", err, errors.ErrCodeConfiguration)
+	}
+
+	h = newSpeechTestHandler(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("invalid requests must not reach the platform")
+	})
+	for _, req := range []*models.TTSRequest{{Voice: "v"}, {Text: "hi"}, {Text: "hi", Voice: "v", Speed: 3}} {
+		if _, err := h.TextToSpeech(context.Background(), req); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+			t.Errorf("%+v: err = %v, want %s", req, err, errors.ErrCodeInvalidRequest)
+		}
+	}
+}
+
+func TestTTSSpeechRate(t *testing.T) {
+	tests := []struct {
+		speed float64
+		want  int
+	}{
+		{0, 0}, // 未设置
+		{1, 0},
+		{0.5, -50},
+		{0.8, -20},
+		{1.25, 25},
+		{2, 100},
+	}
+	for _, tt := range tests {
+		if got, err := ttsSpeechRate(tt.speed); err != nil || got != tt.want {
+			t.Errorf("ttsSpeechRate(%g) = %d, %v, want %d", tt.speed, got, err, tt.want)
+		}
+	}
+	for _, speed := range []float64{-1, 0.49, 2.01} {
+		if _, err := ttsSpeechRate(speed); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+			t.Errorf("ttsSpeechRate(%g): err = %v, want %s", speed, err, errors.ErrCodeInvalidRequest)
+		}
+	}
+}
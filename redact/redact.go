@@ -0,0 +1,172 @@
+// redact/redact.go
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 内置规则的类别名称，同时用于占位符，例如 [EMAIL]、[EMAIL_1]。
+const (
+	CategoryEmail     = "EMAIL"
+	CategoryPhone     = "PHONE"
+	CategoryChineseID = "CN_ID"
+	CategoryBankCard  = "BANK_CARD"
+	CategoryAPIKey    = "API_KEY"
+)
+
+// Match 是文本中一处敏感内容的位置，Start 与 End 为字节偏移。
+type Match struct {
+	Category string
+	Start    int
+	End      int
+}
+
+// Redactor 识别文本中的敏感内容。返回的 Match 按 Start 升序排列且互不重叠。
+// 可以实现此接口接入自定义的识别逻辑 (例如外部 NER 服务)。
+type Redactor interface {
+	Find(s string) []Match
+}
+
+// Rule 是一条基于正则表达式的识别规则。
+type Rule struct {
+	Category string
+	Pattern  *regexp.Regexp
+	// Validate 对正则匹配到的文本做进一步校验 (例如校验位)，为 nil 时不校验。
+	Validate func(match string) bool
+}
+
+// Rules 是按顺序执行的规则列表，实现了 Redactor。多条规则匹配到重叠的内容时以靠前的规则为准。
+type Rules []Rule
+
+// Find 实现了 Redactor。
+func (rules Rules) Find(s string) []Match {
+	var matches []Match
+	for _, rule := range rules {
+		for _, loc := range rule.Pattern.FindAllStringIndex(s, -1) {
+			if rule.Validate != nil && !rule.Validate(s[loc[0]:loc[1]]) {
+				continue
+			}
+			if !overlaps(matches, loc[0], loc[1]) {
+				matches = append(matches, Match{Category: rule.Category, Start: loc[0], End: loc[1]})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+func overlaps(matches []Match, start, end int) bool {
+	for _, m := range matches {
+		if start < m.End && m.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// Custom 创建一条自定义规则，category 建议使用大写字母与下划线。
+func Custom(category string, pattern *regexp.Regexp) Rule {
+	return Rule{Category: category, Pattern: pattern}
+}
+
+var (
+	// Email 匹配电子邮箱地址。
+	Email = Rule{
+		Category: CategoryEmail,
+		Pattern:  regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+	// Phone 匹配中国大陆手机号 (可带 +86)、带区号的固定电话与 E.164 格式的国际号码。
+	Phone = Rule{
+		Category: CategoryPhone,
+		Pattern:  regexp.MustCompile(`(?:\+86[ \-]?|\b)1[3-9]\d{9}\b|\b0\d{2,3}-\d{7,8}\b|\+[1-9]\d{7,14}\b`),
+	}
+	// ChineseID 匹配 18 位居民身份证号码，并校验出生日期格式与校验位。
+	ChineseID = Rule{
+		Category: CategoryChineseID,
+		Pattern:  regexp.MustCompile(`\b[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]\b`),
+		Validate: validChineseID,
+	}
+	// BankCard 匹配 13 到 19 位、可用空格或短横线分组的银行卡号，并进行 Luhn 校验。
+	BankCard = Rule{
+		Category: CategoryBankCard,
+		Pattern:  regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+		Validate: validLuhn,
+	}
+	// APIKey 匹配常见格式的 API 密钥与访问令牌，例如 sk- 前缀的密钥、火山引擎与 AWS 的 AccessKey、Bearer 令牌与 JWT。
+	APIKey = Rule{
+		Category: CategoryAPIKey,
+		Pattern: regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{16,}|\bAKLT[A-Za-z0-9_\-]{20,}|\bAKIA[0-9A-Z]{16}\b|` +
+			`\bgh[pousr]_[A-Za-z0-9]{36,}|\beyJ[A-Za-z0-9_\-]{8,}\.[A-Za-z0-9_\-]{8,}\.[A-Za-z0-9_\-]{8,}|` +
+			`(?i:\bbearer\s+)[A-Za-z0-9._~+/\-]{16,}=*`),
+	}
+)
+
+// DefaultRules 返回内置规则。API 密钥与身份证号排在前面，避免被手机号或银行卡号规则截断。
+func DefaultRules() Rules {
+	return Rules{APIKey, Email, ChineseID, BankCard, Phone}
+}
+
+// Default 返回使用内置规则的 Redactor，自定义规则可以追加在后面：
+//
+//	append(redact.DefaultRules(), redact.Custom("EMPLOYEE_ID", regexp.MustCompile(`\bE\d{6}\b`)))
+func Default() Redactor {
+	return DefaultRules()
+}
+
+// String 将 s 中的敏感内容替换为 [类别] 形式的占位符，适用于日志等不需要还原的场景。
+func String(r Redactor, s string) string {
+	return replace(s, r.Find(s), func(m Match, _ string) string { return "[" + m.Category + "]" })
+}
+
+// replace 按 matches 依次替换 s 中的内容，matches 需按 Start 升序且互不重叠。
+func replace(s string, matches []Match, fn func(m Match, value string) string) string {
+	if len(matches) == 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	last := 0
+	for _, m := range matches {
+		if m.Start < last || m.End > len(s) || m.Start > m.End {
+			continue // 忽略自定义 Redactor 返回的非法位置
+		}
+		b.WriteString(s[last:m.Start])
+		b.WriteString(fn(m, s[m.Start:m.End]))
+		last = m.End
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// validChineseID 按 GB 11643 校验身份证号码的最后一位。
+func validChineseID(id string) bool {
+	weights := [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(id[i]-'0') * w
+	}
+	return "10X98765432"[sum%11] == strings.ToUpper(id[17:])[0]
+}
+
+// validLuhn 对去掉分隔符后的卡号进行 Luhn 校验。
+func validLuhn(card string) bool {
+	sum, n := 0, 0
+	for i := len(card) - 1; i >= 0; i-- {
+		c := card[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
@@ -0,0 +1,114 @@
+// redact/vault.go
+package redact
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Vault 保存占位符与原文的对应关系，用于在发送给模型前替换敏感内容，并在模型返回后还原。
+// 同一原文在同一个 Vault 中始终对应同一个占位符，例如 [EMAIL_1]。Vault 可以并发使用。
+type Vault struct {
+	mu       sync.Mutex
+	tokens   map[string]string // 原文 -> 占位符
+	values   map[string]string // 占位符 -> 原文
+	counters map[string]int
+	replacer *strings.Replacer
+}
+
+// NewVault 创建一个空的 Vault。
+func NewVault() *Vault {
+	return &Vault{
+		tokens:   make(map[string]string),
+		values:   make(map[string]string),
+		counters: make(map[string]int),
+	}
+}
+
+// Tokenize 将 s 中 r 识别出的敏感内容替换为可还原的占位符。
+func (v *Vault) Tokenize(r Redactor, s string) string {
+	matches := r.Find(s)
+	if len(matches) == 0 {
+		return s
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return replace(s, matches, func(m Match, value string) string {
+		if token, ok := v.tokens[value]; ok {
+			return token
+		}
+		v.counters[m.Category]++
+		token := fmt.Sprintf("[%s_%d]", m.Category, v.counters[m.Category])
+		v.tokens[value] = token
+		v.values[token] = value
+		v.replacer = nil
+		return token
+	})
+}
+
+// Len 返回 Vault 中保存的占位符数量。
+func (v *Vault) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.values)
+}
+
+// Restore 将 s 中的占位符还原为原文，未知的占位符保持不变。
+func (v *Vault) Restore(s string) string {
+	if s == "" {
+		return s
+	}
+	v.mu.Lock()
+	if v.replacer == nil {
+		pairs := make([]string, 0, len(v.values)*2)
+		for token, value := range v.values {
+			pairs = append(pairs, token, value)
+		}
+		v.replacer = strings.NewReplacer(pairs...)
+	}
+	replacer := v.replacer
+	v.mu.Unlock()
+	return replacer.Replace(s)
+}
+
+// hasTokenPrefix 判断 s 是否可能是某个占位符的开头。
+func (v *Vault) hasTokenPrefix(s string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for token := range v.values {
+		if len(s) < len(token) && strings.HasPrefix(token, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamRestorer 在流式输出中还原占位符。占位符可能被拆分到多个文本块中，
+// 因此可能构成占位符开头的末尾内容会暂存到下一次 Write 或 Flush 时输出。
+type StreamRestorer struct {
+	vault   *Vault
+	pending string
+}
+
+// NewStreamRestorer 创建使用 v 还原占位符的 StreamRestorer。每路文本流 (例如每个候选结果) 需要单独创建。
+func (v *Vault) NewStreamRestorer() *StreamRestorer {
+	return &StreamRestorer{vault: v}
+}
+
+// Write 追加一个文本块，返回已经可以输出的还原后文本。
+func (sr *StreamRestorer) Write(delta string) string {
+	s := sr.pending + delta
+	sr.pending = ""
+	if i := strings.LastIndexByte(s, '['); i >= 0 && sr.vault.hasTokenPrefix(s[i:]) {
+		s, sr.pending = s[:i], s[i:]
+	}
+	return sr.vault.Restore(s)
+}
+
+// Flush 返回暂存的剩余文本，在文本流结束时调用。
+func (sr *StreamRestorer) Flush() string {
+	s := sr.pending
+	sr.pending = ""
+	return sr.vault.Restore(s)
+}
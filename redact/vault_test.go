@@ -0,0 +1,67 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVault(t *testing.T) {
+	v := NewVault()
+	in := "mail a@example.com and b@example.com, again a@example.com, phone 13812345678"
+	got := v.Tokenize(Default(), in)
+	want := "mail [EMAIL_1] and [EMAIL_2], again [EMAIL_1], phone [PHONE_1]"
+	if got != want {
+		t.Fatalf("Tokenize = %q, want %q", got, want)
+	}
+	if v.Len() != 3 {
+		t.Errorf("Len = %d", v.Len())
+	}
+	// 同一 Vault 中的后续文本复用已有的占位符并继续编号。
+	if got := v.Tokenize(Default(), "b@example.com c@example.com"); got != "[EMAIL_2] [EMAIL_3]" {
+		t.Errorf("second Tokenize = %q", got)
+	}
+	if got := v.Restore("reply to [EMAIL_1] and [EMAIL_3] or [EMAIL_9]"); got != "reply to a@example.com and c@example.com or [EMAIL_9]" {
+		t.Errorf("Restore = %q", got)
+	}
+	if got := v.Restore(want); got != in {
+		t.Errorf("round trip = %q", got)
+	}
+}
+
+func TestStreamRestorer(t *testing.T) {
+	v := NewVault()
+	v.Tokenize(Default(), "a@example.com 13812345678")
+	full := "write to [EMAIL_1] [not a token] or call [PHONE_1]. [EMAIL_1]"
+	want := "write to a@example.com [not a token] or call 13812345678. a@example.com"
+
+	// 在每一个位置切分 (以及逐字节输出)，结果都应与整体还原一致。
+	for i := 0; i <= len(full); i++ {
+		sr := v.NewStreamRestorer()
+		got := sr.Write(full[:i]) + sr.Write(full[i:]) + sr.Flush()
+		if got != want {
+			t.Errorf("split at %d: %q", i, got)
+		}
+	}
+	sr := v.NewStreamRestorer()
+	var b strings.Builder
+	for i := range full {
+		out := sr.Write(full[i : i+1])
+		if strings.Contains(out, "[EMAIL") || strings.Contains(out, "[PHONE") {
+			t.Fatalf("partial placeholder emitted: %q", out)
+		}
+		b.WriteString(out)
+	}
+	b.WriteString(sr.Flush())
+	if b.String() != want {
+		t.Errorf("byte by byte = %q", b.String())
+	}
+
+	// 流结束时不完整的占位符原样输出。
+	sr = v.NewStreamRestorer()
+	if got := sr.Write("tail [EMA"); got != "tail " {
+		t.Errorf("Write = %q, want the partial placeholder held back", got)
+	}
+	if got := sr.Flush(); got != "[EMA" {
+		t.Errorf("Flush = %q", got)
+	}
+}
@@ -0,0 +1,133 @@
+// tracing/recorder.go
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TraceParentHeader 是 W3C Trace Context 的请求头名称。
+const TraceParentHeader = "traceparent"
+
+// RecordedSpan 是 Recorder 记录的一个已结束的 Span。
+type RecordedSpan struct {
+	Name         string
+	Kind         SpanKind
+	TraceID      string // 32 位十六进制
+	SpanID       string // 16 位十六进制
+	ParentSpanID string // 根 Span 为空
+	Attributes   map[string]interface{}
+	Err          error
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+// Recorder 是在内存中记录 Span 的 Tracer，按 W3C Trace Context 格式生成 ID 并传播 traceparent，
+// 适用于测试与调试，不会导出到任何后端。Recorder 可以并发使用。
+type Recorder struct {
+	mu    sync.Mutex
+	spans []RecordedSpan
+}
+
+// NewRecorder 创建一个空的 Recorder。
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+var _ Tracer = (*Recorder)(nil)
+
+type recorderSpanKey struct{}
+
+// recorderSpan 是 Recorder 创建的 Span。
+type recorderSpan struct {
+	recorder *Recorder
+	mu       sync.Mutex
+	data     RecordedSpan
+	ended    bool
+}
+
+// Start 实现了 Tracer。
+func (r *Recorder) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span) {
+	span := &recorderSpan{
+		recorder: r,
+		data: RecordedSpan{
+			Name:       name,
+			Kind:       kind,
+			SpanID:     randomHex(8),
+			Attributes: make(map[string]interface{}, len(attrs)),
+			StartTime:  time.Now(),
+		},
+	}
+	if parent, ok := ctx.Value(recorderSpanKey{}).(*recorderSpan); ok {
+		span.data.TraceID = parent.data.TraceID
+		span.data.ParentSpanID = parent.data.SpanID
+	} else {
+		span.data.TraceID = randomHex(16)
+	}
+	span.SetAttributes(attrs...)
+	return context.WithValue(ctx, recorderSpanKey{}, span), span
+}
+
+// Inject 实现了 Tracer，ctx 中有 Recorder 创建的 Span 时写入 traceparent 请求头。
+func (r *Recorder) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(recorderSpanKey{}).(*recorderSpan); ok {
+		header.Set(TraceParentHeader, fmt.Sprintf("00-%s-%s-01", span.data.TraceID, span.data.SpanID))
+	}
+}
+
+// Spans 返回已结束的 Span，按结束顺序排列。
+func (r *Recorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RecordedSpan, len(r.spans))
+	copy(out, r.spans)
+	return out
+}
+
+// Reset 清空已记录的 Span。
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.spans = nil
+	r.mu.Unlock()
+}
+
+func (s *recorderSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range attrs {
+		s.data.Attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recorderSpan) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.Err = err
+	s.data.EndTime = time.Now()
+	attrs := make(map[string]interface{}, len(s.data.Attributes))
+	for k, v := range s.data.Attributes {
+		attrs[k] = v
+	}
+	data := s.data
+	data.Attributes = attrs
+	s.mu.Unlock()
+
+	s.recorder.mu.Lock()
+	s.recorder.spans = append(s.recorder.spans, data)
+	s.recorder.mu.Unlock()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,76 @@
+// tracing/tracing.go
+package tracing
+
+import (
+	"context"
+	"net/http"
+)
+
+// GenAI 语义约定 (OpenTelemetry Semantic Conventions for GenAI) 中使用的属性名。
+const (
+	AttrGenAISystem               = "gen_ai.system"
+	AttrGenAIOperationName        = "gen_ai.operation.name"
+	AttrGenAIRequestModel         = "gen_ai.request.model"
+	AttrGenAIRequestMaxTokens     = "gen_ai.request.max_tokens"
+	AttrGenAIRequestTemperature   = "gen_ai.request.temperature"
+	AttrGenAIRequestTopP          = "gen_ai.request.top_p"
+	AttrGenAIResponseID           = "gen_ai.response.id"
+	AttrGenAIResponseFinishReason = "gen_ai.response.finish_reasons"
+	AttrGenAIUsageInputTokens     = "gen_ai.usage.input_tokens"
+	AttrGenAIUsageOutputTokens    = "gen_ai.usage.output_tokens"
+
+	AttrErrorType          = "error.type"
+	AttrHTTPRequestMethod  = "http.request.method"
+	AttrHTTPResponseStatus = "http.response.status_code"
+	AttrURLFull            = "url.full"
+
+	// AttrRequestID 是平台返回的请求 ID，AttrAttempt 是重试中的第几次尝试 (从 1 开始)，不属于语义约定。
+	AttrRequestID = "modelbridge.request_id"
+	AttrAttempt   = "modelbridge.attempt"
+)
+
+// SpanKind 是 Span 的类型，取值与 OpenTelemetry 的 SpanKind 对应。
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota // 进程内的步骤，例如一次重试尝试
+	SpanKindClient                   // 对外部服务的调用，例如一次模型调用或 HTTP 请求
+)
+
+// Attribute 是 Span 上的一个属性。Value 为 string、bool、int、int64、float64 或 []string。
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String 创建字符串属性。
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int 创建整数属性。
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: value} }
+
+// Float64 创建浮点数属性。
+func Float64(key string, value float64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Bool 创建布尔属性。
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// StringSlice 创建字符串数组属性。
+func StringSlice(key string, value []string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Span 是一次正在进行的操作。
+type Span interface {
+	// SetAttributes 设置属性，同名属性会被覆盖。
+	SetAttributes(attrs ...Attribute)
+	// End 结束 Span，err 不为 nil 时将 Span 标记为失败并记录错误。End 只会被调用一次。
+	End(err error)
+}
+
+// Tracer 创建 Span 并在请求头中传播追踪上下文。SDK 不依赖任何追踪库，
+// 使用 OpenTelemetry 时可以用几行代码将其 Tracer 与 TextMapPropagator 适配为此接口。
+type Tracer interface {
+	// Start 创建一个 Span，父 Span 从 ctx 中获取；返回的 ctx 中包含新的 Span。
+	Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span)
+	// Inject 将 ctx 中的追踪上下文写入发往平台的请求头，例如 W3C traceparent。
+	Inject(ctx context.Context, header http.Header)
+}
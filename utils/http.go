@@ -0,0 +1,313 @@
+// utils/http.go
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+// StreamDoneMarker 是 OpenAI 兼容平台在 SSE 流结束时发送的 data 内容。
+const StreamDoneMarker = "[DONE]"
+
+// DefaultRequestIDHeaders 是默认用于提取平台请求 ID 的响应头，按顺序取第一个非空值。
+var DefaultRequestIDHeaders = []string{"X-Request-Id", "X-Client-Request-Id", "X-Tt-Logid"}
+
+// ErrorDecoder 从非 2xx 响应体中解析平台特定的错误信息。
+// 返回 nil 表示无法识别，此时使用通用的 HTTP 状态码错误。
+// 返回的 *errors.Error 若未设置 Code，会按 HTTP 状态码补全。
+type ErrorDecoder func(statusCode int, body []byte) *errors.Error
+
+// Hooks 定义了请求生命周期中的回调，可用于日志记录或指标采集。所有字段均可为 nil。
+type Hooks struct {
+	// BeforeRequest 在请求发送前调用。
+	BeforeRequest func(ctx context.Context, req *http.Request)
+	// AfterResponse 在收到响应头 (或请求失败) 后调用，meta 可能为 nil。
+	AfterResponse func(ctx context.Context, req *http.Request, meta *ResponseMeta, err error)
+}
+
+// Request 描述了一次 API 调用。
+type Request struct {
+	Method string
+	URL    string
+	Header map[string]string // 附加的请求头，例如认证信息
+	Body   interface{}       // 请求体，会被序列化为 JSON；为 nil 时不发送请求体
+}
+
+// ResponseMeta 包含响应的元信息。
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	RequestID  string        // 平台返回的请求 ID，用于排查问题
+	Latency    time.Duration // 从发送请求到收到响应头的耗时
+}
+
+// APIClient 封装了各平台共用的 JSON 请求/响应、SSE 流解码与错误转换流程，
+// 平台处理器只需负责请求与响应结构的映射。
+type APIClient struct {
+	// HTTPClient 用于发送请求，为 nil 时使用 DefaultHTTPClient。
+	HTTPClient HTTPClient
+	// Platform 是平台名称，用于错误信息前缀，例如 "volcengine"。
+	Platform string
+	// DecodeError 解析平台特定的错误响应，可为 nil。
+	DecodeError ErrorDecoder
+	// RequestIDHeaders 指定提取请求 ID 的响应头，为空时使用 DefaultRequestIDHeaders。
+	RequestIDHeaders []string
+	// Hooks 是可选的生命周期回调。
+	Hooks Hooks
+}
+
+// DoJSON 发送 JSON 请求，并将 2xx 响应体解码到 out (out 为 nil 时丢弃响应体)。
+// 非 2xx 响应与网络错误都会被转换为 *errors.Error。
+func (c *APIClient) DoJSON(ctx context.Context, req *Request, out interface{}) (*ResponseMeta, error) {
+	httpResp, meta, err := c.send(ctx, req, "application/json")
+	if err != nil {
+		return meta, err
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return meta, c.wrapTransportError(ctx, err, "failed to read response body", meta)
+	}
+	if out == nil || len(body) == 0 {
+		return meta, nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		sdkErr := errors.Wrap(err, errors.ErrCodeInvalidResponse, fmt.Sprintf("%s: failed to unmarshal response body", c.platformName()))
+		sdkErr.PlatformDetails = c.details(meta, map[string]interface{}{"response_body": string(body)})
+		return meta, sdkErr
+	}
+	return meta, nil
+}
+
+// DoSSE 发送 JSON 请求并以 Server-Sent Events 解码响应，对每个事件调用 onEvent。
+// 收到 data 为 StreamDoneMarker 的事件时返回；在此之前流就结束 (例如连接被代理或服务端截断) 时返回 ErrCodeInvalidResponse 错误，
+// 避免不完整的回答被当作成功。onEvent 返回错误时立即中止并返回该错误。
+func (c *APIClient) DoSSE(ctx context.Context, req *Request, onEvent func(event *SSEEvent) error) (*ResponseMeta, error) {
+	httpResp, meta, err := c.send(ctx, req, "text/event-stream")
+	if err != nil {
+		return meta, err
+	}
+	defer httpResp.Body.Close()
+
+	decoder := NewSSEDecoder(httpResp.Body)
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
+			sdkErr := errors.New(errors.ErrCodeInvalidResponse, fmt.Sprintf("%s: stream ended before %s", c.platformName(), StreamDoneMarker))
+			sdkErr.PlatformDetails = c.details(meta, nil)
+			return meta, sdkErr
+		}
+		if err != nil {
+			return meta, c.wrapTransportError(ctx, err, "error reading stream", meta)
+		}
+		if event.Data == StreamDoneMarker {
+			return meta, nil
+		}
+		if err := onEvent(event); err != nil {
+			return meta, err
+		}
+	}
+}
+
+// DoStream 发送 JSON 请求并返回 2xx 响应的响应体，供平台处理器按自身格式逐段解码，
+// 例如分块返回的音频数据。调用方负责关闭返回的响应体。
+func (c *APIClient) DoStream(ctx context.Context, req *Request, accept string) (io.ReadCloser, *ResponseMeta, error) {
+	httpResp, meta, err := c.send(ctx, req, accept)
+	if err != nil {
+		return nil, meta, err
+	}
+	return httpResp.Body, meta, nil
+}
+
+// WrapStreamError 将读取 DoStream 响应体时遇到的错误转换为 *errors.Error，区分取消与超时。
+func (c *APIClient) WrapStreamError(ctx context.Context, err error, meta *ResponseMeta) *errors.Error {
+	return c.wrapTransportError(ctx, err, "error reading stream", meta)
+}
+
+// send 构造并发送 HTTP 请求，返回 2xx 响应；其他情况返回转换后的错误。
+func (c *APIClient) send(ctx context.Context, req *Request, accept string) (*http.Response, *ResponseMeta, error) {
+	var body io.Reader
+	if req.Body != nil {
+		reqBodyBytes, err := json.Marshal(req.Body)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, fmt.Sprintf("%s: failed to marshal request body", c.platformName()))
+		}
+		body = bytes.NewReader(reqBodyBytes)
+	}
+
+	method := req.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, req.URL, body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, fmt.Sprintf("%s: failed to create HTTP request", c.platformName()))
+	}
+	for k, v := range req.Header {
+		httpReq.Header.Set(k, v)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", accept)
+
+	if c.Hooks.BeforeRequest != nil {
+		c.Hooks.BeforeRequest(ctx, httpReq)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = DefaultHTTPClient
+	}
+	start := time.Now()
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		sdkErr := c.wrapTransportError(ctx, err, "failed to send HTTP request", nil)
+		if c.Hooks.AfterResponse != nil {
+			c.Hooks.AfterResponse(ctx, httpReq, nil, sdkErr)
+		}
+		return nil, nil, sdkErr
+	}
+
+	meta := &ResponseMeta{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		RequestID:  c.requestID(httpResp.Header),
+		Latency:    time.Since(start),
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		respBodyBytes, _ := io.ReadAll(httpResp.Body) // Try to read body for error details
+		httpResp.Body.Close()
+		sdkErr := c.statusError(meta, respBodyBytes)
+		if c.Hooks.AfterResponse != nil {
+			c.Hooks.AfterResponse(ctx, httpReq, meta, sdkErr)
+		}
+		return nil, meta, sdkErr
+	}
+
+	if c.Hooks.AfterResponse != nil {
+		c.Hooks.AfterResponse(ctx, httpReq, meta, nil)
+	}
+	return httpResp, meta, nil
+}
+
+// statusError 将非 2xx 响应转换为 *errors.Error。
+func (c *APIClient) statusError(meta *ResponseMeta, body []byte) *errors.Error {
+	var sdkErr *errors.Error
+	if c.DecodeError != nil {
+		sdkErr = c.DecodeError(meta.StatusCode, body)
+	}
+	if sdkErr == nil {
+		sdkErr = errors.New(HTTPStatusErrorCode(meta.StatusCode), fmt.Sprintf("%s API error: status code %d, response: %s", c.platformName(), meta.StatusCode, string(body)))
+		sdkErr.PlatformDetails = map[string]interface{}{"response_body": string(body)}
+	}
+	if sdkErr.Code == "" {
+		sdkErr.Code = HTTPStatusErrorCode(meta.StatusCode)
+	}
+	sdkErr.PlatformDetails = c.details(meta, sdkErr.PlatformDetails)
+	return sdkErr
+}
+
+// wrapTransportError 将网络层错误转换为 *errors.Error，区分取消与超时。
+func (c *APIClient) wrapTransportError(ctx context.Context, err error, message string, meta *ResponseMeta) *errors.Error {
+	code := errors.ErrCodePlatformError
+	var netErr net.Error
+	switch {
+	case stderrors.Is(err, context.Canceled) || ctx.Err() == context.Canceled:
+		code = errors.ErrCodeCancelled
+	case stderrors.Is(err, context.DeadlineExceeded) || ctx.Err() == context.DeadlineExceeded:
+		code = errors.ErrCodeTimeout
+	case stderrors.As(err, &netErr) && netErr.Timeout():
+		code = errors.ErrCodeTimeout
+	}
+	sdkErr := errors.Wrap(err, code, fmt.Sprintf("%s: %s", c.platformName(), message))
+	if meta != nil {
+		sdkErr.PlatformDetails = c.details(meta, nil)
+	}
+	return sdkErr
+}
+
+// details 在 extra 的基础上补充状态码与请求 ID。
+func (c *APIClient) details(meta *ResponseMeta, extra map[string]interface{}) map[string]interface{} {
+	if extra == nil {
+		extra = make(map[string]interface{})
+	}
+	if meta != nil {
+		extra["status_code"] = meta.StatusCode
+		if meta.RequestID != "" {
+			extra["request_id"] = meta.RequestID
+		}
+	}
+	return extra
+}
+
+func (c *APIClient) requestID(header http.Header) string {
+	names := c.RequestIDHeaders
+	if len(names) == 0 {
+		names = DefaultRequestIDHeaders
+	}
+	for _, name := range names {
+		if v := header.Get(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (c *APIClient) platformName() string {
+	if c.Platform == "" {
+		return "platform"
+	}
+	return c.Platform
+}
+
+// HTTPStatusErrorCode 将 HTTP 状态码映射为 SDK 错误代码。
+func HTTPStatusErrorCode(statusCode int) string {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return errors.ErrCodeAuthentication
+	case statusCode == http.StatusNotFound:
+		return errors.ErrCodeNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return errors.ErrCodeRateLimited
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusGatewayTimeout:
+		return errors.ErrCodeTimeout
+	case statusCode >= 400 && statusCode < 500:
+		return errors.ErrCodeInvalidRequest
+	default:
+		return errors.ErrCodePlatformError
+	}
+}
+
+// MakeHTTPRequest 是一个辅助函数，用于创建和执行一次性的 JSON HTTP 请求。
+// method: HTTP 方法 (GET, POST, etc.)
+// url: 请求的 URL
+// headers: 请求头 (可以为 nil)
+// requestBody: 请求体 (可以为 nil, 对于 GET 请求通常为 nil)
+// responseBody: 用于 unmarshal 响应体的目标结构体指针 (如果不需要解析响应体，可以为 nil)
+// httpClient: 用于执行请求的 HTTPClient (如果为 nil, 使用 DefaultHTTPClient)
+// 需要平台特定的错误解析或日志回调时，请直接使用 APIClient。
+func MakeHTTPRequest(
+	ctx context.Context,
+	method string,
+	url string,
+	headers map[string]string,
+	requestBody interface{},
+	responseBody interface{},
+	httpClient HTTPClient,
+) error {
+	c := &APIClient{HTTPClient: httpClient}
+	_, err := c.DoJSON(ctx, &Request{Method: method, URL: url, Header: headers, Body: requestBody}, responseBody)
+	return err
+}
@@ -0,0 +1,311 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+// hookRecorder 记录 Hooks 的调用，用于检查 BeforeRequest 与 AfterResponse 是否成对出现。
+type hookRecorder struct {
+	mu     sync.Mutex
+	before []*http.Request
+	after  []*http.Request
+	metas  []*ResponseMeta
+	errs   []error
+}
+
+func (r *hookRecorder) hooks() Hooks {
+	return Hooks{
+		BeforeRequest: func(ctx context.Context, req *http.Request) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.before = append(r.before, req)
+		},
+		AfterResponse: func(ctx context.Context, req *http.Request, meta *ResponseMeta, err error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.after = append(r.after, req)
+			r.metas = append(r.metas, meta)
+			r.errs = append(r.errs, err)
+		},
+	}
+}
+
+func (r *hookRecorder) assertPaired(t *testing.T) {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.before) != 1 || len(r.after) != 1 {
+		t.Fatalf("hooks called %d/%d times, want 1/1", len(r.before), len(r.after))
+	}
+	if r.before[0] != r.after[0] {
+		t.Error("AfterResponse received a different request than BeforeRequest")
+	}
+}
+
+func TestDoJSONSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("Accept") != "application/json" {
+			t.Errorf("unexpected request: %s %v", r.Method, r.Header)
+		}
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["q"] != "hi" {
+			t.Errorf("body = %v, %v", body, err)
+		}
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte(`{"answer":"ok"}`))
+	}))
+	defer server.Close()
+
+	rec := &hookRecorder{}
+	c := &APIClient{Platform: "test", Hooks: rec.hooks()}
+	var out struct{ Answer string }
+	meta, err := c.DoJSON(context.Background(), &Request{
+		URL:    server.URL,
+		Header: map[string]string{"Authorization": "Bearer key"},
+		Body:   map[string]string{"q": "hi"},
+	}, &out)
+	if err != nil {
+		t.Fatalf("DoJSON: %v", err)
+	}
+	if out.Answer != "ok" {
+		t.Errorf("Answer = %q", out.Answer)
+	}
+	if meta.StatusCode != http.StatusOK || meta.RequestID != "req-1" {
+		t.Errorf("meta = %+v", meta)
+	}
+	rec.assertPaired(t)
+	if rec.errs[0] != nil || rec.metas[0] != meta {
+		t.Errorf("AfterResponse got meta=%v err=%v", rec.metas[0], rec.errs[0])
+	}
+}
+
+func TestDoJSONStatusErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{http.StatusBadRequest, errors.ErrCodeInvalidRequest},
+		{http.StatusUnauthorized, errors.ErrCodeAuthentication},
+		{http.StatusForbidden, errors.ErrCodeAuthentication},
+		{http.StatusNotFound, errors.ErrCodeNotFound},
+		{http.StatusRequestTimeout, errors.ErrCodeTimeout},
+		{http.StatusTooManyRequests, errors.ErrCodeRateLimited},
+		{http.StatusInternalServerError, errors.ErrCodePlatformError},
+		{http.StatusBadGateway, errors.ErrCodePlatformError},
+		{http.StatusGatewayTimeout, errors.ErrCodeTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Tt-Logid", "log-1")
+				w.WriteHeader(tt.status)
+				w.Write([]byte("boom"))
+			}))
+			defer server.Close()
+
+			rec := &hookRecorder{}
+			c := &APIClient{Platform: "test", Hooks: rec.hooks()}
+			meta, err := c.DoJSON(context.Background(), &Request{URL: server.URL}, nil)
+			if !errors.IsSDKError(err, tt.want) {
+				t.Fatalf("err = %v, want %s", err, tt.want)
+			}
+			sdkErr := err.(*errors.Error)
+			if sdkErr.PlatformDetails["status_code"] != tt.status || sdkErr.PlatformDetails["request_id"] != "log-1" {
+				t.Errorf("PlatformDetails = %v", sdkErr.PlatformDetails)
+			}
+			if sdkErr.PlatformDetails["response_body"] != "boom" {
+				t.Errorf("response_body = %v", sdkErr.PlatformDetails["response_body"])
+			}
+			if meta == nil || meta.RequestID != "log-1" {
+				t.Errorf("meta = %+v", meta)
+			}
+			rec.assertPaired(t)
+			if rec.errs[0] != err {
+				t.Errorf("AfterResponse err = %v, want %v", rec.errs[0], err)
+			}
+		})
+	}
+}
+
+func TestDoJSONDecodeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"code":"QuotaExceeded","message":"slow down"}}`))
+	}))
+	defer server.Close()
+
+	var gotStatus int
+	c := &APIClient{
+		Platform: "test",
+		DecodeError: func(statusCode int, body []byte) *errors.Error {
+			gotStatus = statusCode
+			var payload struct {
+				Error struct{ Code, Message string }
+			}
+			if json.Unmarshal(body, &payload) != nil || payload.Error.Code == "" {
+				return nil
+			}
+			// 未设置 Code，由 APIClient 按状态码补全。
+			return &errors.Error{Message: payload.Error.Message, PlatformDetails: map[string]interface{}{"platform_code": payload.Error.Code}}
+		},
+	}
+	_, err := c.DoJSON(context.Background(), &Request{URL: server.URL}, nil)
+	if !errors.IsSDKError(err, errors.ErrCodeRateLimited) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodeRateLimited)
+	}
+	details := err.(*errors.Error).PlatformDetails
+	if gotStatus != http.StatusTooManyRequests || details["platform_code"] != "QuotaExceeded" || details["status_code"] != http.StatusTooManyRequests {
+		t.Errorf("status=%d details=%v", gotStatus, details)
+	}
+}
+
+func TestDoJSONMalformedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"answer":`))
+	}))
+	defer server.Close()
+
+	var out struct{ Answer string }
+	_, err := (&APIClient{}).DoJSON(context.Background(), &Request{URL: server.URL}, &out)
+	if !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+		t.Fatalf("err = %v, want %s", err, errors.ErrCodeInvalidResponse)
+	}
+	if got := err.(*errors.Error).PlatformDetails["response_body"]; got != `{"answer":` {
+		t.Errorf("response_body = %v", got)
+	}
+}
+
+func TestDoJSONTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	rec := &hookRecorder{}
+	c := &APIClient{Hooks: rec.hooks()}
+	meta, err := c.DoJSON(context.Background(), &Request{URL: url}, nil)
+	if !errors.IsSDKError(err, errors.ErrCodePlatformError) {
+		t.Errorf("closed server: err = %v, want %s", err, errors.ErrCodePlatformError)
+	}
+	if meta != nil {
+		t.Errorf("meta = %+v, want nil", meta)
+	}
+	rec.assertPaired(t)
+	if rec.metas[0] != nil || rec.errs[0] != err {
+		t.Errorf("AfterResponse got meta=%v err=%v", rec.metas[0], rec.errs[0])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.DoJSON(ctx, &Request{URL: url}, nil)
+	if !errors.IsSDKError(err, errors.ErrCodeCancelled) {
+		t.Errorf("cancelled: err = %v, want %s", err, errors.ErrCodeCancelled)
+	}
+}
+
+func TestRequestIDHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Client-Request-Id", "client-1")
+		w.Header().Set("X-Tt-Logid", "log-1")
+		w.Header().Set("X-Custom-Trace", "custom-1")
+	}))
+	defer server.Close()
+
+	meta, err := (&APIClient{}).DoJSON(context.Background(), &Request{URL: server.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.RequestID != "client-1" {
+		t.Errorf("default headers: RequestID = %q, want the first non-empty header", meta.RequestID)
+	}
+
+	meta, err = (&APIClient{RequestIDHeaders: []string{"X-Custom-Trace"}}).DoJSON(context.Background(), &Request{URL: server.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.RequestID != "custom-1" {
+		t.Errorf("custom headers: RequestID = %q", meta.RequestID)
+	}
+}
+
+func TestDoSSE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("X-Request-Id", "sse-1")
+		fmt.Fprint(w, "data: one\n\ndata: two\n\ndata: [DONE]\n\ndata: ignored\n\n")
+	}))
+	defer server.Close()
+
+	rec := &hookRecorder{}
+	var got []string
+	meta, err := (&APIClient{Hooks: rec.hooks()}).DoSSE(context.Background(), &Request{URL: server.URL}, func(event *SSEEvent) error {
+		got = append(got, event.Data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DoSSE: %v", err)
+	}
+	if strings.Join(got, ",") != "one,two" {
+		t.Errorf("events = %v, want stop at [DONE]", got)
+	}
+	if meta.RequestID != "sse-1" {
+		t.Errorf("RequestID = %q", meta.RequestID)
+	}
+	rec.assertPaired(t)
+
+	stop := errors.New(errors.ErrCodeInvalidResponse, "stop")
+	_, err = (&APIClient{}).DoSSE(context.Background(), &Request{URL: server.URL}, func(*SSEEvent) error { return stop })
+	if err != stop {
+		t.Errorf("err = %v, want the callback error", err)
+	}
+}
+
+func TestDoSSETruncated(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "cut after events", body: "data: one\n\ndata: two\n\n", want: []string{"one", "two"}},
+		{name: "cut inside an event", body: "data: one\n\ndata: tw", want: []string{"one"}},
+		{name: "empty body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "sse-cut")
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			var got []string
+			_, err := (&APIClient{Platform: "test"}).DoSSE(context.Background(), &Request{URL: server.URL}, func(event *SSEEvent) error {
+				got = append(got, event.Data)
+				return nil
+			})
+			if !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) || !strings.Contains(err.Error(), "stream ended before [DONE]") {
+				t.Fatalf("err = %v, want a truncated stream error", err)
+			}
+			var sdkErr *errors.Error
+			if errors.As(err, &sdkErr) && sdkErr.PlatformDetails["request_id"] != "sse-cut" {
+				t.Errorf("details = %v, want the request id", sdkErr.PlatformDetails)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
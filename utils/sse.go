@@ -0,0 +1,168 @@
+// utils/sse.go
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSEEvent 是按 WHATWG HTML 规范 (event-stream 解析) 解码得到的一个 Server-Sent Events 事件。
+type SSEEvent struct {
+	Type  string        // event 字段，未设置时为 "message"
+	Data  string        // data 字段，多个 data 行以 "\n" 连接
+	ID    string        // 当前的 last event ID，会在事件之间保留
+	Retry time.Duration // 最近一次 retry 字段指定的重连时间，未设置时为 0
+}
+
+// SSEDecoder 从 io.Reader 中逐个解码 Server-Sent Events 事件。
+//
+// 它遵循 WHATWG 规范：支持 CRLF/LF/CR 三种换行、忽略注释行 (以 ":" 开头)、
+// 解析 event/data/id/retry 字段、拼接多行 data，并丢弃流结束时未以空行终止的事件。
+// 与 bufio.Scanner 不同，单行或单个事件的大小默认不受限制。
+type SSEDecoder struct {
+	r            *bufio.Reader
+	line         bytes.Buffer
+	started      bool
+	skipLF       bool // 上一行以 CR 结尾，紧随其后的 LF 属于同一个换行符
+	lastEventID  string
+	retry        time.Duration
+	maxEventSize int
+}
+
+// NewSSEDecoder 创建一个新的 SSEDecoder。
+func NewSSEDecoder(r io.Reader) *SSEDecoder {
+	return &SSEDecoder{r: bufio.NewReader(r)}
+}
+
+// SetMaxEventSize 限制单个事件 data 的最大字节数，用于防止异常的服务端耗尽内存。
+// n 小于等于 0 表示不限制 (默认)。
+func (d *SSEDecoder) SetMaxEventSize(n int) {
+	d.maxEventSize = n
+}
+
+// Next 返回下一个事件。流正常结束时返回 io.EOF。
+func (d *SSEDecoder) Next() (*SSEEvent, error) {
+	var (
+		eventType string
+		data      strings.Builder
+		hasData   bool
+	)
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			// 流结束时尚未以空行终止的事件按规范丢弃。
+			return nil, err
+		}
+
+		if len(line) == 0 {
+			// 空行：分派事件。data 缓冲为空时只重置状态，不分派。
+			if !hasData {
+				eventType = ""
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+			return &SSEEvent{
+				Type:  eventType,
+				Data:  data.String(),
+				ID:    d.lastEventID,
+				Retry: d.retry,
+			}, nil
+		}
+
+		if line[0] == ':' {
+			continue // 注释行
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], line[i+1:]
+			value = strings.TrimPrefix(value, " ")
+		}
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+			if d.maxEventSize > 0 && data.Len() > d.maxEventSize {
+				return nil, fmt.Errorf("sse: event data exceeds %d bytes", d.maxEventSize)
+			}
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				d.lastEventID = value
+			}
+		case "retry":
+			if isASCIIDigits(value) {
+				if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+					d.retry = time.Duration(ms) * time.Millisecond
+				}
+			}
+		default:
+			// 未知字段按规范忽略。
+		}
+	}
+}
+
+// readLine 读取一行 (不含换行符)，支持 CRLF、LF 与单独的 CR。
+// 未以换行符结尾的最后一行不构成完整的行，返回 io.EOF。
+func (d *SSEDecoder) readLine() (string, error) {
+	d.line.Reset()
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if d.skipLF {
+			d.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+		switch b {
+		case '\n':
+			return d.finishLine(), nil
+		case '\r':
+			// 不预读下一个字节，避免在实时流中阻塞等待。
+			d.skipLF = true
+			return d.finishLine(), nil
+		default:
+			d.line.WriteByte(b)
+			if d.maxEventSize > 0 && d.line.Len() > d.maxEventSize {
+				return "", fmt.Errorf("sse: line exceeds %d bytes", d.maxEventSize)
+			}
+		}
+	}
+}
+
+// finishLine 返回当前行内容，并去掉流开头的 UTF-8 BOM。
+func (d *SSEDecoder) finishLine() string {
+	line := d.line.String()
+	if !d.started {
+		d.started = true
+		line = strings.TrimPrefix(line, "\uFEFF")
+	}
+	return line
+}
+
+func isASCIIDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
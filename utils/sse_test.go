@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeAll 解码 input 中的所有事件。
+func decodeAll(t testing.TB, input string) []SSEEvent {
+	t.Helper()
+	d := NewSSEDecoder(strings.NewReader(input))
+	var events []SSEEvent
+	for {
+		event, err := d.Next()
+		if err == io.EOF {
+			return events
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		events = append(events, *event)
+	}
+}
+
+var sseLineBreak = regexp.MustCompile("\r\n|\r|\n")
+
+// referenceParse 是按 WHATWG 规范逐字实现的参考解析：先整体切分行，再逐行处理字段。
+func referenceParse(input string) []SSEEvent {
+	input = strings.TrimPrefix(input, "\uFEFF")
+	lines := sseLineBreak.Split(input, -1)
+	lines = lines[:len(lines)-1] // 最后一段没有换行符结尾，不构成完整的行
+
+	var (
+		events      []SSEEvent
+		eventType   string
+		data        []string
+		lastEventID string
+		retry       time.Duration
+	)
+	for _, line := range lines {
+		if line == "" {
+			if data != nil {
+				typ := eventType
+				if typ == "" {
+					typ = "message"
+				}
+				events = append(events, SSEEvent{Type: typ, Data: strings.Join(data, "\n"), ID: lastEventID, Retry: retry})
+			}
+			eventType, data = "", nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, found := strings.Cut(line, ":")
+		if found {
+			value = strings.TrimPrefix(value, " ")
+		}
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				lastEventID = value
+			}
+		case "retry":
+			if value != "" && strings.Trim(value, "0123456789") == "" {
+				if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+					retry = time.Duration(ms) * time.Millisecond
+				}
+			}
+		}
+	}
+	return events
+}
+
+func TestSSEDecoder(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []SSEEvent
+	}{
+		{
+			name:  "LF",
+			input: "data: a\n\ndata: b\n\n",
+			want:  []SSEEvent{{Type: "message", Data: "a"}, {Type: "message", Data: "b"}},
+		},
+		{
+			name:  "CRLF",
+			input: "data: a\r\n\r\ndata: b\r\n\r\n",
+			want:  []SSEEvent{{Type: "message", Data: "a"}, {Type: "message", Data: "b"}},
+		},
+		{
+			name:  "CR",
+			input: "data: a\r\rdata: b\r\r",
+			want:  []SSEEvent{{Type: "message", Data: "a"}, {Type: "message", Data: "b"}},
+		},
+		{
+			name:  "mixed line breaks",
+			input: "event: x\rdata: a\r\ndata: b\n\r\n",
+			want:  []SSEEvent{{Type: "x", Data: "a\nb"}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: line1\ndata:line2\ndata\ndata:  indented\n\n",
+			want:  []SSEEvent{{Type: "message", Data: "line1\nline2\n\n indented"}},
+		},
+		{
+			name:  "event type resets after dispatch",
+			input: "event: delta\ndata: 1\n\ndata: 2\n\n",
+			want:  []SSEEvent{{Type: "delta", Data: "1"}, {Type: "message", Data: "2"}},
+		},
+		{
+			name:  "id persists and ignores NUL",
+			input: "id: 1\ndata: a\n\ndata: b\n\nid: 2\x00\ndata: c\n\nid\ndata: d\n\n",
+			want: []SSEEvent{
+				{Type: "message", Data: "a", ID: "1"},
+				{Type: "message", Data: "b", ID: "1"},
+				{Type: "message", Data: "c", ID: "1"},
+				{Type: "message", Data: "d", ID: ""},
+			},
+		},
+		{
+			name:  "retry",
+			input: "retry: 1500\ndata: a\n\nretry: 1.5\ndata: b\n\nretry: x\ndata: c\n\n",
+			want: []SSEEvent{
+				{Type: "message", Data: "a", Retry: 1500 * time.Millisecond},
+				{Type: "message", Data: "b", Retry: 1500 * time.Millisecond},
+				{Type: "message", Data: "c", Retry: 1500 * time.Millisecond},
+			},
+		},
+		{
+			name:  "comments and unknown fields",
+			input: ": keep-alive\n:\nfoo: bar\ndata: a\n: inline comment\n\n",
+			want:  []SSEEvent{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "event without data is not dispatched",
+			input: "event: ping\n\ndata: a\n\n",
+			want:  []SSEEvent{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "leading BOM",
+			input: "\uFEFFdata: a\n\n",
+			want:  []SSEEvent{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "BOM only stripped at stream start",
+			input: "data: a\n\n\uFEFFdata: b\n\n",
+			want:  []SSEEvent{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "unterminated event is discarded",
+			input: "data: a\n\ndata: b\n",
+			want:  []SSEEvent{{Type: "message", Data: "a"}},
+		},
+		{
+			name:  "empty stream",
+			input: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeAll(t, tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v\nwant %#v", got, tt.want)
+			}
+			if ref := referenceParse(tt.input); !reflect.DeepEqual(ref, tt.want) {
+				t.Errorf("reference parse disagrees with the test case: %#v", ref)
+			}
+		})
+	}
+}
+
+func TestSSEDecoderLargeEvent(t *testing.T) {
+	// bufio.Scanner 默认限制单行 64KB，解码器不应受此限制。
+	big := strings.Repeat("x", 256*1024)
+	got := decodeAll(t, "data: "+big+"\ndata: "+big+"\n\ndata: small\n\n")
+	if len(got) != 2 || got[0].Data != big+"\n"+big || got[1].Data != "small" {
+		t.Fatalf("got %d events, first data length %d", len(got), len(got[0].Data))
+	}
+}
+
+func TestSSEDecoderMaxEventSize(t *testing.T) {
+	d := NewSSEDecoder(strings.NewReader("data: " + strings.Repeat("x", 100) + "\n\n"))
+	d.SetMaxEventSize(64)
+	if _, err := d.Next(); err == nil || err == io.EOF {
+		t.Fatalf("err = %v, want size limit error", err)
+	}
+
+	d = NewSSEDecoder(strings.NewReader("data: 0123456789\ndata: 0123456789\n\n"))
+	d.SetMaxEventSize(15)
+	if _, err := d.Next(); err == nil || err == io.EOF {
+		t.Fatalf("err = %v, want size limit error across data lines", err)
+	}
+}
+
+// oneByteReader 每次只返回一个字节，模拟网络上零散到达的数据。
+type oneByteReader struct{ s string }
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if r.s == "" {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	p[0] = r.s[0]
+	r.s = r.s[1:]
+	return 1, nil
+}
+
+func TestSSEDecoderSplitReads(t *testing.T) {
+	input := "id: 7\r\nevent: delta\r\ndata: a\r\n\r\ndata: b\r\rdata: c\n\n"
+	d := NewSSEDecoder(&oneByteReader{s: input})
+	var got []SSEEvent
+	for {
+		event, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, *event)
+	}
+	if want := referenceParse(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v\nwant %#v", got, want)
+	}
+}
+
+func FuzzSSEDecoder(f *testing.F) {
+	for _, seed := range []string{
+		"data: a\n\n",
+		"data: a\r\n\r\n",
+		"data: a\r\r",
+		"\uFEFFevent: x\ndata: 1\ndata: 2\nid: 3\nretry: 10\n\n",
+		": comment\ndata\n\n",
+		"id: a\x00b\ndata: c\n\n",
+		"data: incomplete",
+		"\r\n\r\r\n\n",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		got := decodeAll(t, input)
+		want := referenceParse(input)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("input %q\ngot  %#v\nwant %#v", input, got, want)
+		}
+	})
+}
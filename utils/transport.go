@@ -0,0 +1,153 @@
+// utils/transport.go
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+// TransportConfig 描述了构建 HTTP 传输层时可调整的配置。
+// 零值字段表示沿用基础 Transport 的设置。
+type TransportConfig struct {
+	// Proxy 指定代理选择函数，例如 http.ProxyURL(u)。
+	// 为 nil 时沿用基础 Transport 的设置 (默认为 http.ProxyFromEnvironment)。
+	Proxy func(*http.Request) (*url.URL, error)
+	// TLSConfig 指定 TLS 配置，例如自定义根证书或客户端证书。
+	TLSConfig *tls.Config
+
+	MaxIdleConns        int           // 所有主机的最大空闲连接数
+	MaxIdleConnsPerHost int           // 每个主机的最大空闲连接数
+	MaxConnsPerHost     int           // 每个主机的最大连接数 (包含活跃连接)
+	IdleConnTimeout     time.Duration // 空闲连接的保持时间
+}
+
+// IsZero 判断是否没有设置任何配置项。
+func (c TransportConfig) IsZero() bool {
+	return c.Proxy == nil && c.TLSConfig == nil &&
+		c.MaxIdleConns == 0 && c.MaxIdleConnsPerHost == 0 && c.MaxConnsPerHost == 0 &&
+		c.IdleConnTimeout == 0
+}
+
+// NewTransport 在 base 的基础上应用 cfg，返回新的 http.RoundTripper。
+// base 为 nil 时使用 http.DefaultTransport。
+// 只有 *http.Transport 才能应用 cfg。cfg 不为空而 base 是其他类型的 RoundTripper
+// (例如测试中录制/回放的 Transport) 时返回 ErrCodeConfiguration 错误，避免代理或 TLS 设置被静默忽略。
+func NewTransport(base http.RoundTripper, cfg TransportConfig) (http.RoundTripper, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if cfg.IsZero() {
+		return base, nil
+	}
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("proxy, TLS and connection pool settings require an *http.Transport, got %T", base))
+	}
+
+	t = t.Clone()
+	if cfg.Proxy != nil {
+		t.Proxy = cfg.Proxy
+	}
+	if cfg.TLSConfig != nil {
+		t.TLSClientConfig = cfg.TLSConfig.Clone()
+	}
+	if cfg.MaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+	if cfg.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	return t, nil
+}
+
+// NewHTTPClient 使用给定的超时时间与传输层配置创建 *http.Client，错误与 NewTransport 相同。
+func NewHTTPClient(timeout time.Duration, base http.RoundTripper, cfg TransportConfig) (*http.Client, error) {
+	transport, err := NewTransport(base, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}, nil
+}
+
+// ApplyTransportConfig 将 cfg 应用到调用方注入的 HTTPClient 上。cfg 为空时原样返回 client；
+// client 是 Transport 为 nil 或 *http.Transport 的 *http.Client 时返回应用了 cfg 的副本，
+// 其他类型的客户端无法应用 cfg，返回 ErrCodeConfiguration 错误。
+func ApplyTransportConfig(client HTTPClient, cfg TransportConfig) (HTTPClient, error) {
+	if cfg.IsZero() {
+		return client, nil
+	}
+	hc, ok := client.(*http.Client)
+	if !ok {
+		return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("proxy, TLS and connection pool settings cannot be applied to a custom HTTP client of type %T", client))
+	}
+	transport, err := NewTransport(hc.Transport, cfg)
+	if err != nil {
+		return nil, err
+	}
+	clone := *hc
+	clone.Transport = transport
+	return &clone, nil
+}
+
+// TLSFiles 描述了从文件加载的 TLS 配置，零值表示使用系统默认设置。
+type TLSFiles struct {
+	CAFile             string // PEM 格式的根证书文件，为空时使用系统根证书
+	CertFile           string // PEM 格式的客户端证书文件，需与 KeyFile 同时设置
+	KeyFile            string // PEM 格式的客户端私钥文件
+	ServerName         string // 覆盖用于校验服务端证书的主机名
+	InsecureSkipVerify bool   // 跳过服务端证书校验，仅用于测试环境
+}
+
+// IsZero 判断是否没有设置任何配置项。
+func (f TLSFiles) IsZero() bool {
+	return f == TLSFiles{}
+}
+
+// Load 读取证书文件并构建 *tls.Config，f 为空时返回 nil。
+func (f TLSFiles) Load() (*tls.Config, error) {
+	if f.IsZero() {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		ServerName:         f.ServerName,
+		InsecureSkipVerify: f.InsecureSkipVerify,
+	}
+	if f.CAFile != "" {
+		pem, err := os.ReadFile(f.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeConfiguration, fmt.Sprintf("failed to read TLS CA file %s", f.CAFile))
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("TLS CA file %s contains no PEM certificates", f.CAFile))
+		}
+		cfg.RootCAs = pool
+	}
+	if (f.CertFile == "") != (f.KeyFile == "") {
+		return nil, errors.New(errors.ErrCodeConfiguration, "TLS cert file and key file must be set together")
+	}
+	if f.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeConfiguration, "failed to load TLS client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestNewTransportAppliesConfig(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.internal:3128")
+	base := &http.Transport{}
+	rt, err := NewTransport(base, TransportConfig{
+		Proxy:               http.ProxyURL(proxyURL),
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 5,
+		MaxConnsPerHost:     7,
+		IdleConnTimeout:     time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+	tr, ok := rt.(*http.Transport)
+	if !ok || tr == base {
+		t.Fatalf("NewTransport returned %T (same as base: %v), want a clone", rt, tr == base)
+	}
+	if tr.MaxIdleConns != 10 || tr.MaxIdleConnsPerHost != 5 || tr.MaxConnsPerHost != 7 || tr.IdleConnTimeout != time.Minute {
+		t.Errorf("pool settings not applied: %+v", tr)
+	}
+	got, _ := tr.Proxy(httptest.NewRequest(http.MethodGet, "https://example.com", nil))
+	if got == nil || got.String() != proxyURL.String() {
+		t.Errorf("proxy = %v, want %v", got, proxyURL)
+	}
+	if base.MaxIdleConns != 0 {
+		t.Error("base transport was modified")
+	}
+}
+
+func TestNewTransportRejectsCustomRoundTripper(t *testing.T) {
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+
+	rt, err := NewTransport(custom, TransportConfig{})
+	if err != nil {
+		t.Fatalf("NewTransport with empty config: %v", err)
+	}
+	if _, ok := rt.(roundTripperFunc); !ok {
+		t.Errorf("empty config should return the base transport, got %T", rt)
+	}
+
+	proxyURL, _ := url.Parse("http://proxy.internal:3128")
+	_, err = NewTransport(custom, TransportConfig{Proxy: http.ProxyURL(proxyURL)})
+	if !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("err = %v, want %s", err, errors.ErrCodeConfiguration)
+	}
+}
+
+func TestApplyTransportConfig(t *testing.T) {
+	cfg := TransportConfig{MaxConnsPerHost: 3}
+
+	hc := &http.Client{Timeout: time.Second}
+	applied, err := ApplyTransportConfig(hc, cfg)
+	if err != nil {
+		t.Fatalf("ApplyTransportConfig: %v", err)
+	}
+	clone, ok := applied.(*http.Client)
+	if !ok || clone == hc {
+		t.Fatalf("want a copy of the client, got %T", applied)
+	}
+	if clone.Timeout != time.Second || clone.Transport.(*http.Transport).MaxConnsPerHost != 3 {
+		t.Errorf("client not configured: %+v", clone)
+	}
+	if hc.Transport != nil {
+		t.Error("original client was modified")
+	}
+
+	doer := doerFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+	if got, err := ApplyTransportConfig(doer, TransportConfig{}); err != nil || got == nil {
+		t.Errorf("empty config: got %v, %v", got, err)
+	}
+	if _, err := ApplyTransportConfig(doer, cfg); !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("custom client: err = %v, want %s", err, errors.ErrCodeConfiguration)
+	}
+	custom := &http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, nil })}
+	if _, err := ApplyTransportConfig(custom, cfg); !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+		t.Errorf("custom transport: err = %v, want %s", err, errors.ErrCodeConfiguration)
+	}
+}
+
+func TestTLSFilesLoad(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tlsConfig, err := TLSFiles{CAFile: caFile}.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	hc, err := NewHTTPClient(time.Second, nil, TransportConfig{TLSConfig: tlsConfig})
+	if err != nil {
+		t.Fatalf("NewHTTPClient: %v", err)
+	}
+	resp, err := hc.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with custom CA failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if cfg, err := (TLSFiles{}).Load(); cfg != nil || err != nil {
+		t.Errorf("empty TLSFiles: got %v, %v", cfg, err)
+	}
+
+	badFile := filepath.Join(dir, "bad.pem")
+	if err := os.WriteFile(badFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, files := range map[string]TLSFiles{
+		"missing ca":       {CAFile: filepath.Join(dir, "missing.pem")},
+		"no certificates":  {CAFile: badFile},
+		"cert without key": {CertFile: caFile},
+	} {
+		if _, err := files.Load(); !errors.IsSDKError(err, errors.ErrCodeConfiguration) {
+			t.Errorf("%s: err = %v, want %s", name, err, errors.ErrCodeConfiguration)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+// utils/websocket.go
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+// WebSocket 消息类型 (RFC 6455 opcode)。
+const (
+	WebSocketText   = 1
+	WebSocketBinary = 2
+
+	wsOpContinuation = 0
+	wsOpClose        = 8
+	wsOpPing         = 9
+	wsOpPong         = 10
+
+	wsAcceptGUID      = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsMaxMessageBytes = 64 << 20
+)
+
+// WebSocketConn 是一个最小化的 WebSocket 客户端连接 (RFC 6455)，供需要双向流的平台接口使用，
+// 例如流式语音识别。不支持扩展 (如 permessage-deflate)；Ping 会自动回复 Pong。
+// WriteMessage 可以与 ReadMessage 并发调用，但 ReadMessage 本身不能并发调用。
+type WebSocketConn struct {
+	rw       io.ReadWriteCloser
+	br       *bufio.Reader
+	wmu      sync.Mutex
+	stop     func() bool // 取消 ctx 关闭连接的回调
+	closeErr error
+	closed   bool
+}
+
+// DialWebSocket 建立 WebSocket 连接。req.URL 可以使用 ws/wss 或 http/https 协议，req.Body 被忽略。
+// 连接的生命周期由 ctx 控制：ctx 被取消时连接会被关闭。*http.Client 的 Timeout 不适用于 WebSocket 连接。
+func (c *APIClient) DialWebSocket(ctx context.Context, req *Request) (*WebSocketConn, *ResponseMeta, error) {
+	url := req.URL
+	switch {
+	case strings.HasPrefix(url, "wss://"):
+		url = "https://" + strings.TrimPrefix(url, "wss://")
+	case strings.HasPrefix(url, "ws://"):
+		url = "http://" + strings.TrimPrefix(url, "ws://")
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, fmt.Sprintf("%s: failed to create WebSocket request", c.platformName()))
+	}
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, errors.Wrap(err, errors.ErrCodeInternal, fmt.Sprintf("%s: failed to generate WebSocket key", c.platformName()))
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+	for k, v := range req.Header {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("Connection", "Upgrade")
+	httpReq.Header.Set("Upgrade", "websocket")
+	httpReq.Header.Set("Sec-WebSocket-Version", "13")
+	httpReq.Header.Set("Sec-WebSocket-Key", key)
+
+	if c.Hooks.BeforeRequest != nil {
+		c.Hooks.BeforeRequest(ctx, httpReq)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = DefaultHTTPClient
+	}
+	if hc, ok := httpClient.(*http.Client); ok && hc.Timeout > 0 {
+		// 设置了 Timeout 时升级后的连接不可写，且长连接不应受整体超时限制。
+		copied := *hc
+		copied.Timeout = 0
+		httpClient = &copied
+	}
+	start := time.Now()
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		sdkErr := c.wrapTransportError(ctx, err, "failed to open WebSocket connection", nil)
+		if c.Hooks.AfterResponse != nil {
+			c.Hooks.AfterResponse(ctx, httpReq, nil, sdkErr)
+		}
+		return nil, nil, sdkErr
+	}
+	meta := &ResponseMeta{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		RequestID:  c.requestID(httpResp.Header),
+		Latency:    time.Since(start),
+	}
+
+	var sdkErr *errors.Error
+	rw, writable := httpResp.Body.(io.ReadWriteCloser)
+	switch {
+	case httpResp.StatusCode != http.StatusSwitchingProtocols:
+		body, _ := io.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
+		if httpResp.StatusCode >= 200 && httpResp.StatusCode < 300 {
+			sdkErr = errors.New(errors.ErrCodePlatformError, fmt.Sprintf("%s: server did not upgrade to WebSocket (status %d)", c.platformName(), httpResp.StatusCode))
+		} else {
+			sdkErr = c.statusError(meta, body)
+		}
+	case !writable:
+		httpResp.Body.Close()
+		sdkErr = errors.New(errors.ErrCodeConfiguration, fmt.Sprintf("%s: HTTP client does not support WebSocket connections", c.platformName()))
+	case httpResp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key):
+		httpResp.Body.Close()
+		sdkErr = errors.New(errors.ErrCodePlatformError, fmt.Sprintf("%s: invalid Sec-WebSocket-Accept in handshake response", c.platformName()))
+	}
+	if c.Hooks.AfterResponse != nil {
+		var hookErr error
+		if sdkErr != nil {
+			hookErr = sdkErr
+		}
+		c.Hooks.AfterResponse(ctx, httpReq, meta, hookErr)
+	}
+	if sdkErr != nil {
+		return nil, meta, sdkErr
+	}
+
+	conn := &WebSocketConn{rw: rw, br: bufio.NewReader(rw)}
+	conn.stop = context.AfterFunc(ctx, func() { rw.Close() })
+	return conn, meta, nil
+}
+
+// websocketAccept 计算握手响应中期望的 Sec-WebSocket-Accept。
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// WriteMessage 发送一条完整的消息，messageType 为 WebSocketText 或 WebSocketBinary。
+func (c *WebSocketConn) WriteMessage(messageType int, data []byte) error {
+	return c.writeFrame(byte(messageType), data)
+}
+
+// ReadMessage 读取下一条完整的消息，自动拼接分片并处理控制帧。
+// 对端正常关闭 (状态码 1000) 时返回 io.EOF，其他关闭状态码以错误返回。
+func (c *WebSocketConn) ReadMessage() (messageType int, data []byte, err error) {
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			code, reason := 1005, ""
+			if len(payload) >= 2 {
+				code, reason = int(binary.BigEndian.Uint16(payload)), string(payload[2:])
+			}
+			c.closeWith(payload)
+			if code == 1000 || code == 1005 {
+				return 0, nil, io.EOF
+			}
+			return 0, nil, fmt.Errorf("websocket closed by peer: code %d, reason: %s", code, reason)
+		case wsOpContinuation:
+			if messageType == 0 {
+				return 0, nil, fmt.Errorf("websocket: unexpected continuation frame")
+			}
+		default:
+			if messageType != 0 {
+				return 0, nil, fmt.Errorf("websocket: expected continuation frame, got opcode %d", opcode)
+			}
+			messageType = int(opcode)
+		}
+		if len(data)+len(payload) > wsMaxMessageBytes {
+			return 0, nil, fmt.Errorf("websocket: message exceeds %d bytes", wsMaxMessageBytes)
+		}
+		data = append(data, payload...)
+		if fin {
+			return messageType, data, nil
+		}
+	}
+}
+
+// Close 发送正常关闭帧并关闭底层连接，可以重复调用。
+func (c *WebSocketConn) Close() error {
+	c.closeWith([]byte{0x03, 0xe8}) // 1000 Normal Closure
+	return c.closeErr
+}
+
+// closeWith 发送关闭帧 (尽力而为) 并关闭底层连接。
+func (c *WebSocketConn) closeWith(payload []byte) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.writeFrameLocked(wsOpClose, payload)
+	c.stop()
+	c.closeErr = c.rw.Close()
+}
+
+func (c *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return fmt.Errorf("websocket: connection closed")
+	}
+	return c.writeFrameLocked(opcode, payload)
+}
+
+// writeFrameLocked 以单个 FIN 帧发送 payload。客户端发送的帧必须使用掩码。
+func (c *WebSocketConn) writeFrameLocked(opcode byte, payload []byte) error {
+	header := make([]byte, 0, 14)
+	header = append(header, 0x80|opcode)
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, 0x80|byte(n))
+	case n <= 0xffff:
+		header = append(header, 0x80|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 0x80|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	header = append(header, mask[:]...)
+	frame := make([]byte, len(header)+len(payload))
+	copy(frame, header)
+	for i, b := range payload {
+		frame[len(header)+i] = b ^ mask[i%4]
+	}
+	_, err := c.rw.Write(frame)
+	return err
+}
+
+// readFrame 读取一帧，返回是否为最后一个分片、opcode 与 (去掉掩码后的) 负载。
+func (c *WebSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode = head[0]&0x80 != 0, head[0]&0x0f
+	masked := head[1]&0x80 != 0
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > wsMaxMessageBytes {
+		return false, 0, nil, fmt.Errorf("websocket: frame exceeds %d bytes", wsMaxMessageBytes)
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
@@ -0,0 +1,352 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+// wsTestFrame 是测试服务端收发的一帧。
+type wsTestFrame struct {
+	fin     bool
+	opcode  byte
+	payload []byte
+}
+
+// wsTestPeer 是测试用 WebSocket 服务端连接：读取带掩码的客户端帧，发送不带掩码的服务端帧。
+type wsTestPeer struct {
+	t  *testing.T
+	rw *bufio.ReadWriter
+}
+
+// read 读取一个客户端帧并检查其使用了掩码。
+func (p *wsTestPeer) read() (wsTestFrame, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(p.rw, head[:]); err != nil {
+		return wsTestFrame{}, err
+	}
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(p.rw, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(p.rw, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if head[1]&0x80 == 0 {
+		p.t.Error("client frame is not masked")
+	}
+	var mask [4]byte
+	io.ReadFull(p.rw, mask[:])
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(p.rw, payload); err != nil {
+		return wsTestFrame{}, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return wsTestFrame{fin: head[0]&0x80 != 0, opcode: head[0] & 0x0f, payload: payload}, nil
+}
+
+// write 发送一帧，按负载长度选择 7 位、16 位或 64 位长度编码。
+func (p *wsTestPeer) write(f wsTestFrame) {
+	b0 := f.opcode
+	if f.fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0}
+	switch n := len(f.payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	p.rw.Write(append(frame, f.payload...))
+	p.rw.Flush()
+}
+
+// readAll 读取客户端帧直到连接关闭。
+func (p *wsTestPeer) readAll() []wsTestFrame {
+	var frames []wsTestFrame
+	for {
+		f, err := p.read()
+		if err != nil {
+			return frames
+		}
+		frames = append(frames, f)
+	}
+}
+
+// newWebSocketServer 启动 WebSocket 测试服务并返回其 ws:// 地址。accept 为 nil 时返回正确的
+// Sec-WebSocket-Accept；serve 返回后连接被关闭。
+func newWebSocketServer(t *testing.T, accept func(key string) string, serve func(p *wsTestPeer)) string {
+	t.Helper()
+	if accept == nil {
+		accept = websocketAccept
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+			t.Errorf("handshake headers = %v", r.Header)
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + accept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		rw.Flush()
+		serve(&wsTestPeer{t: t, rw: rw})
+	}))
+	t.Cleanup(server.Close)
+	return "ws://" + strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestDialWebSocketHandshake(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{name: "unauthorized", want: errors.ErrCodeAuthentication, handler: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}},
+		{name: "not upgraded", want: errors.ErrCodePlatformError, handler: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+			api := &APIClient{Platform: "test"}
+			conn, meta, err := api.DialWebSocket(context.Background(), &Request{URL: "ws://" + strings.TrimPrefix(server.URL, "http://")})
+			if conn != nil || !errors.IsSDKError(err, tt.want) {
+				t.Errorf("conn = %v, err = %v, want %s", conn, err, tt.want)
+			}
+			if meta == nil {
+				t.Error("meta = nil, want the handshake response")
+			}
+		})
+	}
+
+	t.Run("bad accept", func(t *testing.T) {
+		url := newWebSocketServer(t, func(string) string { return "bm90IHRoZSByaWdodCBrZXk=" }, func(p *wsTestPeer) {})
+		api := &APIClient{Platform: "test"}
+		conn, _, err := api.DialWebSocket(context.Background(), &Request{URL: url})
+		if conn != nil || !errors.IsSDKError(err, errors.ErrCodePlatformError) || !strings.Contains(err.Error(), "Sec-WebSocket-Accept") {
+			t.Errorf("conn = %v, err = %v", conn, err)
+		}
+	})
+}
+
+func TestWebSocketReadMessage(t *testing.T) {
+	large := bytes.Repeat([]byte("x"), 70000) // 需要 64 位长度编码
+	medium := bytes.Repeat([]byte("y"), 300)  // 需要 16 位长度编码
+	tests := []struct {
+		name     string
+		frames   []wsTestFrame
+		raw      []byte // 在 frames 之后直接写入的原始字节
+		wantType int
+		wantData []byte
+		wantErr  string // 为 "EOF" 时期望 io.EOF
+		received []wsTestFrame
+	}{
+		{
+			name:     "single text frame",
+			frames:   []wsTestFrame{{fin: true, opcode: WebSocketText, payload: []byte("hello")}},
+			wantType: WebSocketText, wantData: []byte("hello"),
+		},
+		{
+			name: "fragmented binary message",
+			frames: []wsTestFrame{
+				{opcode: WebSocketBinary, payload: []byte("he")},
+				{opcode: wsOpContinuation, payload: []byte("ll")},
+				{fin: true, opcode: wsOpContinuation, payload: []byte("o")},
+			},
+			wantType: WebSocketBinary, wantData: []byte("hello"),
+		},
+		{
+			name: "ping between fragments",
+			frames: []wsTestFrame{
+				{opcode: WebSocketText, payload: []byte("a")},
+				{fin: true, opcode: wsOpPing, payload: []byte("p1")},
+				{fin: true, opcode: wsOpPong, payload: []byte("ignored")},
+				{fin: true, opcode: wsOpContinuation, payload: []byte("b")},
+			},
+			wantType: WebSocketText, wantData: []byte("ab"),
+			received: []wsTestFrame{{fin: true, opcode: wsOpPong, payload: []byte("p1")}},
+		},
+		{
+			name:     "16-bit length",
+			frames:   []wsTestFrame{{fin: true, opcode: WebSocketBinary, payload: medium}},
+			wantType: WebSocketBinary, wantData: medium,
+		},
+		{
+			name:     "64-bit length",
+			frames:   []wsTestFrame{{fin: true, opcode: WebSocketBinary, payload: large}},
+			wantType: WebSocketBinary, wantData: large,
+		},
+		{
+			name:    "oversize frame",
+			raw:     binary.BigEndian.AppendUint64([]byte{0x80 | WebSocketBinary, 127}, wsMaxMessageBytes+1),
+			wantErr: "frame exceeds",
+		},
+		{
+			name:    "unexpected continuation",
+			frames:  []wsTestFrame{{fin: true, opcode: wsOpContinuation, payload: []byte("x")}},
+			wantErr: "unexpected continuation frame",
+		},
+		{
+			name: "new message inside a fragmented one",
+			frames: []wsTestFrame{
+				{opcode: WebSocketText, payload: []byte("a")},
+				{fin: true, opcode: WebSocketText, payload: []byte("b")},
+			},
+			wantErr: "expected continuation frame",
+		},
+		{
+			name:     "normal closure",
+			frames:   []wsTestFrame{{fin: true, opcode: wsOpClose, payload: []byte{0x03, 0xe8}}},
+			wantErr:  "EOF",
+			received: []wsTestFrame{{fin: true, opcode: wsOpClose, payload: []byte{0x03, 0xe8}}},
+		},
+		{
+			name:     "close without status",
+			frames:   []wsTestFrame{{fin: true, opcode: wsOpClose}},
+			wantErr:  "EOF",
+			received: []wsTestFrame{{fin: true, opcode: wsOpClose, payload: []byte{}}},
+		},
+		{
+			name:     "abnormal closure",
+			frames:   []wsTestFrame{{fin: true, opcode: wsOpClose, payload: append([]byte{0x03, 0xf3}, "overloaded"...)}},
+			wantErr:  "code 1011, reason: overloaded",
+			received: []wsTestFrame{{fin: true, opcode: wsOpClose, payload: append([]byte{0x03, 0xf3}, "overloaded"...)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan []wsTestFrame, 1)
+			url := newWebSocketServer(t, nil, func(p *wsTestPeer) {
+				for _, f := range tt.frames {
+					p.write(f)
+				}
+				if len(tt.raw) > 0 {
+					p.rw.Write(tt.raw)
+					p.rw.Flush()
+				}
+				received <- p.readAll()
+			})
+			api := &APIClient{Platform: "test"}
+			conn, _, err := api.DialWebSocket(context.Background(), &Request{URL: url})
+			if err != nil {
+				t.Fatalf("DialWebSocket: %v", err)
+			}
+			typ, data, err := conn.ReadMessage()
+			switch {
+			case tt.wantErr == "EOF":
+				if err != io.EOF {
+					t.Errorf("err = %v, want io.EOF", err)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("ReadMessage: %v", err)
+			case typ != tt.wantType || !bytes.Equal(data, tt.wantData):
+				t.Errorf("message = %d %q, want %d %q", typ, data, tt.wantType, tt.wantData)
+			}
+			conn.Close()
+
+			// 客户端关闭时发送 1000；对端先关闭时客户端回显其关闭帧，之后不再发送。
+			want := tt.received
+			if tt.wantErr != "EOF" && !strings.HasPrefix(tt.wantErr, "code") {
+				want = append(want, wsTestFrame{fin: true, opcode: wsOpClose, payload: []byte{0x03, 0xe8}})
+			}
+			got := <-received
+			if len(got) != len(want) {
+				t.Fatalf("client frames = %+v, want %+v", got, want)
+			}
+			for i := range got {
+				if got[i].fin != want[i].fin || got[i].opcode != want[i].opcode || !bytes.Equal(got[i].payload, want[i].payload) {
+					t.Errorf("client frame %d = %+v, want %+v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWebSocketWriteMessage(t *testing.T) {
+	payloads := [][]byte{
+		[]byte("short"),
+		bytes.Repeat([]byte("m"), 300),
+		bytes.Repeat([]byte("l"), 70000),
+	}
+	received := make(chan []wsTestFrame, 1)
+	url := newWebSocketServer(t, nil, func(p *wsTestPeer) {
+		received <- p.readAll()
+	})
+	api := &APIClient{Platform: "test"}
+	conn, _, err := api.DialWebSocket(context.Background(), &Request{URL: url})
+	if err != nil {
+		t.Fatalf("DialWebSocket: %v", err)
+	}
+	for _, payload := range payloads {
+		if err := conn.WriteMessage(WebSocketBinary, payload); err != nil {
+			t.Fatalf("WriteMessage(%d bytes): %v", len(payload), err)
+		}
+	}
+	if err := conn.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if err := conn.WriteMessage(WebSocketText, []byte("late")); err == nil {
+		t.Error("WriteMessage after Close succeeded")
+	}
+
+	got := <-received
+	if len(got) != len(payloads)+1 {
+		t.Fatalf("frames = %d, want %d", len(got), len(payloads)+1)
+	}
+	for i, payload := range payloads {
+		if !got[i].fin || got[i].opcode != WebSocketBinary || !bytes.Equal(got[i].payload, payload) {
+			t.Errorf("frame %d: fin=%v opcode=%d len=%d, want a %d byte binary frame", i, got[i].fin, got[i].opcode, len(got[i].payload), len(payload))
+		}
+	}
+	if last := got[len(payloads)]; last.opcode != wsOpClose || !bytes.Equal(last.payload, []byte{0x03, 0xe8}) {
+		t.Errorf("close frame = %+v, want status 1000", last)
+	}
+}
+
+func TestWebSocketContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	url := newWebSocketServer(t, nil, func(p *wsTestPeer) { <-release })
+	defer close(release)
+	ctx, cancel := context.WithCancel(context.Background())
+	api := &APIClient{Platform: "test"}
+	conn, _, err := api.DialWebSocket(ctx, &Request{URL: url})
+	if err != nil {
+		t.Fatalf("DialWebSocket: %v", err)
+	}
+	defer conn.Close()
+	cancel()
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Error("ReadMessage after cancel succeeded")
+	}
+}
@@ -0,0 +1,91 @@
+// vector/search.go
+package vector
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+// Metric 是 TopK 使用的相似度度量。
+type Metric int
+
+const (
+	MetricCosine    Metric = iota // 余弦相似度，越大越相似
+	MetricDot                     // 点积，越大越相似；向量已归一化时与余弦相似度等价且更快
+	MetricEuclidean               // 欧氏距离，越小越相似
+)
+
+// Match 是 TopK 的一个结果。
+type Match struct {
+	Index int     // 候选向量在输入切片中的位置
+	Score float32 // 相似度；MetricEuclidean 时为距离
+}
+
+// TopK 在 candidates 中找出与 query 最相似的 k 个向量，按相似度从高到低 (欧氏距离从小到大) 返回。
+// 分数相同时序号较小的排在前面。k 大于候选数量时返回全部候选。
+func TopK(query []float32, candidates [][]float32, k int, metric Metric) ([]Match, error) {
+	if k <= 0 {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("vector: k must be positive, got %d", k))
+	}
+	var score func(a, b []float32) (float32, error)
+	switch metric {
+	case MetricCosine:
+		score = Cosine
+	case MetricDot:
+		score = Dot
+	case MetricEuclidean:
+		score = Euclidean
+	default:
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("vector: unknown metric %d", metric))
+	}
+
+	h := &matchHeap{lowerIsBetter: metric == MetricEuclidean}
+	for i, c := range candidates {
+		s, err := score(query, c)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInvalidRequest, fmt.Sprintf("vector: candidate %d", i))
+		}
+		m := Match{Index: i, Score: s}
+		if h.Len() < k {
+			heap.Push(h, m)
+		} else if h.better(m, h.matches[0]) {
+			h.matches[0] = m
+			heap.Fix(h, 0)
+		}
+	}
+
+	out := h.matches
+	sort.Slice(out, func(i, j int) bool { return h.better(out[i], out[j]) })
+	return out, nil
+}
+
+// matchHeap 是以"最差"结果为堆顶的堆，用于保留最好的 k 个结果。
+type matchHeap struct {
+	matches       []Match
+	lowerIsBetter bool
+}
+
+// better 报告 a 是否比 b 更相似。
+func (h *matchHeap) better(a, b Match) bool {
+	if a.Score != b.Score {
+		if h.lowerIsBetter {
+			return a.Score < b.Score
+		}
+		return a.Score > b.Score
+	}
+	return a.Index < b.Index
+}
+
+func (h *matchHeap) Len() int           { return len(h.matches) }
+func (h *matchHeap) Less(i, j int) bool { return h.better(h.matches[j], h.matches[i]) }
+func (h *matchHeap) Swap(i, j int)      { h.matches[i], h.matches[j] = h.matches[j], h.matches[i] }
+func (h *matchHeap) Push(x interface{}) { h.matches = append(h.matches, x.(Match)) }
+func (h *matchHeap) Pop() interface{} {
+	old := h.matches
+	m := old[len(old)-1]
+	h.matches = old[:len(old)-1]
+	return m
+}
@@ -0,0 +1,178 @@
+// vector/vector.go
+package vector
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+// Norm 返回 v 的 L2 范数。
+func Norm(v []float32) float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return float32(math.Sqrt(sum))
+}
+
+// Normalize 返回 v 按 L2 范数归一化后的副本。零向量原样返回副本。
+func Normalize(v []float32) []float32 {
+	out := make([]float32, len(v))
+	copy(out, v)
+	NormalizeInPlace(out)
+	return out
+}
+
+// NormalizeInPlace 将 v 就地按 L2 范数归一化。零向量保持不变。
+func NormalizeInPlace(v []float32) {
+	norm := float64(Norm(v))
+	if norm == 0 {
+		return
+	}
+	for i, x := range v {
+		v[i] = float32(float64(x) / norm)
+	}
+}
+
+// Dot 返回 a 与 b 的点积。两者维度不同时返回错误。
+func Dot(a, b []float32) (float32, error) {
+	if err := checkDims(a, b); err != nil {
+		return 0, err
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return float32(sum), nil
+}
+
+// Cosine 返回 a 与 b 的余弦相似度，取值 [-1, 1]。任一向量为零向量时返回 0。
+func Cosine(a, b []float32) (float32, error) {
+	if err := checkDims(a, b); err != nil {
+		return 0, err
+	}
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0, nil
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb))), nil
+}
+
+// Euclidean 返回 a 与 b 的欧氏距离。
+func Euclidean(a, b []float32) (float32, error) {
+	if err := checkDims(a, b); err != nil {
+		return 0, err
+	}
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return float32(math.Sqrt(sum)), nil
+}
+
+// Truncate 保留 v 的前 dim 维并重新归一化，适用于以 Matryoshka 方式训练的模型
+// (前若干维本身即是有效的低维向量)。dim 不能大于 v 的维度。
+func Truncate(v []float32, dim int) ([]float32, error) {
+	if dim <= 0 || dim > len(v) {
+		return nil, errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("vector: cannot truncate %d dimensions to %d", len(v), dim))
+	}
+	return Normalize(v[:dim]), nil
+}
+
+// QuantizeInt8 将 v 对称量化为 int8，返回量化值与缩放系数，原值约等于 q[i] * scale。
+func QuantizeInt8(v []float32) (q []int8, scale float32) {
+	var maxAbs float64
+	for _, x := range v {
+		maxAbs = math.Max(maxAbs, math.Abs(float64(x)))
+	}
+	q = make([]int8, len(v))
+	if maxAbs == 0 {
+		return q, 0
+	}
+	s := maxAbs / 127
+	for i, x := range v {
+		q[i] = int8(math.Round(float64(x) / s))
+	}
+	return q, float32(s)
+}
+
+// DequantizeInt8 将 QuantizeInt8 的结果还原为 float32 向量。
+func DequantizeInt8(q []int8, scale float32) []float32 {
+	out := make([]float32, len(q))
+	for i, x := range q {
+		out[i] = float32(x) * scale
+	}
+	return out
+}
+
+// QuantizeBinary 将 v 的每一维按符号量化为 1 位 (大于 0 为 1)，按高位在前打包为字节，
+// 长度为 ceil(len(v)/8)。二值向量之间使用 Hamming 距离比较。
+func QuantizeBinary(v []float32) []byte {
+	out := make([]byte, (len(v)+7)/8)
+	for i, x := range v {
+		if x > 0 {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
+
+// Hamming 返回两个二值向量中不同位的数量。
+func Hamming(a, b []byte) (int, error) {
+	if len(a) != len(b) {
+		return 0, dimensionMismatch(len(a), len(b))
+	}
+	d := 0
+	for i := range a {
+		d += bits.OnesCount8(a[i] ^ b[i])
+	}
+	return d, nil
+}
+
+// DecodeBase64 解码 EncodingFormat 为 "base64" 时平台返回的向量，即小端序 float32 数组的 base64 编码。
+func DecodeBase64(s string) ([]float32, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInvalidResponse, "vector: invalid base64 embedding")
+	}
+	if len(data)%4 != 0 {
+		return nil, errors.New(errors.ErrCodeInvalidResponse, fmt.Sprintf("vector: base64 embedding has %d bytes, not a multiple of 4", len(data)))
+	}
+	out := make([]float32, len(data)/4)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return out, nil
+}
+
+// EncodeBase64 将向量编码为小端序 float32 数组的 base64 字符串，是 DecodeBase64 的逆操作。
+func EncodeBase64(v []float32) string {
+	data := make([]byte, len(v)*4)
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(x))
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func checkDims(a, b []float32) error {
+	if len(a) != len(b) {
+		return dimensionMismatch(len(a), len(b))
+	}
+	return nil
+}
+
+func dimensionMismatch(a, b int) error {
+	return errors.New(errors.ErrCodeInvalidRequest, fmt.Sprintf("vector: dimension mismatch (%d vs %d)", a, b))
+}
@@ -0,0 +1,214 @@
+package vector
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/hewenyu/modelbridge/errors"
+)
+
+// randomVectors 生成 n 个 dim 维的随机向量，固定种子使失败可复现。
+func randomVectors(seed int64, n, dim int) [][]float32 {
+	r := rand.New(rand.NewSource(seed))
+	out := make([][]float32, n)
+	for i := range out {
+		out[i] = make([]float32, dim)
+		for j := range out[i] {
+			out[i][j] = float32(r.NormFloat64())
+		}
+	}
+	return out
+}
+
+func approx(a, b, eps float64) bool { return math.Abs(a-b) <= eps }
+
+func TestNormalize(t *testing.T) {
+	for i, v := range randomVectors(1, 200, 17) {
+		orig := append([]float32(nil), v...)
+		n := Normalize(v)
+		if !approx(float64(Norm(n)), 1, 1e-5) {
+			t.Errorf("vector %d: ‖Normalize(v)‖ = %v", i, Norm(n))
+		}
+		for j := range v {
+			if v[j] != orig[j] {
+				t.Fatalf("vector %d: Normalize modified its input", i)
+			}
+		}
+		if c, _ := Cosine(v, n); !approx(float64(c), 1, 1e-5) {
+			t.Errorf("vector %d: direction changed, cosine = %v", i, c)
+		}
+	}
+	zero := make([]float32, 4)
+	if n := Normalize(zero); Norm(n) != 0 {
+		t.Errorf("Normalize(zero) = %v", n)
+	}
+}
+
+func TestCosineProperties(t *testing.T) {
+	vs := randomVectors(2, 100, 32)
+	for i := range vs {
+		a, b := vs[i], vs[(i+1)%len(vs)]
+		self, err := Cosine(a, a)
+		if err != nil || !approx(float64(self), 1, 1e-6) {
+			t.Errorf("Cosine(a, a) = %v, %v", self, err)
+		}
+		ab, _ := Cosine(a, b)
+		ba, _ := Cosine(b, a)
+		if ab != ba {
+			t.Errorf("Cosine not symmetric: %v != %v", ab, ba)
+		}
+		if ab < -1-1e-6 || ab > 1+1e-6 {
+			t.Errorf("Cosine out of range: %v", ab)
+		}
+		// 向量归一化后点积与余弦相似度一致。
+		dot, _ := Dot(Normalize(a), Normalize(b))
+		if !approx(float64(dot), float64(ab), 1e-5) {
+			t.Errorf("Dot of normalized vectors = %v, cosine = %v", dot, ab)
+		}
+	}
+	if c, _ := Cosine([]float32{0, 0}, []float32{1, 2}); c != 0 {
+		t.Errorf("Cosine with zero vector = %v", c)
+	}
+	for _, fn := range []func(a, b []float32) (float32, error){Dot, Cosine, Euclidean} {
+		if _, err := fn([]float32{1}, []float32{1, 2}); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+			t.Errorf("dimension mismatch: err = %v", err)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	v := randomVectors(3, 1, 64)[0]
+	got, err := Truncate(v, 16)
+	if err != nil || len(got) != 16 || !approx(float64(Norm(got)), 1, 1e-5) {
+		t.Errorf("Truncate = %d dims, norm %v, err %v", len(got), Norm(got), err)
+	}
+	for _, dim := range []int{0, 65} {
+		if _, err := Truncate(v, dim); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+			t.Errorf("Truncate(%d): err = %v", dim, err)
+		}
+	}
+}
+
+// encodeBase64 按平台 base64 格式 (小端 float32) 编码向量。
+func encodeBase64(v []float32) string {
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(x))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+func TestDecodeBase64(t *testing.T) {
+	for _, v := range append(randomVectors(4, 50, 9), []float32{}, []float32{float32(math.Inf(1)), float32(math.Copysign(0, -1)), math.SmallestNonzeroFloat32}) {
+		got, err := DecodeBase64(encodeBase64(v))
+		if err != nil {
+			t.Fatalf("DecodeBase64: %v", err)
+		}
+		if len(got) != len(v) {
+			t.Fatalf("got %d values, want %d", len(got), len(v))
+		}
+		for i := range v {
+			if math.Float32bits(got[i]) != math.Float32bits(v[i]) {
+				t.Errorf("value %d = %v, want %v", i, got[i], v[i])
+			}
+		}
+	}
+	for _, bad := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte{1, 2, 3})} {
+		if _, err := DecodeBase64(bad); !errors.IsSDKError(err, errors.ErrCodeInvalidResponse) {
+			t.Errorf("DecodeBase64(%q): err = %v", bad, err)
+		}
+	}
+}
+
+func TestQuantizeInt8(t *testing.T) {
+	for i, v := range randomVectors(5, 100, 48) {
+		q, scale := QuantizeInt8(v)
+		back := DequantizeInt8(q, scale)
+		for j := range v {
+			// 对称量化的舍入误差不超过半个量化步长。
+			if diff := math.Abs(float64(back[j]) - float64(v[j])); diff > float64(scale)/2+1e-6 {
+				t.Fatalf("vector %d dim %d: error %v exceeds scale/2 = %v", i, j, diff, scale/2)
+			}
+		}
+	}
+	q, scale := QuantizeInt8([]float32{0, 0})
+	if scale != 0 || q[0] != 0 || q[1] != 0 {
+		t.Errorf("zero vector: q = %v, scale = %v", q, scale)
+	}
+}
+
+func TestHammingMatchesQuantizeBinary(t *testing.T) {
+	for _, dim := range []int{1, 7, 8, 13, 64} {
+		vs := randomVectors(int64(dim), 20, dim)
+		for i := range vs {
+			a, b := vs[i], vs[(i+1)%len(vs)]
+			qa, qb := QuantizeBinary(a), QuantizeBinary(b)
+			if len(qa) != (dim+7)/8 {
+				t.Fatalf("dim %d: packed length %d", dim, len(qa))
+			}
+			want := 0
+			for j := range a {
+				if (a[j] > 0) != (b[j] > 0) {
+					want++
+				}
+			}
+			if got, err := Hamming(qa, qb); err != nil || got != want {
+				t.Errorf("dim %d: Hamming = %d, %v, want %d", dim, got, err, want)
+			}
+		}
+	}
+	if _, err := Hamming([]byte{0}, []byte{0, 0}); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("length mismatch: err = %v", err)
+	}
+}
+
+func TestTopKMatchesFullSort(t *testing.T) {
+	query := randomVectors(6, 1, 8)[0]
+	candidates := randomVectors(7, 60, 8)
+	// 重复的候选向量产生相同的分数，用于检查按序号打破平局。
+	candidates = append(candidates, candidates[3], candidates[10], candidates[3])
+
+	metrics := map[Metric]func(a, b []float32) (float32, error){MetricCosine: Cosine, MetricDot: Dot, MetricEuclidean: Euclidean}
+	for metric, score := range metrics {
+		all := make([]Match, len(candidates))
+		for i, c := range candidates {
+			s, _ := score(query, c)
+			all[i] = Match{Index: i, Score: s}
+		}
+		sort.Slice(all, func(i, j int) bool {
+			if all[i].Score != all[j].Score {
+				if metric == MetricEuclidean {
+					return all[i].Score < all[j].Score
+				}
+				return all[i].Score > all[j].Score
+			}
+			return all[i].Index < all[j].Index
+		})
+		for _, k := range []int{1, 5, 17, len(candidates), len(candidates) + 10} {
+			got, err := TopK(query, candidates, k, metric)
+			if err != nil {
+				t.Fatalf("metric %d k %d: %v", metric, k, err)
+			}
+			want := all[:min(k, len(all))]
+			if len(got) != len(want) {
+				t.Fatalf("metric %d k %d: got %d matches", metric, k, len(got))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("metric %d k %d: match %d = %+v, want %+v", metric, k, i, got[i], want[i])
+				}
+			}
+		}
+	}
+
+	if _, err := TopK(query, candidates, 0, MetricDot); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("k = 0: err = %v", err)
+	}
+	if _, err := TopK(query, [][]float32{{1}}, 1, MetricDot); !errors.IsSDKError(err, errors.ErrCodeInvalidRequest) {
+		t.Errorf("dimension mismatch: err = %v", err)
+	}
+}